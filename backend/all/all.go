@@ -2,7 +2,8 @@
 package all
 
 import (
-	_ "github.com/c2fo/vfs/v3/backend/gs" // register gs backend
-	_ "github.com/c2fo/vfs/v3/backend/os" // register os backend
-	_ "github.com/c2fo/vfs/v3/backend/s3" // register s3 backend
+	_ "github.com/c2fo/vfs/v3/backend/gs"      // register gs backend
+	_ "github.com/c2fo/vfs/v3/backend/os"      // register os backend
+	_ "github.com/c2fo/vfs/v3/backend/s3"      // register s3 backend
+	_ "github.com/c2fo/vfs/v3/backend/webhdfs" // register webhdfs backend
 )
@@ -0,0 +1,59 @@
+/*
+Package archive zip and tar VFS implementation.
+
+An archive FileSystem wraps an existing vfs.File (the "container") so that the members of a zip or tar archive can be
+used like any other vfs.Location and vfs.File. Because the container is itself a vfs.File, the archive may live on any
+backend: os, s3, gs, etc.
+
+Usage
+
+Archive filesystems are bound to a single container so they are not registered with backend. Create one directly:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend/archive"
+      "github.com/c2fo/vfs/v3/vfssimple"
+  )
+
+  func DoSomething() error {
+      container, err := vfssimple.NewFile("s3://mybucket/bundles/bundle.zip")
+      if err != nil {
+          return err
+      }
+
+      fs := archive.NewZipFileSystem(container)
+      defer fs.Close()
+
+      loc, err := fs.NewLocation("", "/data/")
+      if err != nil {
+          return err
+      }
+
+      names, err := loc.List() // members directly under data/ in the archive
+      ...
+  }
+
+Tar archives are supported with NewTarFileSystem. A container whose name ends in ".gz" or ".tgz" is treated as a
+gzip-compressed tar.
+
+Volume
+
+Archives have no volume. Paths are relative to the root of the archive, so the member data/a.csv has a URI of
+zip:///data/a.csv.
+
+Writing
+
+Members written with File.Write are staged in memory when the File is closed. Staged members, along with any deletes,
+are written to the container when FileSystem.Close() is called. The container is rewritten in full, preserving all
+existing members that were not overwritten or deleted. Closing a FileSystem with no staged changes leaves the container
+untouched.
+
+Reading
+
+Zip members are streamed directly from the container. Tar members are streamed from their offset within the container
+(or, for gzip-compressed tars, by decompressing from the start of the container).
+
+See Also
+
+See: https://golang.org/pkg/archive/zip/ and https://golang.org/pkg/archive/tar/
+*/
+package archive
@@ -0,0 +1,237 @@
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for a member of an archive.
+type File struct {
+	fileSystem  *FileSystem
+	name        string
+	reader      io.ReadCloser
+	offset      int64
+	writeBuffer *bytes.Buffer
+}
+
+// Info Functions
+
+// LastModified returns the modification time recorded for the member.
+func (f *File) LastModified() (*time.Time, error) {
+	e, err := f.entry()
+	if err != nil {
+		return nil, err
+	}
+	return &e.modTime, nil
+}
+
+// Name returns the base name of the member. IE: "file.txt" of "zip:///some/path/to/file.txt
+func (f *File) Name() string {
+	return path.Base(f.name)
+}
+
+// Path returns the full path of the member within the archive. IE: "/some/path/to/file.txt" of
+// "zip:///some/path/to/file.txt
+func (f *File) Path() string {
+	return f.name
+}
+
+// Exists returns whether the archive contains the member, including members staged but not yet written.
+func (f *File) Exists() (bool, error) {
+	e, err := f.fileSystem.lookup(f.name)
+	if err != nil {
+		return false, err
+	}
+	return e != nil, nil
+}
+
+// Size returns the uncompressed size of the member.
+func (f *File) Size() (uint64, error) {
+	e, err := f.entry()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(e.size), nil
+}
+
+// Location returns a vfs.Location for the archive directory containing the member.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		path:       memberDir(f.name),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the member's content to the target file.
+func (f *File) CopyToFile(target vfs.File) error {
+	if err := utils.TouchCopy(target, f); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := target.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	return f.Close()
+}
+
+// CopyToLocation copies the member to a file of the same name at location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.CopyToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// MoveToFile copies the member to the target file then deletes the member.
+func (f *File) MoveToFile(target vfs.File) error {
+	if err := f.CopyToFile(target); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation copies the member to location then deletes the member, returning the new file.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := f.CopyToLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	return newFile, f.Delete()
+}
+
+// CRUD Operations
+
+// Delete removes the member from the archive. The container is rewritten when the FileSystem is closed.
+func (f *File) Delete() error {
+	f.writeBuffer = nil
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return f.fileSystem.remove(f.name)
+}
+
+// Close releases any open reader and stages anything written to the member. Staged members are written to the
+// container when the FileSystem is closed.
+func (f *File) Close() error {
+	f.offset = 0
+	if f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.writeBuffer != nil {
+		data := f.writeBuffer.Bytes()
+		f.writeBuffer = nil
+		return f.fileSystem.stage(f.name, data)
+	}
+	return nil
+}
+
+// Read implements the io.Reader interface, streaming the member's content from the container.
+func (f *File) Read(p []byte) (int, error) {
+	if f.reader == nil {
+		e, err := f.entry()
+		if err != nil {
+			return 0, err
+		}
+		reader, err := e.open()
+		if err != nil {
+			return 0, err
+		}
+		if f.offset > 0 {
+			if _, err := io.CopyN(ioutil.Discard, reader, f.offset); err != nil && err != io.EOF {
+				_ = reader.Close()
+				return 0, err
+			}
+		}
+		f.reader = reader
+	}
+
+	n, err := f.reader.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Seek implements the io.Seeker interface. Members are not randomly accessible within an archive so the next Read
+// after a Seek reopens the member and skips to the new offset.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.offset + offset
+	case io.SeekEnd:
+		size, err := f.Size()
+		if err != nil {
+			return 0, err
+		}
+		newOffset = int64(size) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence value %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, errors.New("seek to a negative offset")
+	}
+
+	if newOffset != f.offset && f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		if err != nil {
+			return 0, err
+		}
+	}
+	f.offset = newOffset
+	return newOffset, nil
+}
+
+// Write implements the io.Writer interface. Content is buffered until Close() stages it in the FileSystem.
+func (f *File) Write(data []byte) (int, error) {
+	if f.writeBuffer == nil {
+		f.writeBuffer = bytes.NewBuffer([]byte{})
+	}
+	return f.writeBuffer.Write(data)
+}
+
+/*
+	Private helpers
+*/
+
+func (f *File) entry() (*entry, error) {
+	e, err := f.fileSystem.lookup(f.name)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, fmt.Errorf("file does not exist at %s", f)
+	}
+	return e, nil
+}
@@ -0,0 +1,303 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+const (
+	// ZipScheme defines the filesystem type for zip archives.
+	ZipScheme = "zip"
+	// TarScheme defines the filesystem type for tar archives.
+	TarScheme = "tar"
+)
+
+// FileSystem implements vfs.Filesystem for the members of a zip or tar archive stored in a container vfs.File.
+type FileSystem struct {
+	scheme    string
+	container vfs.File
+
+	mu      sync.Mutex
+	loaded  bool
+	dirty   bool
+	entries map[string]*entry
+	order   []string
+	reader  *fileReaderAt
+	size    int64
+}
+
+// entry is a single regular file within the archive.
+type entry struct {
+	name    string
+	size    int64
+	modTime time.Time
+
+	// data holds the content of a member staged by a Write/Close. It is nil for members read from the container.
+	data []byte
+
+	// source holds what is needed to stream a member from the container.
+	source memberSource
+}
+
+// NewZipFileSystem initializer returns a FileSystem for the zip archive stored in container.
+func NewZipFileSystem(container vfs.File) *FileSystem {
+	return &FileSystem{scheme: ZipScheme, container: container}
+}
+
+// NewTarFileSystem initializer returns a FileSystem for the tar archive stored in container. Containers whose name ends
+// in ".gz" or ".tgz" are read and written as gzip-compressed tars.
+func NewTarFileSystem(container vfs.File) *FileSystem {
+	return &FileSystem{scheme: TarScheme, container: container}
+}
+
+// NewFile function returns the archive implementation of vfs.File. Archives have no volume so volume is ignored.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	return newFile(fs, name)
+}
+
+// NewLocation function returns the archive implementation of vfs.Location. Archives have no volume so volume is
+// ignored.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	return &Location{
+		fileSystem: fs,
+		path:       cleanLocationPath(name),
+	}, nil
+}
+
+// Name returns "zip archive" or "tar archive"
+func (fs *FileSystem) Name() string {
+	return fs.scheme + " archive"
+}
+
+// Scheme returns "zip" or "tar" as the initial part of a file URI ie: zip://
+func (fs *FileSystem) Scheme() string {
+	return fs.scheme
+}
+
+// Container returns the vfs.File holding the archive.
+func (fs *FileSystem) Container() vfs.File {
+	return fs.container
+}
+
+// Close writes any staged members and deletes to the container, then closes it. If nothing was staged, the container
+// is left untouched and simply closed.
+func (fs *FileSystem) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.dirty {
+		if err := fs.flush(); err != nil {
+			return err
+		}
+	}
+
+	fs.loaded = false
+	fs.dirty = false
+	fs.entries = nil
+	fs.order = nil
+	fs.reader = nil
+	return fs.container.Close()
+}
+
+/*
+	Private helpers
+*/
+
+// load indexes the members of the container, if it hasn't already been done. Callers must hold fs.mu.
+func (fs *FileSystem) load() error {
+	if fs.loaded {
+		return nil
+	}
+
+	fs.entries = make(map[string]*entry)
+	fs.order = nil
+
+	exists, err := fs.container.Exists()
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		size, err := fs.container.Size()
+		if err != nil {
+			return err
+		}
+		fs.size = int64(size)
+		fs.reader = &fileReaderAt{file: fs.container}
+
+		var members []*entry
+		switch fs.scheme {
+		case ZipScheme:
+			members, err = readZipIndex(fs.reader, fs.size)
+		case TarScheme:
+			members, err = readTarIndex(fs.reader, fs.size, fs.isGzip())
+		default:
+			err = fmt.Errorf("unsupported archive scheme %q", fs.scheme)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, e := range members {
+			fs.addEntry(e)
+		}
+	}
+
+	fs.loaded = true
+	return nil
+}
+
+// addEntry adds or replaces an entry, preserving the position of replaced entries. Callers must hold fs.mu.
+func (fs *FileSystem) addEntry(e *entry) {
+	if _, ok := fs.entries[e.name]; !ok {
+		fs.order = append(fs.order, e.name)
+	}
+	fs.entries[e.name] = e
+}
+
+// lookup returns the entry at the given archive path, or nil if there isn't one.
+func (fs *FileSystem) lookup(name string) (*entry, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := fs.load(); err != nil {
+		return nil, err
+	}
+	return fs.entries[name], nil
+}
+
+// stage records new content for the member at the given archive path, to be written on Close().
+func (fs *FileSystem) stage(name string, data []byte) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := fs.load(); err != nil {
+		return err
+	}
+	fs.addEntry(&entry{
+		name:    name,
+		size:    int64(len(data)),
+		modTime: time.Now(),
+		data:    data,
+	})
+	fs.dirty = true
+	return nil
+}
+
+// remove deletes the member at the given archive path, to be removed from the container on Close().
+func (fs *FileSystem) remove(name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := fs.load(); err != nil {
+		return err
+	}
+	if _, ok := fs.entries[name]; !ok {
+		return fmt.Errorf("failed to delete. File does not exist at %s://%s", fs.scheme, name)
+	}
+	delete(fs.entries, name)
+	for i, n := range fs.order {
+		if n == name {
+			fs.order = append(fs.order[:i], fs.order[i+1:]...)
+			break
+		}
+	}
+	fs.dirty = true
+	return nil
+}
+
+// list returns the base names of all members directly within the archive directory dir, which must have leading and
+// trailing slashes.
+func (fs *FileSystem) list(dir string) ([]string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	files := make([]string, 0)
+	if err := fs.load(); err != nil {
+		return files, err
+	}
+	for _, name := range fs.order {
+		if memberDir(name) == dir {
+			files = append(files, path.Base(name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// listDirs returns the names of the directories directly beneath the archive directory dir.
+func (fs *FileSystem) listDirs(dir string) ([]string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	dirs := make([]string, 0)
+	if err := fs.load(); err != nil {
+		return dirs, err
+	}
+	seen := make(map[string]bool)
+	for _, name := range fs.order {
+		if !strings.HasPrefix(name, dir) {
+			continue
+		}
+		rest := strings.TrimPrefix(name, dir)
+		if i := strings.Index(rest, "/"); i > 0 && !seen[rest[:i]] {
+			seen[rest[:i]] = true
+			dirs = append(dirs, rest[:i])
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// hasPrefix reports whether any member lives beneath the archive directory dir.
+func (fs *FileSystem) hasPrefix(dir string) (bool, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := fs.load(); err != nil {
+		return false, err
+	}
+	for _, name := range fs.order {
+		if strings.HasPrefix(name, dir) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isGzip reports whether the container should be treated as gzip-compressed.
+func (fs *FileSystem) isGzip() bool {
+	name := strings.ToLower(fs.container.Name())
+	return strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz")
+}
+
+func newFile(fs *FileSystem, name string) (*File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil archive.FileSystem pointer is required")
+	}
+	name = utils.CleanPrefix(name)
+	if name == "" {
+		return nil, errors.New("non-empty string for name is required")
+	}
+	return &File{
+		fileSystem: fs,
+		name:       "/" + name,
+	}, nil
+}
+
+// memberDir returns the archive directory of a member, with leading and trailing slashes.
+func memberDir(name string) string {
+	return utils.EnsureTrailingSlash(path.Dir(name))
+}
+
+func cleanLocationPath(name string) string {
+	return "/" + utils.EnsureTrailingSlash(utils.CleanPrefix(name))
+}
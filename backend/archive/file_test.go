@@ -0,0 +1,245 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir string
+}
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "archive-test")
+	s.NoError(err)
+	s.tmpDir = dir
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) container(name string) vfs.File {
+	file, err := (&_os.FileSystem{}).NewFile("", filepath.Join(s.tmpDir, name))
+	s.NoError(err)
+	return file
+}
+
+func (s *fileTestSuite) TestReadZip() {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("data/a.csv")
+	s.NoError(err)
+	_, err = w.Write([]byte("a,b,c\n1,2,3\n"))
+	s.NoError(err)
+	_, err = zw.Create("data/empty/")
+	s.NoError(err)
+	s.NoError(zw.Close())
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "bundle.zip"), buf.Bytes(), 0644))
+
+	fs := NewZipFileSystem(s.container("bundle.zip"))
+	file, err := fs.NewFile("", "/data/a.csv")
+	s.NoError(err)
+
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.True(exists, "member exists")
+
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(12), size, "uncompressed size")
+
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("a,b,c\n1,2,3\n", string(contents))
+
+	_, err = file.Seek(6, io.SeekStart)
+	s.NoError(err)
+	contents, err = ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("1,2,3\n", string(contents), "seek reopens member at offset")
+
+	s.NoError(file.Close())
+	s.NoError(fs.Close())
+	s.Equal("zip:///data/a.csv", file.URI())
+}
+
+func (s *fileTestSuite) TestReadTarGz() {
+	for _, name := range []string{"bundle.tar", "bundle.tar.gz"} {
+		buf := &bytes.Buffer{}
+		var w io.Writer = buf
+		var gz *gzip.Writer
+		if filepath.Ext(name) == ".gz" {
+			gz = gzip.NewWriter(buf)
+			w = gz
+		}
+		tw := tar.NewWriter(w)
+		for _, member := range []string{"one.txt", "dir/two.txt"} {
+			content := "content of " + member
+			s.NoError(tw.WriteHeader(&tar.Header{Name: member, Mode: 0644, Size: int64(len(content))}))
+			_, err := tw.Write([]byte(content))
+			s.NoError(err)
+		}
+		s.NoError(tw.Close())
+		if gz != nil {
+			s.NoError(gz.Close())
+		}
+		s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, name), buf.Bytes(), 0644))
+
+		fs := NewTarFileSystem(s.container(name))
+		file, err := fs.NewFile("", "dir/two.txt")
+		s.NoError(err)
+		contents, err := ioutil.ReadAll(file)
+		s.NoError(err, name)
+		s.Equal("content of dir/two.txt", string(contents), name)
+
+		other, err := fs.NewFile("", "one.txt")
+		s.NoError(err)
+		_, err = other.Seek(-3, io.SeekEnd)
+		s.NoError(err)
+		contents, err = ioutil.ReadAll(other)
+		s.NoError(err, name)
+		s.Equal("txt", string(contents), name)
+
+		s.NoError(fs.Close())
+	}
+}
+
+func (s *fileTestSuite) TestWriteNewZip() {
+	container := s.container("new.zip")
+	fs := NewZipFileSystem(container)
+
+	file, err := fs.NewFile("", "/out/report.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("hello "))
+	s.NoError(err)
+	_, err = file.Write([]byte("world"))
+	s.NoError(err)
+
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists, "not staged until close")
+
+	s.NoError(file.Close())
+	exists, err = file.Exists()
+	s.NoError(err)
+	s.True(exists, "staged on close")
+
+	exists, err = container.Exists()
+	s.NoError(err)
+	s.False(exists, "container not written until filesystem close")
+
+	s.NoError(fs.Close())
+
+	zr, err := zip.OpenReader(filepath.Join(s.tmpDir, "new.zip"))
+	s.NoError(err)
+	defer zr.Close()
+	s.Len(zr.File, 1)
+	s.Equal("out/report.txt", zr.File[0].Name)
+	r, err := zr.File[0].Open()
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(r)
+	s.NoError(err)
+	s.Equal("hello world", string(contents))
+}
+
+func (s *fileTestSuite) TestModifyTar() {
+	container := s.container("existing.tar")
+	fs := NewTarFileSystem(container)
+	for _, name := range []string{"keep.txt", "replace.txt", "remove.txt"} {
+		file, err := fs.NewFile("", name)
+		s.NoError(err)
+		_, err = file.Write([]byte("original " + name))
+		s.NoError(err)
+		s.NoError(file.Close())
+	}
+	s.NoError(fs.Close())
+	original, err := os.Stat(filepath.Join(s.tmpDir, "existing.tar"))
+	s.NoError(err)
+
+	fs = NewTarFileSystem(container)
+	replace, err := fs.NewFile("", "replace.txt")
+	s.NoError(err)
+	_, err = replace.Write([]byte("new"))
+	s.NoError(err)
+	s.NoError(replace.Close())
+
+	remove, err := fs.NewFile("", "remove.txt")
+	s.NoError(err)
+	s.NoError(remove.Delete())
+	s.Error(remove.Delete(), "member already deleted")
+	s.NoError(fs.Close())
+	modified, err := os.Stat(filepath.Join(s.tmpDir, "existing.tar"))
+	s.NoError(err)
+	s.True(modified.Size() < original.Size(), "the shorter archive replaces the container's content")
+
+	f, err := os.Open(filepath.Join(s.tmpDir, "existing.tar"))
+	s.NoError(err)
+	defer f.Close()
+	tr := tar.NewReader(f)
+	found := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		s.NoError(err)
+		contents, err := ioutil.ReadAll(tr)
+		s.NoError(err)
+		found[header.Name] = string(contents)
+	}
+	s.Equal(map[string]string{"keep.txt": "original keep.txt", "replace.txt": "new"}, found)
+}
+
+func (s *fileTestSuite) TestCopyToLocation() {
+	fs := NewZipFileSystem(s.container("copy.zip"))
+	file, err := fs.NewFile("", "a/src.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("copy me"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	osLocation, err := (&_os.FileSystem{}).NewLocation("", s.tmpDir)
+	s.NoError(err)
+	copied, err := file.CopyToLocation(osLocation)
+	s.NoError(err)
+	contents, err := ioutil.ReadFile(copied.Path())
+	s.NoError(err)
+	s.Equal("copy me", string(contents))
+
+	modTime, err := file.LastModified()
+	s.NoError(err)
+	s.WithinDuration(time.Now(), *modTime, time.Minute)
+
+	target, err := file.Location().NewLocation("../b/")
+	s.NoError(err)
+	moved, err := file.MoveToLocation(target)
+	s.NoError(err)
+	s.Equal("/b/src.txt", moved.Path())
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists, "source removed by move")
+	s.NoError(fs.Close())
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
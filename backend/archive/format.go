@@ -0,0 +1,294 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// memberSource opens a reader for the content of a member stored in the container.
+type memberSource interface {
+	open() (io.ReadCloser, error)
+}
+
+// open returns a reader for the entry's content, whether staged or stored in the container.
+func (e *entry) open() (io.ReadCloser, error) {
+	if e.data != nil || e.source == nil {
+		return ioutil.NopCloser(bytes.NewReader(e.data)), nil
+	}
+	return e.source.open()
+}
+
+// fileReaderAt adapts a vfs.File to io.ReaderAt so that members can be streamed from any backend. Reads are
+// serialized since each one repositions the underlying file.
+type fileReaderAt struct {
+	mu   sync.Mutex
+	file vfs.File
+}
+
+// ReadAt implements io.ReaderAt
+func (r *fileReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.file.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.file, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+/*
+	zip
+*/
+
+type zipSource struct {
+	file *zip.File
+}
+
+func (s zipSource) open() (io.ReadCloser, error) {
+	return s.file.Open()
+}
+
+func readZipIndex(r io.ReaderAt, size int64) ([]*entry, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*entry
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		entries = append(entries, &entry{
+			name:    "/" + cleanMemberName(f.Name),
+			size:    int64(f.UncompressedSize64),
+			modTime: f.Modified,
+			source:  zipSource{file: f},
+		})
+	}
+	return entries, nil
+}
+
+func writeZip(w io.Writer, entries []*entry) error {
+	zw := zip.NewWriter(w)
+	for _, e := range entries {
+		header := &zip.FileHeader{
+			Name:     e.name[1:],
+			Method:   zip.Deflate,
+			Modified: e.modTime,
+		}
+		mw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if err := copyEntry(mw, e); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+/*
+	tar
+*/
+
+// tarSource streams a tar member from its offset in the (uncompressed) archive stream.
+type tarSource struct {
+	reader      io.ReaderAt
+	archiveSize int64
+	gzip        bool
+	offset      int64
+	size        int64
+}
+
+func (s tarSource) open() (io.ReadCloser, error) {
+	if !s.gzip {
+		return ioutil.NopCloser(io.NewSectionReader(s.reader, s.offset, s.size)), nil
+	}
+
+	gz, err := gzip.NewReader(io.NewSectionReader(s.reader, 0, s.archiveSize))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(ioutil.Discard, gz, s.offset); err != nil {
+		_ = gz.Close()
+		return nil, err
+	}
+	return &readCloser{Reader: io.LimitReader(gz, s.size), Closer: gz}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// countingReader tracks how many bytes have been read so that member offsets can be recorded while indexing.
+type countingReader struct {
+	io.Reader
+	count int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+func readTarIndex(r io.ReaderAt, size int64, isGzip bool) ([]*entry, error) {
+	var stream io.Reader = io.NewSectionReader(r, 0, size)
+	if isGzip {
+		gz, err := gzip.NewReader(stream)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		stream = gz
+	}
+	counter := &countingReader{Reader: stream}
+	tr := tar.NewReader(counter)
+
+	var entries []*entry
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		// tar.Reader consumes exactly the header blocks on Next(), so the counter now sits at the member's content.
+		entries = append(entries, &entry{
+			name:    "/" + cleanMemberName(header.Name),
+			size:    header.Size,
+			modTime: header.ModTime,
+			source: tarSource{
+				reader:      r,
+				archiveSize: size,
+				gzip:        isGzip,
+				offset:      counter.count,
+				size:        header.Size,
+			},
+		})
+	}
+	return entries, nil
+}
+
+func writeTar(w io.Writer, entries []*entry, isGzip bool) error {
+	var gz *gzip.Writer
+	if isGzip {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		header := &tar.Header{
+			Name:     e.name[1:],
+			Mode:     0644,
+			Size:     e.size,
+			ModTime:  e.modTime,
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if err := copyEntry(tw, e); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
+
+/*
+	writing the container
+*/
+
+// flush rewrites the container with the current set of entries. The new archive is assembled in a local temp file
+// first since existing members are streamed from the container being replaced. Callers must hold fs.mu.
+func (fs *FileSystem) flush() error {
+	if err := fs.load(); err != nil {
+		return err
+	}
+
+	entries := make([]*entry, 0, len(fs.order))
+	for _, name := range fs.order {
+		entries = append(entries, fs.entries[name])
+	}
+
+	tmpFile, err := ioutil.TempFile("", "vfs-archive")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	switch fs.scheme {
+	case ZipScheme:
+		err = writeZip(tmpFile, entries)
+	case TarScheme:
+		err = writeTar(tmpFile, entries, fs.isGzip())
+	default:
+		err = fmt.Errorf("unsupported archive scheme %q", fs.scheme)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Writing to the closed container replaces its content, so a shorter archive doesn't leave trailing bytes behind.
+	if err := fs.container.Close(); err != nil {
+		return err
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = utils.TouchCopyReader(fs.container, tmpFile)
+	return err
+}
+
+func copyEntry(w io.Writer, e *entry) error {
+	r, err := e.open()
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		_ = r.Close()
+		return err
+	}
+	return r.Close()
+}
+
+// cleanMemberName strips any leading "./" or "/" from a member name as stored in the archive.
+func cleanMemberName(name string) string {
+	for len(name) > 0 && (name[0] == '/' || name[0] == '.') {
+		if name[0] == '.' && (len(name) == 1 || name[1] != '/') {
+			break
+		}
+		name = name[1:]
+	}
+	return name
+}
@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface for a directory within an archive.
+type Location struct {
+	fileSystem *FileSystem
+	path       string
+}
+
+// List returns a slice of the base names of all members directly within the location's directory.
+func (l *Location) List() ([]string, error) {
+	return l.fileSystem.list(l.path)
+}
+
+// ListByPrefix returns a slice of the base names of all members directly within the location's directory that start
+// with prefix.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	if err := utils.ValidateFilePrefix(prefix); err != nil {
+		return nil, err
+	}
+	return l.filter(func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// ListByRegex returns a slice of the base names of all members directly within the location's directory that match
+// the regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.filter(regex.MatchString)
+}
+
+// ListDirs returns the names of the directories directly within the location's directory.
+func (l *Location) ListDirs() ([]string, error) {
+	return l.fileSystem.listDirs(l.path)
+}
+
+func (l *Location) filter(test func(name string) bool) ([]string, error) {
+	names, err := l.List()
+	if err != nil {
+		return names, err
+	}
+
+	filtered := make([]string, 0)
+	for _, name := range names {
+		if test(name) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
+
+// Volume returns "" since archives have no volume.
+func (l *Location) Volume() string {
+	return ""
+}
+
+// Path returns the location's path within the archive, with leading and trailing slashes.
+func (l *Location) Path() string {
+	return l.path
+}
+
+// Exists returns true if any member of the archive lives at or beneath the location. The root location exists
+// whenever the container does.
+func (l *Location) Exists() (bool, error) {
+	if l.path == "/" {
+		return l.fileSystem.container.Exists()
+	}
+	return l.fileSystem.hasPrefix(l.path)
+}
+
+// NewLocation makes a copy of the underlying Location, then modifies its path by calling ChangeDir with the
+// relativePath argument, returning the resulting location.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	newLocation := &Location{}
+	*newLocation = *l
+	if err := newLocation.ChangeDir(relativePath); err != nil {
+		return nil, err
+	}
+	return newLocation, nil
+}
+
+// ChangeDir takes a relative path, and modifies the underlying Location's path. For this implementation there are no
+// errors.
+func (l *Location) ChangeDir(relativePath string) error {
+	l.path = cleanLocationPath(path.Join(l.path, relativePath))
+	return nil
+}
+
+// FileSystem returns a vfs.FileSystem interface of the location's underlying fileSystem.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile uses the properties of the calling location to generate a vfs.File for an archive member. The filePath
+// argument is expected to be a relative path to the location's current path.
+func (l *Location) NewFile(filePath string) (vfs.File, error) {
+	return newFile(l.fileSystem, path.Join(l.path, filePath))
+}
+
+// DeleteFile removes the member at fileName path.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
@@ -0,0 +1,129 @@
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type locationTestSuite struct {
+	suite.Suite
+	tmpDir string
+	fs     *FileSystem
+}
+
+func (s *locationTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "archive-test")
+	s.NoError(err)
+	s.tmpDir = dir
+
+	container, err := (&_os.FileSystem{}).NewFile("", filepath.Join(dir, "list.zip"))
+	s.NoError(err)
+	s.fs = NewZipFileSystem(container)
+	for _, name := range []string{"top.txt", "data/a.csv", "data/b.csv", "data/readme.md", "data/nested/c.csv"} {
+		file, err := s.fs.NewFile("", name)
+		s.NoError(err)
+		_, err = file.Write([]byte(name))
+		s.NoError(err)
+		s.NoError(file.Close())
+	}
+	s.NoError(s.fs.Close())
+}
+
+func (s *locationTestSuite) TearDownTest() {
+	s.NoError(s.fs.Close())
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *locationTestSuite) TestList() {
+	root, err := s.fs.NewLocation("", "/")
+	s.NoError(err)
+	files, err := root.List()
+	s.NoError(err)
+	s.Equal([]string{"top.txt"}, files, "only members directly at root")
+
+	data, err := root.NewLocation("data/")
+	s.NoError(err)
+	s.Equal("/data/", data.Path())
+	s.Equal("zip:///data/", data.URI())
+
+	files, err = data.List()
+	s.NoError(err)
+	s.Equal([]string{"a.csv", "b.csv", "readme.md"}, files)
+
+	files, err = data.ListByPrefix("b")
+	s.NoError(err)
+	s.Equal([]string{"b.csv"}, files)
+
+	_, err = data.ListByPrefix("nested/c")
+	s.Error(err, "prefix may not contain slashes")
+
+	files, err = data.ListByRegex(regexp.MustCompile(`\.csv$`))
+	s.NoError(err)
+	s.Equal([]string{"a.csv", "b.csv"}, files)
+
+	missing, err := data.NewLocation("../missing/")
+	s.NoError(err)
+	files, err = missing.List()
+	s.NoError(err)
+	s.Equal([]string{}, files, "empty slice for non-existent location")
+}
+
+func (s *locationTestSuite) TestListDirs() {
+	root, err := s.fs.NewLocation("", "/")
+	s.NoError(err)
+	dirs, err := root.(*Location).ListDirs()
+	s.NoError(err)
+	s.Equal([]string{"data"}, dirs)
+
+	var walked []string
+	s.NoError(utils.Walk(root, func(relativePath string, file vfs.File) error {
+		walked = append(walked, relativePath)
+		return nil
+	}))
+	s.Equal([]string{"top.txt", "data/a.csv", "data/b.csv", "data/readme.md", "data/nested/c.csv"}, walked)
+}
+
+func (s *locationTestSuite) TestExists() {
+	nested, err := s.fs.NewLocation("", "data/nested")
+	s.NoError(err)
+	exists, err := nested.Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	s.NoError(nested.ChangeDir("../../nope/"))
+	s.Equal("/nope/", nested.Path())
+	exists, err = nested.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *locationTestSuite) TestNewFileAndDelete() {
+	data, err := s.fs.NewLocation("", "data")
+	s.NoError(err)
+	file, err := data.NewFile("nested/c.csv")
+	s.NoError(err)
+	s.Equal("/data/nested/c.csv", file.Path())
+	s.Equal("/data/nested/", file.Location().Path())
+
+	s.NoError(data.DeleteFile("a.csv"))
+	files, err := data.List()
+	s.NoError(err)
+	s.Equal([]string{"b.csv", "readme.md"}, files)
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
@@ -0,0 +1,56 @@
+/*
+Package audit audit trail VFS implementation.
+
+An audit FileSystem wraps another vfs.FileSystem and records every write, delete, move and copy made through it as an
+Event in a Sink. Events have the operation, the source and target URIs on the wrapped FileSystem, the size, the
+duration, the principal from Options and the outcome.
+
+Usage
+
+Audit filesystems wrap another FileSystem so they are not registered with backend. Wrap a registered backend directly,
+here recording to JSON lines files:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend"
+      "github.com/c2fo/vfs/v3/backend/audit"
+      _ "github.com/c2fo/vfs/v3/backend/s3"
+  )
+
+  func DoSomething() error {
+      logs, err := backend.Backend("s3").NewLocation("audit-bucket", "/logs/")
+      if err != nil {
+          return err
+      }
+      sink := audit.NewJSONSink(logs).WithOptions(audit.JSONSinkOptions{Prefix: "myjob-"})
+      defer sink.Close()
+
+      fs := audit.NewFileSystem(backend.Backend("s3"), sink).WithOptions(audit.Options{
+          Principal: "svc-myjob",
+      })
+      file, err := fs.NewFile("prod-bucket", "/path/to/file.txt")
+      ...
+  }
+
+Each line of the files is an Event as JSON:
+
+  {"time":"2020-03-01T12:00:00Z","op":"delete","source":"s3://prod-bucket/path/to/file.txt","size":1024,
+   "durationNs":31000000,"principal":"svc-myjob","outcome":"success"}
+
+Writes are recorded when the file is closed, with the number of bytes written, so a file written but never closed
+isn't recorded. Failed operations are recorded with the outcome "failure" and the error. When the operation succeeds
+but the Sink fails to record it, the Sink's error is returned so that no change goes unaudited.
+
+Sinks
+
+Sink is a single method interface, and SinkFunc adapts a function to it. JSONSink writes batches of events to new files
+in a vfs.Location on any backend, ie: s3://audit-bucket/logs/myjob-20200301T120000.000000000Z-0.jsonl, so files
+already written are never rewritten. A batch is written once it has JSONSinkOptions.MaxEvents events or its first
+event is JSONSinkOptions.FlushInterval old, and by Flush and Close, so Close the sink before exiting.
+
+Scheme
+
+The scheme of an audit FileSystem is the wrapped scheme prefixed with "audit+", ie: audit+s3://mybucket/file.txt. The
+distinct scheme stops backends from using their native copy to write to an audited location unrecorded. Copies and
+moves between files of the same audit FileSystem still use the backend's native copy.
+*/
+package audit
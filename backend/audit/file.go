@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for an audited file.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+
+	// writeStart is when the first Write since the file was last closed began, zero when not writing.
+	writeStart time.Time
+	written    uint64
+	writeErr   error
+}
+
+// Info Functions
+
+// LastModified returns the timestamp of the wrapped file.
+func (f *File) LastModified() (*time.Time, error) {
+	return f.file.LastModified()
+}
+
+// Name returns the name of the wrapped file.
+func (f *File) Name() string {
+	return f.file.Name()
+}
+
+// Path returns the path of the wrapped file.
+func (f *File) Path() string {
+	return f.file.Path()
+}
+
+// Exists returns whether the wrapped file exists.
+func (f *File) Exists() (bool, error) {
+	return f.file.Exists()
+}
+
+// Size returns the size of the wrapped file.
+func (f *File) Size() (uint64, error) {
+	return f.file.Size()
+}
+
+// Location returns an audit Location for the wrapped file's location.
+func (f *File) Location() vfs.Location {
+	return &Location{fileSystem: f.fileSystem, location: f.file.Location()}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the file to the target file, recording an OpCopy Event. A target audited by the same FileSystem
+// is unwrapped first, so the backend may copy natively.
+func (f *File) CopyToFile(target vfs.File) error {
+	return f.toFile(OpCopy, target, f.file.CopyToFile)
+}
+
+// CopyToLocation copies the file to a file of the same name at location, recording an OpCopy Event.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	return f.toLocation(OpCopy, location, f.file.CopyToLocation)
+}
+
+// MoveToFile moves the file to the target file, recording an OpMove Event. A target audited by the same FileSystem
+// is unwrapped first, so the backend may move natively.
+func (f *File) MoveToFile(target vfs.File) error {
+	return f.toFile(OpMove, target, f.file.MoveToFile)
+}
+
+// MoveToLocation moves the file to a file of the same name at location, recording an OpMove Event.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	return f.toLocation(OpMove, location, f.file.MoveToLocation)
+}
+
+// CRUD Operations
+
+// Delete deletes the wrapped file, recording an OpDelete Event.
+func (f *File) Delete() error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+	size := f.size()
+	start := time.Now()
+	err := f.file.Delete()
+	return f.fileSystem.record(OpDelete, f.file.URI(), "", size, start, err)
+}
+
+// Close closes the wrapped file. If the file was written since it was last closed, an OpWrite Event is recorded with
+// the number of bytes written, since closing is when most backends finish writing.
+func (f *File) Close() error {
+	err := f.file.Close()
+	if f.writeStart.IsZero() {
+		return err
+	}
+	if f.writeErr != nil {
+		err = f.writeErr
+	}
+	start, written := f.writeStart, f.written
+	f.writeStart, f.written, f.writeErr = time.Time{}, 0, nil
+	return f.fileSystem.record(OpWrite, f.file.URI(), "", written, start, err)
+}
+
+// Read calls Read on the wrapped file.
+func (f *File) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// Seek calls Seek on the wrapped file.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Write calls Write on the wrapped file. The write is recorded as an OpWrite Event when the file is closed.
+func (f *File) Write(data []byte) (int, error) {
+	if f.writeStart.IsZero() {
+		f.writeStart = time.Now()
+	}
+	n, err := f.file.Write(data)
+	f.written += uint64(n)
+	if err != nil && f.writeErr == nil {
+		f.writeErr = err
+	}
+	return n, err
+}
+
+/*
+	Private helpers
+*/
+
+// toFile calls fn, a CopyToFile or MoveToFile of the wrapped file, recording the Event for op.
+func (f *File) toFile(op string, target vfs.File, fn func(vfs.File) error) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if t, ok := target.(*File); ok && t.fileSystem == f.fileSystem {
+		target = t.file
+	}
+	source, size := f.file.URI(), f.size()
+	start := time.Now()
+	err := fn(target)
+	return f.fileSystem.record(op, source, target.URI(), size, start, err)
+}
+
+// toLocation calls fn, a CopyToLocation or MoveToLocation of the wrapped file, recording the Event for op.
+func (f *File) toLocation(op string, location vfs.Location, fn func(vfs.Location) (vfs.File, error)) (vfs.File, error) {
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	l, sameFileSystem := location.(*Location)
+	sameFileSystem = sameFileSystem && l.fileSystem == f.fileSystem
+	if sameFileSystem {
+		location = l.location
+	}
+	source, size := f.file.URI(), f.size()
+	start := time.Now()
+	newFile, err := fn(location)
+
+	target := location.URI() + f.Name()
+	if newFile != nil {
+		target = newFile.URI()
+	}
+	err = f.fileSystem.record(op, source, target, size, start, err)
+	if newFile != nil && sameFileSystem {
+		newFile = &File{fileSystem: f.fileSystem, file: newFile}
+	}
+	return newFile, err
+}
+
+// size returns the size of the wrapped file, or 0 if it can't be found.
+func (f *File) size() uint64 {
+	size, err := f.file.Size()
+	if err != nil {
+		return 0
+	}
+	return size
+}
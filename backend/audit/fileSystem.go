@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// SchemePrefix is prepended to the scheme of the wrapped FileSystem, ie: audit+s3. A distinct scheme stops backends
+// from using their native copy to write to an audited location without it being recorded.
+const SchemePrefix = "audit+"
+
+// Options holds audit-specific options.
+type Options struct {
+	// Principal identifies who is making changes through the FileSystem, ie: a user or service name. It's recorded in
+	// every Event.
+	Principal string `json:"principal,omitempty"`
+}
+
+// FileSystem implements vfs.Filesystem by recording every write, delete, move and copy on another FileSystem to a
+// Sink.
+type FileSystem struct {
+	fileSystem vfs.FileSystem
+	sink       Sink
+	options    Options
+}
+
+// NewFileSystem initializer returns a FileSystem which records changes to fileSystem in sink.
+func NewFileSystem(fileSystem vfs.FileSystem, sink Sink) *FileSystem {
+	return &FileSystem{fileSystem: fileSystem, sink: sink}
+}
+
+// WithOptions sets options for the FileSystem. Any options that aren't audit.Options are ignored.
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+	}
+	return fs
+}
+
+// Unwrap returns the audited FileSystem.
+func (fs *FileSystem) Unwrap() vfs.FileSystem {
+	return fs.fileSystem
+}
+
+// NewFile function returns the audit implementation of vfs.File.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if err := fs.validate(); err != nil {
+		return nil, err
+	}
+	file, err := fs.fileSystem.NewFile(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: fs, file: file}, nil
+}
+
+// NewLocation function returns the audit implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if err := fs.validate(); err != nil {
+		return nil, err
+	}
+	location, err := fs.fileSystem.NewLocation(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, location: location}, nil
+}
+
+// Name returns the name of the wrapped FileSystem prefixed with "audited", ie: "audited AWS S3"
+func (fs *FileSystem) Name() string {
+	if fs.fileSystem == nil {
+		return "audited"
+	}
+	return "audited " + fs.fileSystem.Name()
+}
+
+// Scheme returns the scheme of the wrapped FileSystem prefixed with SchemePrefix, ie: audit+s3
+func (fs *FileSystem) Scheme() string {
+	if fs.fileSystem == nil {
+		return SchemePrefix
+	}
+	return SchemePrefix + fs.fileSystem.Scheme()
+}
+
+func (fs *FileSystem) validate() error {
+	if fs.fileSystem == nil {
+		return errors.New("audit FileSystem requires a FileSystem")
+	}
+	if fs.sink == nil {
+		return errors.New("audit FileSystem requires a Sink")
+	}
+	return nil
+}
+
+// record sends an Event for an operation that began at start and returned opErr to the sink. It returns opErr, or the
+// sink's error if the operation succeeded.
+func (fs *FileSystem) record(op, source, target string, size uint64, start time.Time, opErr error) error {
+	event := Event{
+		Time:      start,
+		Op:        op,
+		Source:    source,
+		Target:    target,
+		Size:      size,
+		Duration:  time.Since(start),
+		Principal: fs.options.Principal,
+		Outcome:   OutcomeSuccess,
+	}
+	if opErr != nil {
+		event.Outcome = OutcomeFailure
+		event.Error = opErr.Error()
+	}
+	if err := fs.sink.Record(event); err != nil && opErr == nil {
+		return err
+	}
+	return opErr
+}
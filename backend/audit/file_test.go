@@ -0,0 +1,208 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir  string
+	events  []Event
+	sinkErr error
+	fs      *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "audit-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.events = nil
+	s.sinkErr = nil
+	sink := SinkFunc(func(event Event) error {
+		s.events = append(s.events, event)
+		return s.sinkErr
+	})
+	s.fs = NewFileSystem(&_os.FileSystem{}, sink).WithOptions(Options{Principal: "tester"})
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) TestWrite() {
+	name := filepath.Join(s.tmpDir, "data.txt")
+	file, err := s.fs.NewFile("", name)
+	s.NoError(err)
+	s.Equal("audit+file://"+name, file.URI())
+
+	_, err = file.Write([]byte("hello"))
+	s.NoError(err)
+	_, err = file.Write([]byte(" world"))
+	s.NoError(err)
+	s.Empty(s.events, "recorded on close")
+	s.NoError(file.Close())
+
+	s.Len(s.events, 1)
+	event := s.events[0]
+	s.Equal(OpWrite, event.Op)
+	s.Equal("file://"+name, event.Source)
+	s.Equal(uint64(11), event.Size)
+	s.Equal("tester", event.Principal)
+	s.Equal(OutcomeSuccess, event.Outcome)
+	s.False(event.Time.IsZero())
+
+	// reading and closing isn't recorded
+	_, err = ioutil.ReadAll(file)
+	s.NoError(err)
+	s.NoError(file.Close())
+	s.Len(s.events, 1)
+}
+
+func (s *fileTestSuite) TestDeleteAndMove() {
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "a.txt"), []byte("abc"), 0644))
+	location, err := s.fs.NewLocation("", s.tmpDir+"/")
+	s.NoError(err)
+	file, err := location.NewFile("a.txt")
+	s.NoError(err)
+
+	sub, err := location.NewLocation("sub/")
+	s.NoError(err)
+	moved, err := file.MoveToLocation(sub)
+	s.NoError(err)
+	s.Equal("audit+file://"+filepath.Join(s.tmpDir, "sub", "a.txt"), moved.URI(), "still audited")
+	s.NoError(sub.DeleteFile("a.txt"))
+	s.Error(sub.DeleteFile("a.txt"))
+
+	s.Len(s.events, 3)
+	s.Equal(OpMove, s.events[0].Op)
+	s.Equal("file://"+filepath.Join(s.tmpDir, "a.txt"), s.events[0].Source)
+	s.Equal("file://"+filepath.Join(s.tmpDir, "sub", "a.txt"), s.events[0].Target)
+	s.Equal(uint64(3), s.events[0].Size)
+	s.Equal(OpDelete, s.events[1].Op)
+	s.Equal(OutcomeSuccess, s.events[1].Outcome)
+	s.Equal(OpDelete, s.events[2].Op)
+	s.Equal(OutcomeFailure, s.events[2].Outcome)
+	s.NotEmpty(s.events[2].Error)
+}
+
+func (s *fileTestSuite) TestCopyFromUnaudited() {
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "a.txt"), []byte("abc"), 0644))
+	source, err := (&_os.FileSystem{}).NewFile("", filepath.Join(s.tmpDir, "a.txt"))
+	s.NoError(err)
+	target, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "b.txt"))
+	s.NoError(err)
+
+	s.NoError(source.CopyToFile(target))
+	s.Len(s.events, 1, "copying into an audited file is a write")
+	s.Equal(OpWrite, s.events[0].Op)
+	s.Equal(uint64(3), s.events[0].Size)
+}
+
+func (s *fileTestSuite) TestSinkError() {
+	s.sinkErr = errors.New("sink unavailable")
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "data.txt"))
+	s.NoError(err)
+	_, err = file.Write([]byte("x"))
+	s.NoError(err)
+	s.EqualError(file.Close(), "sink unavailable", "unaudited changes are reported")
+
+	s.EqualError(file.Delete(), "sink unavailable")
+	s.Error(file.Delete(), "the operation's own error wins")
+	s.NotEqual("sink unavailable", file.Delete().Error())
+}
+
+func (s *fileTestSuite) TestJSONSink() {
+	logDir := filepath.Join(s.tmpDir, "logs")
+	s.NoError(os.Mkdir(logDir, 0755))
+	s.NoError(ioutil.WriteFile(filepath.Join(logDir, "earlier.jsonl"), []byte(`{"op":"earlier"}`), 0644))
+	logs, err := (&_os.FileSystem{}).NewLocation("", logDir+"/")
+	s.NoError(err)
+	sink := NewJSONSink(logs).WithOptions(JSONSinkOptions{Prefix: "job-", MaxEvents: 2})
+	fs := NewFileSystem(&_os.FileSystem{}, sink).WithOptions(Options{Principal: "tester"})
+
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		file, err := fs.NewFile("", filepath.Join(s.tmpDir, name))
+		s.NoError(err)
+		_, err = file.Write([]byte(name))
+		s.NoError(err)
+		s.NoError(file.Close())
+	}
+	batches, err := logs.ListByPrefix("job-")
+	s.NoError(err)
+	s.Len(batches, 1, "a full batch is written")
+
+	s.NoError(sink.Close())
+	batches, err = logs.ListByPrefix("job-")
+	s.NoError(err)
+	s.Len(batches, 2, "the rest are written on close")
+	sort.Strings(batches)
+
+	first := s.readLines(filepath.Join(logDir, batches[0]))
+	s.Len(first, 2)
+	s.Equal("write", first[0]["op"])
+	s.Equal("file://"+filepath.Join(s.tmpDir, "a.txt"), first[0]["source"])
+	s.Equal("tester", first[1]["principal"])
+	s.Equal("success", first[1]["outcome"])
+	second := s.readLines(filepath.Join(logDir, batches[1]))
+	s.Len(second, 1)
+	s.Equal("file://"+filepath.Join(s.tmpDir, "c.txt"), second[0]["source"])
+
+	earlier, err := ioutil.ReadFile(filepath.Join(logDir, "earlier.jsonl"))
+	s.NoError(err)
+	s.Equal(`{"op":"earlier"}`, string(earlier), "existing files aren't rewritten")
+	s.NoError(sink.Close(), "closing with nothing buffered writes nothing")
+	batches, err = logs.ListByPrefix("job-")
+	s.NoError(err)
+	s.Len(batches, 2)
+}
+
+func (s *fileTestSuite) TestJSONSinkFlushInterval() {
+	logs, err := (&_os.FileSystem{}).NewLocation("", s.tmpDir+"/")
+	s.NoError(err)
+	sink := NewJSONSink(logs).WithOptions(JSONSinkOptions{FlushInterval: 10 * time.Millisecond})
+	s.NoError(sink.Record(Event{Op: OpDelete, Source: "file:///a.txt", Outcome: OutcomeSuccess}))
+
+	var batches []string
+	for deadline := time.Now().Add(time.Second); len(batches) == 0 && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+		batches, err = logs.List()
+		s.NoError(err)
+	}
+	s.Len(batches, 1, "a batch is written once its first event is FlushInterval old")
+	s.NoError(sink.Close())
+}
+
+// readLines returns the JSON lines of the named file.
+func (s *fileTestSuite) readLines(name string) []map[string]interface{} {
+	raw, err := os.Open(name)
+	s.NoError(err)
+	defer raw.Close()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(raw)
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		s.NoError(json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"regexp"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface for an audited location.
+type Location struct {
+	fileSystem *FileSystem
+	location   vfs.Location
+}
+
+// List calls List on the wrapped location.
+func (l *Location) List() ([]string, error) {
+	return l.location.List()
+}
+
+// ListByPrefix calls ListByPrefix on the wrapped location.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	return l.location.ListByPrefix(prefix)
+}
+
+// ListByRegex calls ListByRegex on the wrapped location.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.location.ListByRegex(regex)
+}
+
+// ListDirs returns the names of the directories directly inside the wrapped location, which must implement
+// utils.DirLister.
+func (l *Location) ListDirs() ([]string, error) {
+	return utils.ListDirs(l.location)
+}
+
+// Volume returns the volume of the wrapped location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path returns the path of the wrapped location.
+func (l *Location) Path() string {
+	return l.location.Path()
+}
+
+// Exists calls Exists on the wrapped location.
+func (l *Location) Exists() (bool, error) {
+	return l.location.Exists()
+}
+
+// NewLocation returns a new audit Location relative to this one.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	location, err := l.location.NewLocation(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: l.fileSystem, location: location}, nil
+}
+
+// ChangeDir changes the directory of the wrapped location.
+func (l *Location) ChangeDir(relativePath string) error {
+	return l.location.ChangeDir(relativePath)
+}
+
+// FileSystem returns the audit FileSystem the location was created by.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns an audit File for fileName, relative to the location.
+func (l *Location) NewFile(fileName string) (vfs.File, error) {
+	file, err := l.location.NewFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: l.fileSystem, file: file}, nil
+}
+
+// DeleteFile deletes the file of the given name at the location, recording an OpDelete Event.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
@@ -0,0 +1,202 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// Operations recorded in an Event.
+const (
+	OpWrite  = "write"
+	OpDelete = "delete"
+	OpMove   = "move"
+	OpCopy   = "copy"
+)
+
+// Outcomes of an Event.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event is the audit record of one operation.
+type Event struct {
+	Time time.Time `json:"time"`
+
+	// Op is one of OpWrite, OpDelete, OpMove or OpCopy.
+	Op string `json:"op"`
+
+	// Source is the URI of the file written, deleted, moved or copied, on the wrapped FileSystem, ie: s3://bucket/a.txt
+	Source string `json:"source"`
+
+	// Target is the URI of the file moved or copied to.
+	Target string `json:"target,omitempty"`
+
+	// Size is the number of bytes written, or the size of the file deleted, moved or copied when known.
+	Size uint64 `json:"size"`
+
+	// Duration of the operation, in nanoseconds in JSON.
+	Duration time.Duration `json:"durationNs"`
+
+	// Principal is Options.Principal of the FileSystem, identifying who made the change.
+	Principal string `json:"principal,omitempty"`
+
+	// Outcome is OutcomeSuccess or OutcomeFailure, in which case Error is the error message.
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Sink records audit Events. Record is called synchronously after each operation, so it must be safe for concurrent
+// use. An error from Record is returned to the caller of the operation when the operation itself succeeded, so changes
+// are never silently unaudited.
+type Sink interface {
+	Record(event Event) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(event Event) error
+
+// Record calls fn(event).
+func (fn SinkFunc) Record(event Event) error {
+	return fn(event)
+}
+
+// DefaultMaxEvents is the most events a JSONSink buffers when JSONSinkOptions.MaxEvents isn't set.
+const DefaultMaxEvents = 1000
+
+// DefaultFlushInterval is the longest a JSONSink buffers an event when JSONSinkOptions.FlushInterval isn't set.
+const DefaultFlushInterval = time.Minute
+
+// JSONSinkOptions holds the options of a JSONSink.
+type JSONSinkOptions struct {
+	// Prefix is prepended to the name of each file written, ie: "myjob/" or "myjob-".
+	Prefix string
+
+	// MaxEvents is the most events buffered before they're written. Defaults to DefaultMaxEvents.
+	MaxEvents int
+
+	// FlushInterval is the longest an event is buffered before it's written. Defaults to DefaultFlushInterval.
+	FlushInterval time.Duration
+}
+
+// JSONSink is a Sink that writes Events as lines of JSON to files in a vfs.Location, which may be on any backend.
+//
+// Events are buffered and written as a batch to a new file, named by the time of its first event, so files already
+// written are never read or rewritten. That suits object stores such as s3, which replace a file's content when it's
+// written. A batch is written once it has JSONSinkOptions.MaxEvents events or its first event is
+// JSONSinkOptions.FlushInterval old, and by Flush and Close.
+type JSONSink struct {
+	location vfs.Location
+	options  JSONSinkOptions
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	events int
+	first  time.Time
+	timer  *time.Timer
+	seq    int
+}
+
+// NewJSONSink returns a JSONSink writing files to location.
+func NewJSONSink(location vfs.Location) *JSONSink {
+	return &JSONSink{location: location}
+}
+
+// WithOptions sets options for the JSONSink.
+func (s *JSONSink) WithOptions(opts JSONSinkOptions) *JSONSink {
+	s.options = opts
+	return s
+}
+
+// Record buffers event as a line of JSON, writing the batch if it's full or its first event is FlushInterval old. An
+// error writing the batch is returned, and its events are kept to be written with the next batch.
+func (s *JSONSink) Record(event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := json.NewEncoder(&s.buf).Encode(event); err != nil {
+		return err
+	}
+	if s.events == 0 {
+		s.first = time.Now()
+	}
+	s.events++
+
+	if s.events >= s.maxEvents() || time.Since(s.first) >= s.flushInterval() {
+		return s.flush()
+	}
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.flushInterval()-time.Since(s.first), s.flushLater)
+	}
+	return nil
+}
+
+// Flush writes the buffered events, if any, to a new file.
+func (s *JSONSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+// Close writes the buffered events, if any, to a new file. Recording another event starts a new batch.
+func (s *JSONSink) Close() error {
+	return s.Flush()
+}
+
+// flushLater writes the batch once its first event is FlushInterval old. An error is left for the next Record, Flush
+// or Close to return, since the batch is kept.
+func (s *JSONSink) flushLater() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timer = nil
+	_ = s.flush()
+}
+
+func (s *JSONSink) flush() error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.events == 0 {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s%s-%d.jsonl", s.options.Prefix, s.first.UTC().Format(batchTimeFormat), s.seq)
+	file, err := s.location.NewFile(name)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(s.buf.Bytes()); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	s.buf.Reset()
+	s.events = 0
+	s.seq++
+	return nil
+}
+
+// batchTimeFormat is fixed width so batch files sort by name in the order they were recorded.
+const batchTimeFormat = "20060102T150405.000000000Z"
+
+func (s *JSONSink) maxEvents() int {
+	if s.options.MaxEvents > 0 {
+		return s.options.MaxEvents
+	}
+	return DefaultMaxEvents
+}
+
+func (s *JSONSink) flushInterval() time.Duration {
+	if s.options.FlushInterval > 0 {
+		return s.options.FlushInterval
+	}
+	return DefaultFlushInterval
+}
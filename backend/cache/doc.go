@@ -0,0 +1,53 @@
+/*
+Package cache read-through local cache VFS implementation.
+
+A cache FileSystem wraps another vfs.FileSystem, usually a remote one such as s3 or gs, and keeps the content of files
+it reads in a local directory. Reading the same file again, from any FileSystem using the directory, reads the local
+copy rather than downloading it again.
+
+Usage
+
+Cache filesystems wrap another FileSystem so they are not registered with backend. Wrap a registered backend
+directly:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend"
+      "github.com/c2fo/vfs/v3/backend/cache"
+      _ "github.com/c2fo/vfs/v3/backend/s3"
+  )
+
+  func DoSomething() error {
+      fs := cache.NewFileSystem(backend.Backend("s3")).WithOptions(cache.Options{
+          Dir:     "/var/cache/myjob",
+          MaxSize: 10 << 30, // 10 GiB
+      })
+
+      file, err := fs.NewFile("mybucket", "/reference/zipcodes.csv")
+      if err != nil {
+          return err
+      }
+      defer file.Close()
+      // downloaded on the first run, read from /var/cache/myjob after that
+      reader := csv.NewReader(file)
+      ...
+  }
+
+Validation
+
+Content is cached by file URI and version, so a changed file is never read from the cache. The version is checked
+with a single metadata request when a file is first read or seeked: the ETag for s3, the generation for gs, and the
+modification time and size for other backends.
+
+Eviction
+
+When the content in the cache directory exceeds Options.MaxSize, the least recently used content is removed. The most
+recently downloaded file is always kept, even if it alone exceeds MaxSize. Recency is kept in each cached file's
+modification time, so it survives restarts.
+
+Writing
+
+Writes, deletes and moves go directly to the wrapped FileSystem. Copies to files on the wrapped backend use the
+backend's own copy, and copies elsewhere read from the cache. Cache filesystems use the scheme of the wrapped
+FileSystem, since content is stored as is.
+*/
+package cache
@@ -0,0 +1,242 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for a file on the wrapped FileSystem, reading its content from the cache.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+	cached     *os.File
+}
+
+// Unwrap returns the file on the wrapped FileSystem.
+func (f *File) Unwrap() vfs.File {
+	return f.file
+}
+
+// Info Functions
+
+// LastModified returns the timestamp of the wrapped file.
+func (f *File) LastModified() (*time.Time, error) {
+	return f.file.LastModified()
+}
+
+// Name returns the base name of the file.
+func (f *File) Name() string {
+	return f.file.Name()
+}
+
+// Path returns the full path of the file.
+func (f *File) Path() string {
+	return f.file.Path()
+}
+
+// Exists returns whether the wrapped file exists.
+func (f *File) Exists() (bool, error) {
+	return f.file.Exists()
+}
+
+// Size returns the size of the wrapped file.
+func (f *File) Size() (uint64, error) {
+	return f.file.Size()
+}
+
+// Location returns a cache Location for the directory containing the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		location:   f.file.Location(),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the file to the target file. When the target is on the wrapped backend, the backend's copy is
+// used, which may copy without downloading. Otherwise the content is read from the cache.
+func (f *File) CopyToFile(target vfs.File) error {
+	if t, ok := target.(*File); ok {
+		target = t.file
+	}
+	if target.Location().FileSystem().Scheme() == f.file.Location().FileSystem().Scheme() {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return f.file.CopyToFile(target)
+	}
+
+	if err := utils.TouchCopy(target, f); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := target.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	return f.Close()
+}
+
+// CopyToLocation copies the file to a file of the same name at location, as CopyToFile does.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.CopyToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// MoveToFile copies the file to the target file then deletes the file.
+func (f *File) MoveToFile(target vfs.File) error {
+	if err := f.CopyToFile(target); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation copies the file to location then deletes the file, returning the new file.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := f.CopyToLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	return newFile, f.Delete()
+}
+
+// CRUD Operations
+
+// Delete deletes the wrapped file. Its cached content is no longer used, and is removed once it's the least recently
+// used.
+func (f *File) Delete() error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return f.file.Delete()
+}
+
+// Close closes the cached content and the wrapped file.
+func (f *File) Close() error {
+	if err := f.closeCached(); err != nil {
+		return err
+	}
+	return f.file.Close()
+}
+
+// Read implements the io.Reader interface. The first Read checks the version of the wrapped file, downloading it to
+// the cache if that version isn't already cached, then all reads are from the cached content.
+func (f *File) Read(p []byte) (int, error) {
+	if err := f.openCached(); err != nil {
+		return 0, err
+	}
+	return f.cached.Read(p)
+}
+
+// Seek implements the io.Seeker interface, seeking within the cached content.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if err := f.openCached(); err != nil {
+		return 0, err
+	}
+	return f.cached.Seek(offset, whence)
+}
+
+// Write implements the io.Writer interface, writing to the wrapped file. Once closed, the file has a new version so
+// the next read downloads it again.
+func (f *File) Write(data []byte) (int, error) {
+	if err := f.closeCached(); err != nil {
+		return 0, err
+	}
+	return f.file.Write(data)
+}
+
+/*
+	Private helpers
+*/
+
+// openCached opens the cached content for the current version of the wrapped file, downloading it if needed.
+func (f *File) openCached() error {
+	if f.cached != nil {
+		return nil
+	}
+	cache, err := f.fileSystem.cache()
+	if err != nil {
+		return err
+	}
+	version, err := f.version()
+	if err != nil {
+		return err
+	}
+
+	key := f.file.URI() + "\x00" + version
+	p, ok := cache.get(key)
+	if !ok {
+		// the wrapped file isn't seeked first, since s3 and gs download a file to their own temp file to seek it.
+		// Unread, they stream it straight into the cache with WriteTo instead.
+		p, err = cache.put(key, f.file)
+		if cerr := f.file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	cached, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	f.cached = cached
+	return nil
+}
+
+func (f *File) closeCached() error {
+	if f.cached == nil {
+		return nil
+	}
+	err := f.cached.Close()
+	f.cached = nil
+	return err
+}
+
+// version returns an identifier of the wrapped file's current content: an s3 ETag, a gs generation or, for other
+// backends, its modification time and size.
+func (f *File) version() (string, error) {
+	switch file := f.file.(type) {
+	case interface{ ETag() (string, error) }:
+		return file.ETag()
+	case interface{ Generation() (int64, error) }:
+		generation, err := file.Generation()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(generation, 10), nil
+	}
+
+	modTime, err := f.file.LastModified()
+	if err != nil {
+		return "", err
+	}
+	size, err := f.file.Size()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d", modTime.UnixNano(), size), nil
+}
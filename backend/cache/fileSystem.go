@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// DefaultMaxSize is the size limit of the cache directory when Options.MaxSize isn't set: 1 GiB.
+const DefaultMaxSize = 1 << 30
+
+// Options holds cache-specific options.
+type Options struct {
+	// Dir is the local directory content is cached in. Defaults to a "vfs-cache" directory in os.TempDir(). The
+	// directory may be shared between FileSystems and processes, and cached content is reused across restarts.
+	Dir string `json:"dir,omitempty"`
+
+	// MaxSize is the most content, in bytes, kept in Dir before the least recently used content is removed. Defaults
+	// to DefaultMaxSize and a negative value means no limit.
+	MaxSize int64 `json:"maxSize,omitempty"`
+}
+
+// FileSystem implements vfs.Filesystem by caching the content of files read from another FileSystem in a local
+// directory.
+type FileSystem struct {
+	fileSystem vfs.FileSystem
+	options    Options
+
+	once     sync.Once
+	store    *store
+	storeErr error
+}
+
+// NewFileSystem initializer returns a FileSystem which caches files read from fileSystem.
+func NewFileSystem(fileSystem vfs.FileSystem) *FileSystem {
+	return &FileSystem{fileSystem: fileSystem}
+}
+
+// WithOptions sets options for the FileSystem. Any options that aren't cache.Options are ignored.
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+		fs.once = sync.Once{}
+		fs.store = nil
+		fs.storeErr = nil
+	}
+	return fs
+}
+
+// Unwrap returns the FileSystem whose files are cached.
+func (fs *FileSystem) Unwrap() vfs.FileSystem {
+	return fs.fileSystem
+}
+
+// NewFile function returns the cache implementation of vfs.File.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("cache FileSystem requires a FileSystem")
+	}
+	file, err := fs.fileSystem.NewFile(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: fs, file: file}, nil
+}
+
+// NewLocation function returns the cache implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("cache FileSystem requires a FileSystem")
+	}
+	location, err := fs.fileSystem.NewLocation(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, location: location}, nil
+}
+
+// Name returns the name of the wrapped FileSystem prefixed with "cached", ie: "cached AWS S3"
+func (fs *FileSystem) Name() string {
+	if fs.fileSystem == nil {
+		return "cached"
+	}
+	return "cached " + fs.fileSystem.Name()
+}
+
+// Scheme returns the scheme of the wrapped FileSystem. Content is stored as is, so backends may copy to and from
+// cached files natively.
+func (fs *FileSystem) Scheme() string {
+	if fs.fileSystem == nil {
+		return ""
+	}
+	return fs.fileSystem.Scheme()
+}
+
+// cache returns the store for the cache directory.
+func (fs *FileSystem) cache() (*store, error) {
+	fs.once.Do(func() {
+		dir := fs.options.Dir
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "vfs-cache")
+		}
+		maxSize := fs.options.MaxSize
+		if maxSize == 0 {
+			maxSize = DefaultMaxSize
+		}
+		fs.store, fs.storeErr = openStore(dir, maxSize)
+	})
+	return fs.store, fs.storeErr
+}
@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	srcDir   string
+	cacheDir string
+	fs       *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	var err error
+	s.srcDir, err = ioutil.TempDir("", "cache-src")
+	s.NoError(err)
+	s.cacheDir, err = ioutil.TempDir("", "cache-dir")
+	s.NoError(err)
+	s.fs = NewFileSystem(&_os.FileSystem{}).WithOptions(Options{Dir: s.cacheDir, MaxSize: 100})
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.srcDir))
+	s.NoError(os.RemoveAll(s.cacheDir))
+	stores.Lock()
+	stores.byDir = make(map[string]*store)
+	stores.Unlock()
+}
+
+func (s *fileTestSuite) writeSource(name, content string, modTime time.Time) string {
+	p := filepath.Join(s.srcDir, name)
+	s.NoError(ioutil.WriteFile(p, []byte(content), 0644))
+	s.NoError(os.Chtimes(p, modTime, modTime))
+	return p
+}
+
+func (s *fileTestSuite) read(p string) string {
+	file, err := s.fs.NewFile("", p)
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.NoError(file.Close())
+	return string(contents)
+}
+
+func (s *fileTestSuite) cached() []string {
+	infos, err := ioutil.ReadDir(s.cacheDir)
+	s.NoError(err)
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names
+}
+
+func (s *fileTestSuite) TestReadThrough() {
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	p := s.writeSource("ref.csv", "version 1", modTime)
+
+	s.Equal("version 1", s.read(p))
+	s.Len(s.cached(), 1, "content cached")
+
+	// same version (modification time and size) is read from the cache
+	s.writeSource("ref.csv", "version X", modTime)
+	s.Equal("version 1", s.read(p), "served from cache")
+
+	// a new version is downloaded
+	s.writeSource("ref.csv", "version 2", modTime.Add(time.Minute))
+	s.Equal("version 2", s.read(p), "changed file isn't read from cache")
+	s.Len(s.cached(), 2)
+
+	// cached entries are found after a restart
+	stores.Lock()
+	stores.byDir = make(map[string]*store)
+	stores.Unlock()
+	s.fs = NewFileSystem(&_os.FileSystem{}).WithOptions(Options{Dir: s.cacheDir, MaxSize: 100})
+	s.writeSource("ref.csv", "version Y", modTime.Add(time.Minute))
+	s.Equal("version 2", s.read(p), "served from cache after restart")
+}
+
+func (s *fileTestSuite) TestEviction() {
+	modTime := time.Now().Add(-time.Hour)
+	content := strings.Repeat("x", 40)
+	a := s.writeSource("a.txt", content, modTime)
+	b := s.writeSource("b.txt", content, modTime)
+	c := s.writeSource("c.txt", content, modTime)
+
+	s.read(a)
+	time.Sleep(10 * time.Millisecond)
+	s.read(b)
+	time.Sleep(10 * time.Millisecond)
+	s.read(a) // a is now more recently used than b
+	s.Len(s.cached(), 2)
+
+	s.read(c)
+	s.Len(s.cached(), 2, "least recently used evicted")
+	cache, err := s.fs.cache()
+	s.NoError(err)
+	_, ok := cache.get(s.key(a))
+	s.True(ok, "a kept")
+	_, ok = cache.get(s.key(b))
+	s.False(ok, "b evicted")
+	s.Equal(int64(80), cache.size)
+
+	big := s.writeSource("big.txt", strings.Repeat("y", 150), modTime)
+	s.Equal(150, len(s.read(big)), "files larger than the cache are still read")
+	s.Len(s.cached(), 1, "everything else evicted")
+}
+
+func (s *fileTestSuite) key(p string) string {
+	file, err := s.fs.NewFile("", p)
+	s.NoError(err)
+	version, err := file.(*File).version()
+	s.NoError(err)
+	return file.URI() + "\x00" + version
+}
+
+func (s *fileTestSuite) TestSeekAndWrite() {
+	p := s.writeSource("seek.txt", "0123456789", time.Now().Add(-time.Hour))
+	file, err := s.fs.NewFile("", p)
+	s.NoError(err)
+	_, err = file.Seek(-3, io.SeekEnd)
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("789", string(contents))
+	s.NoError(file.Close())
+
+	_, err = file.Write([]byte("new content"))
+	s.NoError(err)
+	s.NoError(file.Close())
+	raw, err := ioutil.ReadFile(p)
+	s.NoError(err)
+	s.Equal("new content", string(raw), "writes go to the wrapped file")
+	s.Equal("new content", s.read(p))
+}
+
+func (s *fileTestSuite) TestCopy() {
+	p := s.writeSource("copy.txt", "copy me", time.Now().Add(-time.Hour))
+	file, err := s.fs.NewFile("", p)
+	s.NoError(err)
+
+	location, err := s.fs.NewLocation("", filepath.Join(s.srcDir, "out"))
+	s.NoError(err)
+	copied, err := file.CopyToLocation(location)
+	s.NoError(err)
+	s.IsType(&File{}, copied)
+	s.Equal("file://"+filepath.Join(s.srcDir, "out", "copy.txt"), copied.URI())
+	raw, err := ioutil.ReadFile(copied.Path())
+	s.NoError(err)
+	s.Equal("copy me", string(raw))
+	s.Empty(s.cached(), "same backend copies without caching")
+}
+
+type etagFile struct {
+	vfs.File
+	etag string
+}
+
+func (f *etagFile) ETag() (string, error) {
+	return f.etag, nil
+}
+
+func (s *fileTestSuite) TestVersion() {
+	file := &File{fileSystem: s.fs, file: &etagFile{etag: `"abc"`}}
+	version, err := file.version()
+	s.NoError(err)
+	s.Equal(`"abc"`, version, "etag is used when available")
+}
+
+// streamFile is a wrapped file that can only be read with WriteTo, as an s3 or gs file is without downloading it to a
+// temp file first.
+type streamFile struct {
+	etagFile
+	content string
+	closed  int
+}
+
+func (f *streamFile) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, f.content)
+	return int64(n), err
+}
+
+func (f *streamFile) URI() string {
+	return "s3://bucket/stream.txt"
+}
+
+func (f *streamFile) Close() error {
+	f.closed++
+	return nil
+}
+
+func (s *fileTestSuite) TestReadStreamsIntoCache() {
+	wrapped := &streamFile{etagFile: etagFile{etag: `"abc"`}, content: "streamed"}
+	file := &File{fileSystem: s.fs, file: wrapped}
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err, "the wrapped file isn't seeked or read")
+	s.Equal("streamed", string(contents))
+	s.Equal(1, wrapped.closed)
+	s.Len(s.cached(), 1)
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheFileExt is the extension of files in the cache directory, so that unrelated files are never evicted.
+const cacheFileExt = ".vfscache"
+
+// store is an LRU cache of downloaded content in a local directory. Entries are files named by the hash of their key,
+// and recency is kept in each file's modification time so that it survives restarts.
+type store struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+}
+
+// storeEntry is a single cached file.
+type storeEntry struct {
+	name string
+	size int64
+}
+
+// stores holds a store for each cache directory, so that every FileSystem using a directory shares its size limit.
+var stores = struct {
+	sync.Mutex
+	byDir map[string]*store
+}{byDir: make(map[string]*store)}
+
+// openStore returns the store for dir, loading any entries already in it.
+func openStore(dir string, maxSize int64) (*store, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	stores.Lock()
+	defer stores.Unlock()
+	if s, ok := stores.byDir[dir]; ok {
+		s.mu.Lock()
+		s.maxSize = maxSize
+		s.evict("")
+		s.mu.Unlock()
+		return s, nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	// oldest first, so the most recently used end up at the front
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ModTime().Before(infos[j].ModTime()) })
+
+	s := &store{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), cacheFileExt) {
+			continue
+		}
+		s.entries[info.Name()] = s.lru.PushFront(&storeEntry{name: info.Name(), size: info.Size()})
+		s.size += info.Size()
+	}
+	s.evict("")
+
+	stores.byDir[dir] = s
+	return s, nil
+}
+
+// get returns the path of the content cached for key, marking it as recently used.
+func (s *store) get(key string) (string, bool) {
+	name := entryName(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[name]
+	if !ok {
+		return "", false
+	}
+	s.lru.MoveToFront(element)
+
+	p := filepath.Join(s.dir, name)
+	now := time.Now()
+	if err := os.Chtimes(p, now, now); err != nil {
+		// removed by something else, forget it
+		s.remove(element)
+		return "", false
+	}
+	return p, true
+}
+
+// put caches the content of r for key, returning its path. Least recently used entries are evicted to keep the
+// store within its size limit, though the new entry is always kept.
+func (s *store) put(key string, r io.Reader) (string, error) {
+	tmp, err := ioutil.TempFile(s.dir, "download-")
+	if err != nil {
+		return "", err
+	}
+	size, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+
+	name := entryName(key)
+	p := filepath.Join(s.dir, name)
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[name]; ok {
+		// downloaded concurrently, the content is the same
+		s.lru.Remove(element)
+		s.size -= element.Value.(*storeEntry).size
+	}
+	s.entries[name] = s.lru.PushFront(&storeEntry{name: name, size: size})
+	s.size += size
+	s.evict(name)
+	return p, nil
+}
+
+// evict removes least recently used entries, other than keep, until the store is within its size limit.
+func (s *store) evict(keep string) {
+	if s.maxSize <= 0 {
+		return
+	}
+	for element := s.lru.Back(); element != nil && s.size > s.maxSize; {
+		prev := element.Prev()
+		if element.Value.(*storeEntry).name != keep {
+			s.remove(element)
+		}
+		element = prev
+	}
+}
+
+func (s *store) remove(element *list.Element) {
+	e := element.Value.(*storeEntry)
+	_ = os.Remove(filepath.Join(s.dir, e.name))
+	s.lru.Remove(element)
+	delete(s.entries, e.name)
+	s.size -= e.size
+}
+
+func entryName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + cacheFileExt
+}
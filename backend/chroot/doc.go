@@ -0,0 +1,37 @@
+/*
+Package chroot jailed VFS implementation.
+
+A chroot FileSystem exposes the files and locations under a base vfs.Location, on any backend, as a FileSystem of its
+own whose root "/" is the base location. Paths can't resolve outside the root: ".." elements stop at the root, so
+NewLocation("../../") of the root is the root and NewFile("", "../../etc/passwd") is /etc/passwd under the base
+location. The base location is never exposed.
+
+  import(
+      "github.com/c2fo/vfs/v3/backend/chroot"
+      "github.com/c2fo/vfs/v3/backend/readonly"
+      "github.com/c2fo/vfs/v3/vfssimple"
+  )
+
+  func RunPlugin(p Plugin) error {
+      base, err := vfssimple.NewLocation("s3://mybucket/plugins/example/")
+      if err != nil {
+          return err
+      }
+      root, err := chroot.NewRootLocation(base)
+      if err != nil {
+          return err
+      }
+      // the plugin can read and write under s3://mybucket/plugins/example/ only
+      return p.Run(root)
+  }
+
+Wrap the root location with readonly.WrapLocation to also stop the plugin from making changes.
+
+Paths and Scheme
+
+Paths are relative to the root, so a file at s3://mybucket/plugins/example/data/in.csv has a Path() of /data/in.csv.
+The volume is the base location's volume. The scheme is the base location's scheme prefixed with "chroot+", ie:
+chroot+s3://mybucket/data/in.csv. The distinct scheme stops backends from using their native copy with a path
+relative to the root as though it were a real path.
+*/
+package chroot
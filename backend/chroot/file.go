@@ -0,0 +1,132 @@
+package chroot
+
+import (
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for a file under the base location.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+	path       string
+}
+
+// Info Functions
+
+// LastModified returns the timestamp of the file.
+func (f *File) LastModified() (*time.Time, error) {
+	return f.file.LastModified()
+}
+
+// Name returns the base name of the file.
+func (f *File) Name() string {
+	return path.Base(f.path)
+}
+
+// Path returns the full path of the file relative to the base location, ie: "/path/to/file.txt"
+func (f *File) Path() string {
+	return f.path
+}
+
+// Exists returns whether the file exists.
+func (f *File) Exists() (bool, error) {
+	return f.file.Exists()
+}
+
+// Size returns the size of the file.
+func (f *File) Size() (uint64, error) {
+	return f.file.Size()
+}
+
+// Location returns a chroot Location for the directory containing the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		location:   f.file.Location(),
+		path:       utils.EnsureTrailingSlash(path.Dir(f.path)),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the file to the target file.
+func (f *File) CopyToFile(target vfs.File) error {
+	if t, ok := target.(*File); ok {
+		// both are already resolved within their roots, so the backend's copy can be used
+		return f.file.CopyToFile(t.file)
+	}
+	return f.file.CopyToFile(target)
+}
+
+// CopyToLocation copies the file to a file of the same name at location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	if l, ok := location.(*Location); ok {
+		newFile, err := f.file.CopyToLocation(l.location)
+		if err != nil {
+			return nil, err
+		}
+		return l.wrapFile(newFile), nil
+	}
+	return f.file.CopyToLocation(location)
+}
+
+// MoveToFile moves the file to the target file.
+func (f *File) MoveToFile(target vfs.File) error {
+	if t, ok := target.(*File); ok {
+		return f.file.MoveToFile(t.file)
+	}
+	return f.file.MoveToFile(target)
+}
+
+// MoveToLocation moves the file to a file of the same name at location.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	if l, ok := location.(*Location); ok {
+		newFile, err := f.file.MoveToLocation(l.location)
+		if newFile == nil {
+			return nil, err
+		}
+		return l.wrapFile(newFile), err
+	}
+	return f.file.MoveToLocation(location)
+}
+
+// CRUD Operations
+
+// Delete deletes the file.
+func (f *File) Delete() error {
+	return f.file.Delete()
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// Read implements the io.Reader interface.
+func (f *File) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// Seek implements the io.Seeker interface.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Write implements the io.Writer interface.
+func (f *File) Write(data []byte) (int, error) {
+	return f.file.Write(data)
+}
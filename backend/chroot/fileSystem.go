@@ -0,0 +1,91 @@
+package chroot
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// SchemePrefix is prepended to the scheme of the base location's FileSystem, ie: chroot+s3. A distinct scheme stops
+// backends from using their native copy with a jailed path as though it were a real one.
+const SchemePrefix = "chroot+"
+
+// FileSystem implements vfs.Filesystem for the files and locations under a base location. Paths are relative to the
+// base location, which is the root "/", and can't resolve outside it: "/../../etc/passwd" is "/etc/passwd" under the
+// base location. The base location is never exposed.
+type FileSystem struct {
+	base vfs.Location
+}
+
+// NewFileSystem initializer returns a FileSystem rooted at base.
+func NewFileSystem(base vfs.Location) *FileSystem {
+	return &FileSystem{base: base}
+}
+
+// NewRootLocation returns a Location for the root of a FileSystem rooted at base, ie: to hand to code that must be
+// kept under base.
+func NewRootLocation(base vfs.Location) (vfs.Location, error) {
+	return NewFileSystem(base).NewLocation(base.Volume(), "/")
+}
+
+// NewFile function returns the chroot implementation of vfs.File for name, which is relative to the base location.
+// Volume must be empty or the volume of the base location.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if err := fs.checkVolume(volume); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("non-empty string for name is required")
+	}
+	jailed := jailPath(name)
+	if jailed == "/" {
+		return nil, fmt.Errorf("%s is not a file", name)
+	}
+	file, err := fs.base.FileSystem().NewFile(fs.base.Volume(), path.Join(fs.base.Path(), jailed))
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: fs, file: file, path: jailed}, nil
+}
+
+// NewLocation function returns the chroot implementation of vfs.Location for name, which is relative to the base
+// location. Volume must be empty or the volume of the base location.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if err := fs.checkVolume(volume); err != nil {
+		return nil, err
+	}
+	return fs.newLocation(utils.EnsureTrailingSlash(jailPath(name)))
+}
+
+// Name returns the name of the base location's FileSystem prefixed with "chroot", ie: "chroot AWS S3"
+func (fs *FileSystem) Name() string {
+	return "chroot " + fs.base.FileSystem().Name()
+}
+
+// Scheme returns the scheme of the base location's FileSystem prefixed with "chroot+", ie: chroot+s3
+func (fs *FileSystem) Scheme() string {
+	return SchemePrefix + fs.base.FileSystem().Scheme()
+}
+
+// newLocation returns a Location for a jailed location path.
+func (fs *FileSystem) newLocation(jailed string) (*Location, error) {
+	location, err := fs.base.FileSystem().NewLocation(fs.base.Volume(), utils.EnsureTrailingSlash(path.Join(fs.base.Path(), jailed)))
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, location: location, path: jailed}, nil
+}
+
+func (fs *FileSystem) checkVolume(volume string) error {
+	if volume != "" && volume != fs.base.Volume() {
+		return fmt.Errorf("volume %q is outside of the chroot's volume %q", volume, fs.base.Volume())
+	}
+	return nil
+}
+
+// jailPath cleans p as an absolute path, so ".." elements can't go above the root.
+func jailPath(p string) string {
+	return path.Clean("/" + p)
+}
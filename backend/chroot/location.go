@@ -0,0 +1,105 @@
+package chroot
+
+import (
+	"path"
+	"regexp"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface for a location under the base location.
+type Location struct {
+	fileSystem *FileSystem
+	location   vfs.Location
+	path       string
+}
+
+// List returns the names of the files at the location.
+func (l *Location) List() ([]string, error) {
+	return l.location.List()
+}
+
+// ListByPrefix returns the names of the files at the location starting with prefix.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	return l.location.ListByPrefix(prefix)
+}
+
+// ListByRegex returns the names of the files at the location matching regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.location.ListByRegex(regex)
+}
+
+// ListDirs returns the names of the directories directly inside the wrapped location, which must implement
+// utils.DirLister.
+func (l *Location) ListDirs() ([]string, error) {
+	return utils.ListDirs(l.location)
+}
+
+// Volume returns the volume of the base location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path returns the path of the location relative to the base location, with leading and trailing slashes.
+func (l *Location) Path() string {
+	return l.path
+}
+
+// Exists returns whether the location exists.
+func (l *Location) Exists() (bool, error) {
+	return l.location.Exists()
+}
+
+// NewLocation returns a new Location relative to this one. Relative paths can't go above the root.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	return l.fileSystem.newLocation(utils.EnsureTrailingSlash(jailPath(path.Join(l.path, relativePath))))
+}
+
+// ChangeDir changes the location to a path relative to it. Relative paths can't go above the root.
+func (l *Location) ChangeDir(relativePath string) error {
+	location, err := l.fileSystem.newLocation(utils.EnsureTrailingSlash(jailPath(path.Join(l.path, relativePath))))
+	if err != nil {
+		return err
+	}
+	*l = *location
+	return nil
+}
+
+// FileSystem returns the chroot FileSystem the location was created by.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a chroot File for fileName, relative to the location. Relative paths can't go above the root.
+func (l *Location) NewFile(fileName string) (vfs.File, error) {
+	return l.fileSystem.NewFile("", path.Join(l.path, fileName))
+}
+
+// DeleteFile deletes the file of the given name at the location.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
+
+// wrapFile returns a chroot File for a file created by the backend at this location.
+func (l *Location) wrapFile(file vfs.File) *File {
+	return &File{
+		fileSystem: l.fileSystem,
+		file:       file,
+		path:       path.Join(l.path, file.Name()),
+	}
+}
@@ -0,0 +1,118 @@
+package chroot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type locationTestSuite struct {
+	suite.Suite
+	tmpDir string
+	root   vfs.Location
+}
+
+func (s *locationTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "chroot-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.NoError(os.MkdirAll(filepath.Join(dir, "jail", "sub"), 0755))
+	s.NoError(ioutil.WriteFile(filepath.Join(dir, "secret.txt"), []byte("outside"), 0644))
+	s.NoError(ioutil.WriteFile(filepath.Join(dir, "jail", "sub", "in.txt"), []byte("inside"), 0644))
+
+	base, err := (&_os.FileSystem{}).NewLocation("", filepath.Join(dir, "jail"))
+	s.NoError(err)
+	s.root, err = NewRootLocation(base)
+	s.NoError(err)
+}
+
+func (s *locationTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *locationTestSuite) TestCannotEscape() {
+	s.Equal("/", s.root.Path())
+
+	up, err := s.root.NewLocation("../../")
+	s.NoError(err)
+	s.Equal("/", up.Path(), "can't go above the root")
+	names, err := up.List()
+	s.NoError(err)
+	s.NotContains(names, "secret.txt")
+
+	file, err := s.root.NewFile("../secret.txt")
+	s.NoError(err)
+	s.Equal("/secret.txt", file.Path())
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists, "resolves under the root, not to the real secret.txt")
+
+	sub, err := s.root.NewLocation("sub/")
+	s.NoError(err)
+	file, err = sub.NewFile("../../../secret.txt")
+	s.NoError(err)
+	s.Equal("/secret.txt", file.Path())
+
+	s.NoError(sub.ChangeDir("../../.."))
+	s.Equal("/", sub.Path(), "ChangeDir can't go above the root either")
+
+	file, err = s.root.FileSystem().NewFile("", "/../../secret.txt")
+	s.NoError(err)
+	s.Equal("/secret.txt", file.Path())
+	_, err = s.root.FileSystem().NewFile("other-volume", "/a.txt")
+	s.Error(err, "other volumes are outside the root")
+	_, err = s.root.FileSystem().NewFile("", "/..")
+	s.Error(err, "root isn't a file")
+}
+
+func (s *locationTestSuite) TestReadWrite() {
+	sub, err := s.root.NewLocation("sub/")
+	s.NoError(err)
+	s.Equal("/sub/", sub.Path())
+	s.Equal("chroot+file:///sub/", sub.URI())
+
+	file, err := sub.NewFile("in.txt")
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("inside", string(contents))
+	s.NoError(file.Close())
+	s.Equal("/sub/", file.Location().Path())
+	s.Equal(s.root.FileSystem(), file.Location().FileSystem(), "file location stays jailed")
+
+	out, err := s.root.NewFile("out/new.txt")
+	s.NoError(err)
+	_, err = out.Write([]byte("written"))
+	s.NoError(err)
+	s.NoError(out.Close())
+	raw, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "jail", "out", "new.txt"))
+	s.NoError(err)
+	s.Equal("written", string(raw), "written under the base location")
+
+	moved, err := out.MoveToLocation(sub)
+	s.NoError(err)
+	s.Equal("/sub/new.txt", moved.Path())
+	s.Equal(s.root.FileSystem(), moved.Location().FileSystem())
+	names, err := sub.List()
+	s.NoError(err)
+	s.ElementsMatch([]string{"in.txt", "new.txt"}, names)
+
+	s.NoError(sub.DeleteFile("new.txt"))
+	exists, err := moved.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
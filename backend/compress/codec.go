@@ -0,0 +1,120 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec compresses and decompresses file content.
+type Codec interface {
+	// Name returns the name of the compression format, ie: "gzip"
+	Name() string
+
+	// Extension returns the file extension, including the dot, of files compressed with the codec, ie: ".gz"
+	Extension() string
+
+	// NewReader returns a reader of the decompressed content of r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+
+	// NewWriter returns a writer that compresses content written to it to w. The content isn't complete until the
+	// writer is closed.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+var (
+	// Gzip compresses files with gzip at the default level.
+	Gzip Codec = GzipCodec{}
+
+	// Zstd compresses files with zstd at the default level.
+	Zstd Codec = ZstdCodec{}
+)
+
+// Codecs are the codecs chosen by file extension when Options.Codec isn't set.
+var Codecs = []Codec{Gzip, Zstd}
+
+// CodecForName returns the codec in Codecs whose extension matches the end of name, or nil if none do.
+func CodecForName(name string) Codec {
+	for _, codec := range Codecs {
+		if strings.HasSuffix(name, codec.Extension()) {
+			return codec
+		}
+	}
+	return nil
+}
+
+// GzipCodec is a Codec for the gzip format.
+type GzipCodec struct {
+	// Level is a compress/gzip compression level. Zero uses gzip.DefaultCompression.
+	Level int
+}
+
+// Name returns "gzip"
+func (c GzipCodec) Name() string {
+	return "gzip"
+}
+
+// Extension returns ".gz"
+func (c GzipCodec) Extension() string {
+	return ".gz"
+}
+
+// NewReader implements Codec
+func (c GzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// NewWriter implements Codec
+func (c GzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// ZstdCodec is a Codec for the zstd format.
+type ZstdCodec struct {
+	// Level is a zstd compression level, from 1 to 22. Zero uses the default level.
+	Level int
+}
+
+// Name returns "zstd"
+func (c ZstdCodec) Name() string {
+	return "zstd"
+}
+
+// Extension returns ".zst"
+func (c ZstdCodec) Extension() string {
+	return ".zst"
+}
+
+// NewReader implements Codec
+func (c ZstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return zstdReader{decoder}, nil
+}
+
+// NewWriter implements Codec
+func (c ZstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	var opts []zstd.EOption
+	if c.Level != 0 {
+		opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(c.Level)))
+	}
+	return zstd.NewWriter(w, opts...)
+}
+
+// zstdReader adapts zstd.Decoder, whose Close doesn't return an error, to io.ReadCloser.
+type zstdReader struct {
+	*zstd.Decoder
+}
+
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Package compress transparent compression VFS implementation.
+
+A compress FileSystem wraps any other vfs.FileSystem (os, s3, gs, etc) so that file content is compressed as it's
+written to the wrapped FileSystem and decompressed as it's read. gzip and zstd are supported, and other formats can be
+added by implementing Codec.
+
+Usage
+
+Compress filesystems wrap another FileSystem so they are not registered with backend. Wrap a registered backend
+directly:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend"
+      "github.com/c2fo/vfs/v3/backend/compress"
+      _ "github.com/c2fo/vfs/v3/backend/s3"
+  )
+
+  func DoSomething() error {
+      fs := compress.NewFileSystem(backend.Backend("s3"))
+
+      // stored gzipped since the name ends in .gz, but read and written uncompressed
+      file, err := fs.NewFile("mybucket", "/reports/daily.csv.gz")
+      if err != nil {
+          return err
+      }
+      reader := csv.NewReader(file)
+      ...
+  }
+
+By default the codec is chosen by file extension (".gz" for gzip, ".zst" for zstd, see Codecs) and files without a
+matching extension are read and written as is. Set Options.Codec to compress every file with one codec:
+
+  fs := compress.NewFileSystem(backend.Backend("s3")).WithOptions(compress.Options{Codec: compress.Zstd})
+
+To decompress a single file, wrap it:
+
+  gz, _ := vfssimple.NewFile("s3://mybucket/reports/daily.csv.gz")
+  target, _ := vfssimple.NewFile("file:///tmp/daily.csv")
+  err := compress.Wrap(gz, compress.Options{}).CopyToFile(target) // target holds the uncompressed csv
+
+Sizes
+
+Size returns the uncompressed size, which requires decompressing the whole file, while CompressedSize returns the size
+of the stored file.
+
+Copying
+
+Copying to a compress File that uses the same codec copies the compressed content as is, using the wrapped backend's
+native copy where possible. Copying to any other file decompresses, unless Options.CopyCompressed is set in which case
+the compressed content is copied as is.
+
+Writing always replaces the whole file.
+
+Scheme
+
+The scheme of a compress FileSystem is the wrapped scheme prefixed with "compress+", ie:
+compress+s3://mybucket/reports/daily.csv.gz. The distinct scheme stops backends from using their native copy between
+compressed and uncompressed files.
+*/
+package compress
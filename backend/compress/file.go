@@ -0,0 +1,351 @@
+package compress
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for a file stored compressed on the wrapped FileSystem. Files without a codec are
+// read and written as is.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+
+	reader    io.ReadCloser
+	offset    int64
+	size      int64
+	sizeKnown bool
+
+	writer io.WriteCloser
+}
+
+// Unwrap returns the file on the wrapped FileSystem that holds the compressed content.
+func (f *File) Unwrap() vfs.File {
+	return f.file
+}
+
+// Codec returns the codec used for the file, or nil if the file isn't compressed.
+func (f *File) Codec() Codec {
+	return f.fileSystem.codecFor(f.file.Name())
+}
+
+// Info Functions
+
+// LastModified returns the timestamp of the wrapped file.
+func (f *File) LastModified() (*time.Time, error) {
+	return f.file.LastModified()
+}
+
+// Name returns the base name of the file.
+func (f *File) Name() string {
+	return f.file.Name()
+}
+
+// Path returns the full path of the file.
+func (f *File) Path() string {
+	return f.file.Path()
+}
+
+// Exists returns whether the wrapped file exists.
+func (f *File) Exists() (bool, error) {
+	return f.file.Exists()
+}
+
+// Size returns the uncompressed size of the file. The whole file is decompressed to find it, so use CompressedSize
+// when the stored size is enough.
+func (f *File) Size() (uint64, error) {
+	codec := f.Codec()
+	if codec == nil {
+		return f.file.Size()
+	}
+	if f.sizeKnown {
+		return uint64(f.size), nil
+	}
+
+	// decompressing moves the wrapped file's cursor, so any open reader must be reopened at its offset
+	if err := f.closeReader(); err != nil {
+		return 0, err
+	}
+	reader, err := f.openReader(codec)
+	if err != nil {
+		return 0, err
+	}
+	size, err := io.Copy(ioutil.Discard, reader)
+	if cerr := reader.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	f.size = size
+	f.sizeKnown = true
+	return uint64(size), nil
+}
+
+// CompressedSize returns the size of the file as stored on the wrapped FileSystem.
+func (f *File) CompressedSize() (uint64, error) {
+	return f.file.Size()
+}
+
+// Location returns a compress Location for the directory containing the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		location:   f.file.Location(),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the file to the target file. When the target is a compress File using the same codec, the
+// compressed content is copied as is. Otherwise the decompressed content is copied, unless Options.CopyCompressed is
+// set and the target isn't a compress File.
+func (f *File) CopyToFile(target vfs.File) error {
+	if t, ok := target.(*File); ok && sameCodec(f.Codec(), t.Codec()) {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return f.file.CopyToFile(t.file)
+	}
+	if _, ok := target.(*File); !ok && f.fileSystem.options.CopyCompressed {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return f.file.CopyToFile(target)
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// write even when there's no content so the target is always created
+	if _, err := target.Write([]byte{}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(target, f); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := target.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	return f.Close()
+}
+
+// CopyToLocation copies the file to a file of the same name at location, as CopyToFile does.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	if _, ok := location.(*Location); !ok && f.fileSystem.options.CopyCompressed {
+		if err := f.Close(); err != nil {
+			return nil, err
+		}
+		return f.file.CopyToLocation(location)
+	}
+
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.CopyToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// MoveToFile copies the file to the target file then deletes the file.
+func (f *File) MoveToFile(target vfs.File) error {
+	if err := f.CopyToFile(target); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation copies the file to location then deletes the file, returning the new file.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := f.CopyToLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	return newFile, f.Delete()
+}
+
+// CRUD Operations
+
+// Delete deletes the wrapped file, discarding anything written but not yet closed.
+func (f *File) Delete() error {
+	f.writer = nil
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return f.file.Delete()
+}
+
+// Close finishes compressing anything written, closes any reader and closes the wrapped file.
+func (f *File) Close() error {
+	if f.writer != nil {
+		err := f.writer.Close()
+		f.writer = nil
+		if err != nil {
+			return err
+		}
+	}
+	if err := f.closeReader(); err != nil {
+		return err
+	}
+	f.offset = 0
+	f.sizeKnown = false
+	return f.file.Close()
+}
+
+// Read implements the io.Reader interface, returning the decompressed content of the file.
+func (f *File) Read(p []byte) (int, error) {
+	codec := f.Codec()
+	if codec == nil {
+		return f.file.Read(p)
+	}
+	if f.writer != nil {
+		return 0, errors.New("file must be closed after writing before it can be read")
+	}
+
+	if f.reader == nil {
+		reader, err := f.openReader(codec)
+		if err != nil {
+			return 0, err
+		}
+		if f.offset > 0 {
+			if _, err := io.CopyN(ioutil.Discard, reader, f.offset); err != nil && err != io.EOF {
+				_ = reader.Close()
+				return 0, err
+			}
+		}
+		f.reader = reader
+	}
+
+	n, err := f.reader.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Seek implements the io.Seeker interface. Compressed content can't be randomly accessed, so seeking forward
+// decompresses up to the new offset and seeking backward decompresses again from the start.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.Codec() == nil {
+		return f.file.Seek(offset, whence)
+	}
+	if f.writer != nil {
+		return 0, errors.New("file must be closed after writing before it can be seeked")
+	}
+
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.offset + offset
+	case io.SeekEnd:
+		size, err := f.Size()
+		if err != nil {
+			return 0, err
+		}
+		newOffset = int64(size) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence value %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, errors.New("seek to a negative offset")
+	}
+
+	if f.reader != nil && newOffset > f.offset {
+		if _, err := io.CopyN(ioutil.Discard, f.reader, newOffset-f.offset); err != nil && err != io.EOF {
+			return 0, err
+		}
+	} else if newOffset != f.offset {
+		if err := f.closeReader(); err != nil {
+			return 0, err
+		}
+	}
+	f.offset = newOffset
+	return newOffset, nil
+}
+
+// Write implements the io.Writer interface, compressing content as it's written to the wrapped file. The compressed
+// content is complete once Close is called. Writing always replaces the whole file, so it must start at offset 0.
+func (f *File) Write(data []byte) (int, error) {
+	codec := f.Codec()
+	if codec == nil {
+		return f.file.Write(data)
+	}
+
+	if f.writer == nil {
+		if f.offset != 0 {
+			return 0, errors.New("compressed files can only be written from the start")
+		}
+		if err := f.startWrite(codec); err != nil {
+			return 0, err
+		}
+	}
+	return f.writer.Write(data)
+}
+
+/*
+	Private helpers
+*/
+
+// openReader returns a reader of the decompressed content from the start of the wrapped file. Empty files are read as
+// empty content.
+func (f *File) openReader(codec Codec) (io.ReadCloser, error) {
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader, err := codec.NewReader(f.file)
+	if err == io.EOF {
+		return ioutil.NopCloser(&io.LimitedReader{}), nil
+	}
+	return reader, err
+}
+
+func (f *File) closeReader() error {
+	if f.reader == nil {
+		return nil
+	}
+	err := f.reader.Close()
+	f.reader = nil
+	return err
+}
+
+// startWrite replaces the wrapped file with a new compressed stream.
+func (f *File) startWrite(codec Codec) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	writer, err := codec.NewWriter(f.file)
+	if err != nil {
+		return err
+	}
+	f.writer = writer
+	return nil
+}
+
+func sameCodec(a, b Codec) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Name() == b.Name()
+}
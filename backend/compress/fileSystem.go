@@ -0,0 +1,103 @@
+package compress
+
+import (
+	"errors"
+	"path"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// SchemePrefix is prepended to the scheme of the wrapped FileSystem, ie: compress+s3. A distinct scheme stops backends
+// from copying between compressed and uncompressed files with their native copy.
+const SchemePrefix = "compress+"
+
+// Options holds compress-specific options.
+type Options struct {
+	// Codec compresses every file when set. Otherwise the codec is chosen by file extension (see Codecs) and files
+	// without a matching extension are read and written as is.
+	Codec Codec `json:"-"`
+
+	// CopyCompressed makes CopyToFile and CopyToLocation copy the compressed content as is when the target isn't a
+	// compress File, rather than decompressing it.
+	CopyCompressed bool `json:"copyCompressed,omitempty"`
+}
+
+// FileSystem implements vfs.Filesystem by compressing the content of files stored on another FileSystem.
+type FileSystem struct {
+	fileSystem vfs.FileSystem
+	options    Options
+}
+
+// NewFileSystem initializer returns a FileSystem which compresses files stored on fileSystem.
+func NewFileSystem(fileSystem vfs.FileSystem) *FileSystem {
+	return &FileSystem{fileSystem: fileSystem}
+}
+
+// Wrap returns a compress File for an existing file, ie: to decompress a .gz file as it's read or copied.
+func Wrap(file vfs.File, opts Options) *File {
+	return &File{
+		fileSystem: NewFileSystem(file.Location().FileSystem()).WithOptions(opts),
+		file:       file,
+	}
+}
+
+// WithOptions sets options for the FileSystem. Any options that aren't compress.Options are ignored.
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+	}
+	return fs
+}
+
+// Unwrap returns the FileSystem that compressed files are stored on.
+func (fs *FileSystem) Unwrap() vfs.FileSystem {
+	return fs.fileSystem
+}
+
+// NewFile function returns the compress implementation of vfs.File.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("compress FileSystem requires a FileSystem")
+	}
+	file, err := fs.fileSystem.NewFile(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: fs, file: file}, nil
+}
+
+// NewLocation function returns the compress implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("compress FileSystem requires a FileSystem")
+	}
+	location, err := fs.fileSystem.NewLocation(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, location: location}, nil
+}
+
+// Name returns the name of the wrapped FileSystem prefixed with "compressed", ie: "compressed AWS S3"
+func (fs *FileSystem) Name() string {
+	if fs.fileSystem == nil {
+		return "compressed"
+	}
+	return "compressed " + fs.fileSystem.Name()
+}
+
+// Scheme returns the scheme of the wrapped FileSystem prefixed with "compress+", ie: compress+s3
+func (fs *FileSystem) Scheme() string {
+	if fs.fileSystem == nil {
+		return SchemePrefix
+	}
+	return SchemePrefix + fs.fileSystem.Scheme()
+}
+
+// codecFor returns the codec for the file name, or nil when the file isn't compressed.
+func (fs *FileSystem) codecFor(name string) Codec {
+	if fs.options.Codec != nil {
+		return fs.options.Codec
+	}
+	return CodecForName(path.Base(name))
+}
@@ -0,0 +1,192 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir string
+	fs     *FileSystem
+}
+
+var csvContent = strings.Repeat("id,name,amount\n1,widget,9.99\n", 100)
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "compress-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.fs = NewFileSystem(&_os.FileSystem{})
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) writeFile(fs *FileSystem, name, content string) vfs.File {
+	file, err := fs.NewFile("", filepath.Join(s.tmpDir, name))
+	s.NoError(err)
+	_, err = file.Write([]byte(content))
+	s.NoError(err)
+	s.NoError(file.Close())
+	return file
+}
+
+func (s *fileTestSuite) TestCodecByExtension() {
+	for name, codec := range map[string]Codec{"data.csv.gz": Gzip, "data.csv.zst": Zstd, "data.csv": nil} {
+		file := s.writeFile(s.fs, name, csvContent)
+		s.Equal(codec, file.(*File).Codec(), name)
+
+		raw, err := ioutil.ReadFile(filepath.Join(s.tmpDir, name))
+		s.NoError(err)
+		if codec == nil {
+			s.Equal(csvContent, string(raw), "stored as is")
+		} else {
+			s.True(len(raw) < len(csvContent), "%s is stored compressed", name)
+		}
+
+		size, err := file.Size()
+		s.NoError(err)
+		s.Equal(uint64(len(csvContent)), size, "%s uncompressed size", name)
+		compressedSize, err := file.(*File).CompressedSize()
+		s.NoError(err)
+		s.Equal(uint64(len(raw)), compressedSize, "%s compressed size", name)
+
+		contents, err := ioutil.ReadAll(file)
+		s.NoError(err)
+		s.Equal(csvContent, string(contents), name)
+		s.NoError(file.Close())
+	}
+}
+
+func (s *fileTestSuite) TestConfiguredCodec() {
+	fs := NewFileSystem(&_os.FileSystem{}).WithOptions(Options{Codec: GzipCodec{Level: gzip.BestCompression}})
+	s.writeFile(fs, "data.csv", csvContent)
+
+	f, err := os.Open(filepath.Join(s.tmpDir, "data.csv"))
+	s.NoError(err)
+	defer f.Close()
+	reader, err := gzip.NewReader(f)
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(reader)
+	s.NoError(err)
+	s.Equal(csvContent, string(contents), "compressed regardless of extension")
+
+	empty := s.writeFile(fs, "empty.csv", "")
+	contents, err = ioutil.ReadAll(empty)
+	s.NoError(err)
+	s.Empty(contents)
+
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "zero.csv"), nil, 0644))
+	zero, err := fs.NewFile("", filepath.Join(s.tmpDir, "zero.csv"))
+	s.NoError(err)
+	size, err := zero.Size()
+	s.NoError(err)
+	s.Zero(size, "zero length file reads as empty")
+}
+
+func (s *fileTestSuite) TestSeek() {
+	file := s.writeFile(s.fs, "seek.csv.zst", csvContent)
+	buf := make([]byte, 10)
+
+	for _, offset := range []int64{100, 20, 500} {
+		_, err := file.Seek(offset, io.SeekStart)
+		s.NoError(err)
+		_, err = io.ReadFull(file, buf)
+		s.NoError(err)
+		s.Equal(csvContent[offset:offset+10], string(buf), "read at %d", offset)
+	}
+
+	_, err := file.Seek(-5, io.SeekEnd)
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal(csvContent[len(csvContent)-5:], string(contents), "seek from end")
+
+	_, err = file.Seek(4, io.SeekStart)
+	s.NoError(err)
+	_, err = file.Write([]byte("x"))
+	s.Error(err, "writes must start at 0")
+	s.NoError(file.Close())
+}
+
+func (s *fileTestSuite) TestCopy() {
+	file := s.writeFile(s.fs, "copy.csv.gz", csvContent)
+	raw, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "copy.csv.gz"))
+	s.NoError(err)
+
+	osFs := &_os.FileSystem{}
+	decompressed, err := osFs.NewFile("", filepath.Join(s.tmpDir, "out", "copy.csv"))
+	s.NoError(err)
+	s.NoError(file.CopyToFile(decompressed))
+	contents, err := ioutil.ReadFile(decompressed.Path())
+	s.NoError(err)
+	s.Equal(csvContent, string(contents), "decompressed by default")
+
+	same, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "same", "copy.csv.gz"))
+	s.NoError(err)
+	s.NoError(file.CopyToFile(same))
+	contents, err = ioutil.ReadFile(same.Path())
+	s.NoError(err)
+	s.True(bytes.Equal(raw, contents), "same codec copies compressed content as is")
+
+	recompressed, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "zstd", "copy.csv.zst"))
+	s.NoError(err)
+	s.NoError(file.CopyToFile(recompressed))
+	contents, err = ioutil.ReadAll(recompressed)
+	s.NoError(err)
+	s.Equal(csvContent, string(contents), "recompressed with target codec")
+
+	location, err := osFs.NewLocation("", filepath.Join(s.tmpDir, "raw"))
+	s.NoError(err)
+	wrapped := Wrap(file.(*File).Unwrap(), Options{CopyCompressed: true})
+	copied, err := wrapped.CopyToLocation(location)
+	s.NoError(err)
+	contents, err = ioutil.ReadFile(copied.Path())
+	s.NoError(err)
+	s.True(bytes.Equal(raw, contents), "compressed content copied as is")
+}
+
+func (s *fileTestSuite) TestWrap() {
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "plain.txt"), []byte("hello"), 0644))
+	gzFile := s.writeFile(s.fs, "hello.txt.gz", "hello")
+
+	plain, err := (&_os.FileSystem{}).NewFile("", filepath.Join(s.tmpDir, "plain.txt"))
+	s.NoError(err)
+	wrapped := Wrap(plain, Options{})
+	s.Nil(wrapped.Codec(), "no codec for .txt")
+	contents, err := ioutil.ReadAll(wrapped)
+	s.NoError(err)
+	s.Equal("hello", string(contents))
+
+	wrapped = Wrap(gzFile.(*File).Unwrap(), Options{})
+	s.Equal("compress+file://"+filepath.Join(s.tmpDir, "hello.txt.gz"), wrapped.URI())
+	s.Equal("compressed os", wrapped.Location().FileSystem().Name())
+	contents, err = ioutil.ReadAll(wrapped)
+	s.NoError(err)
+	s.Equal("hello", string(contents))
+	s.NoError(wrapped.Delete())
+	exists, err := gzFile.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
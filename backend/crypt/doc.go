@@ -0,0 +1,69 @@
+/*
+Package crypt client-side encryption VFS implementation.
+
+A crypt FileSystem wraps any other vfs.FileSystem (os, s3, gs, etc) so that file content is encrypted before it's
+written to the wrapped FileSystem and decrypted as it's read, using AES-GCM with keys from a pluggable KeyProvider.
+
+Usage
+
+Crypt filesystems need a KeyProvider so they are not registered with backend. Wrap a registered backend directly:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend"
+      "github.com/c2fo/vfs/v3/backend/crypt"
+      _ "github.com/c2fo/vfs/v3/backend/s3"
+  )
+
+  func DoSomething() error {
+      keys, err := crypt.NewStaticKeyProvider("2019-06", key) // key is 32 bytes for AES-256
+      if err != nil {
+          return err
+      }
+
+      fs := crypt.NewFileSystem(backend.Backend("s3"), keys)
+
+      file, err := fs.NewFile("mybucket", "/pii/customers.csv")
+      if err != nil {
+          return err
+      }
+      // content is encrypted as it's written, and the final chunk is written on Close
+      if _, err := file.Write(data); err != nil {
+          return err
+      }
+      return file.Close()
+  }
+
+Copying a crypt File to a File on any other FileSystem writes the decrypted content, and copying any File to a crypt
+File encrypts it.
+
+Format
+
+Files are encrypted in chunks (64 KiB of plaintext by default, see Options.ChunkSize) each sealed with AES-GCM, so
+reading any part of a file only requires decrypting the chunks containing it, and Seek is supported. Each file has a
+header recording the ID of the key it was encrypted with and its chunk size. Chunks are bound to their position and
+the final chunk is marked, so reordered, truncated or extended files fail to decrypt rather than returning partial
+content. Empty content is still written as a header and a sealed final chunk, so a file truncated to nothing fails
+too.
+
+Writing always replaces the whole file.
+
+Keys
+
+A KeyProvider returns the key used for new files and looks up keys by ID for reading. StaticKeyProvider holds keys in
+memory; use Rotate to start encrypting with a new key while keeping older keys for reading. Implement KeyProvider to
+fetch keys from a secret store or KMS.
+
+File Names
+
+Setting Options.NameKey also encrypts file names, deterministically so that files can be found by name. Location paths
+(directories and prefixes) are not encrypted. When names are encrypted, List, ListByPrefix and ListByRegex return
+decrypted names and leave out files whose names weren't encrypted with NameKey.
+
+  fs := crypt.NewFileSystem(backend.Backend("s3"), keys).WithOptions(crypt.Options{NameKey: nameKey})
+
+Scheme
+
+The scheme of a crypt FileSystem is the wrapped scheme prefixed with "crypt+", ie: crypt+s3://mybucket/pii/customers.csv.
+The distinct scheme stops backends from using their native copy between encrypted and unencrypted files.
+*/
+package crypt
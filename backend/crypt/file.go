@@ -0,0 +1,344 @@
+package crypt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for an encrypted file stored on the wrapped FileSystem.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+	name       string
+
+	// read state
+	loaded     bool
+	header     *header
+	fileSize   int64
+	offset     int64
+	chunk      []byte
+	chunkIndex int64
+
+	// write state
+	writeHeader *header
+	writeIndex  int64
+	writeBuffer []byte
+}
+
+// Unwrap returns the file on the wrapped FileSystem that holds the encrypted content.
+func (f *File) Unwrap() vfs.File {
+	return f.file
+}
+
+// Info Functions
+
+// LastModified returns the timestamp of the wrapped file.
+func (f *File) LastModified() (*time.Time, error) {
+	return f.file.LastModified()
+}
+
+// Name returns the decrypted base name of the file. IE: "file.txt" of "crypt+s3://bucket/some/path/to/file.txt
+func (f *File) Name() string {
+	return f.name
+}
+
+// Path returns the full path of the file with its decrypted name. IE: "/some/path/to/file.txt" of
+// "crypt+s3://bucket/some/path/to/file.txt
+func (f *File) Path() string {
+	return path.Join(f.file.Location().Path(), f.name)
+}
+
+// Exists returns whether the wrapped file exists.
+func (f *File) Exists() (bool, error) {
+	return f.file.Exists()
+}
+
+// Size returns the size of the decrypted content, which is calculated from the size of the wrapped file.
+func (f *File) Size() (uint64, error) {
+	if err := f.loadHeader(); err != nil {
+		return 0, err
+	}
+	return uint64(f.header.plaintextSize(f.fileSize)), nil
+}
+
+// Location returns a crypt Location for the directory containing the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		location:   f.file.Location(),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the decrypted content of the file to the target file, which encrypts it again when the target is
+// also a crypt File.
+func (f *File) CopyToFile(target vfs.File) error {
+	if err := utils.TouchCopy(target, f); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := target.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	return f.Close()
+}
+
+// CopyToLocation copies the file to a file of the same name at location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.CopyToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// MoveToFile copies the file to the target file then deletes the file.
+func (f *File) MoveToFile(target vfs.File) error {
+	if err := f.CopyToFile(target); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation copies the file to location then deletes the file, returning the new file.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := f.CopyToLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	return newFile, f.Delete()
+}
+
+// CRUD Operations
+
+// Delete deletes the wrapped file, discarding anything written but not yet closed.
+func (f *File) Delete() error {
+	f.writeHeader = nil
+	f.writeBuffer = nil
+	f.resetRead()
+	return f.file.Delete()
+}
+
+// Close encrypts and writes anything still buffered by Write, then closes the wrapped file.
+func (f *File) Close() error {
+	if f.writeHeader != nil {
+		err := f.writeChunk(f.writeBuffer, true)
+		f.writeHeader = nil
+		f.writeBuffer = nil
+		if err != nil {
+			return err
+		}
+	}
+	f.resetRead()
+	return f.file.Close()
+}
+
+// Read implements the io.Reader interface, decrypting the wrapped file one chunk at a time.
+func (f *File) Read(p []byte) (int, error) {
+	if f.writeHeader != nil {
+		return 0, errors.New("file must be closed after writing before it can be read")
+	}
+	if err := f.loadHeader(); err != nil {
+		return 0, err
+	}
+	if f.offset >= f.header.plaintextSize(f.fileSize) {
+		return 0, io.EOF
+	}
+
+	index := f.offset / f.header.chunkSize
+	if f.chunk == nil || index != f.chunkIndex {
+		if err := f.readChunk(index); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, f.chunk[f.offset-index*f.header.chunkSize:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+// Seek implements the io.Seeker interface. Only the chunk containing the new offset is decrypted by the next Read.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.writeHeader != nil {
+		return 0, errors.New("file must be closed after writing before it can be seeked")
+	}
+
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.offset + offset
+	case io.SeekEnd:
+		size, err := f.Size()
+		if err != nil {
+			return 0, err
+		}
+		newOffset = int64(size) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence value %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, errors.New("seek to a negative offset")
+	}
+	f.offset = newOffset
+	return newOffset, nil
+}
+
+// Write implements the io.Writer interface. Content is encrypted and written to the wrapped file a chunk at a time,
+// with the final chunk written by Close. Writing always replaces the whole file, so it must start at offset 0.
+func (f *File) Write(data []byte) (int, error) {
+	if f.writeHeader == nil {
+		if f.offset != 0 {
+			return 0, errors.New("encrypted files can only be written from the start")
+		}
+		if err := f.startWrite(); err != nil {
+			return 0, err
+		}
+	}
+
+	f.writeBuffer = append(f.writeBuffer, data...)
+	chunkSize := int(f.writeHeader.chunkSize)
+	// a full chunk is only written once more data follows it, since the final chunk must be marked as such
+	for len(f.writeBuffer) > chunkSize {
+		if err := f.writeChunk(f.writeBuffer[:chunkSize], false); err != nil {
+			return 0, err
+		}
+		f.writeBuffer = append(f.writeBuffer[:0], f.writeBuffer[chunkSize:]...)
+	}
+	return len(data), nil
+}
+
+/*
+	Private helpers
+*/
+
+// loadHeader reads the header of the wrapped file, if it hasn't been already. Even empty content is written as a header
+// and a sealed final chunk, so an empty wrapped file has been truncated.
+func (f *File) loadHeader() error {
+	if f.loaded {
+		return nil
+	}
+
+	size, err := f.file.Size()
+	if err != nil {
+		return err
+	}
+	if size == 0 {
+		return fmt.Errorf("unable to read %s: file is truncated", f)
+	}
+
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	h, err := readHeader(f.file, f.fileSystem.keys)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %s", f, err)
+	}
+	chunks := h.chunkCount(int64(size))
+	if chunks == 0 || int64(size)-h.chunkOffset(chunks-1) < tagSize {
+		return fmt.Errorf("unable to read %s: file is truncated", f)
+	}
+
+	f.header = h
+	f.fileSize = int64(size)
+	f.loaded = true
+	return nil
+}
+
+// readChunk decrypts the chunk with the given index.
+func (f *File) readChunk(index int64) error {
+	final := index == f.header.chunkCount(f.fileSize)-1
+	length := f.header.chunkSize + tagSize
+	if final {
+		length = f.fileSize - f.header.chunkOffset(index)
+	}
+
+	if _, err := f.file.Seek(f.header.chunkOffset(index), io.SeekStart); err != nil {
+		return err
+	}
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(f.file, sealed); err != nil {
+		return err
+	}
+	chunk, err := f.header.open(index, sealed, final)
+	if err != nil {
+		return err
+	}
+
+	f.chunk = chunk
+	f.chunkIndex = index
+	return nil
+}
+
+// startWrite replaces the wrapped file with a new header.
+func (f *File) startWrite() error {
+	f.resetRead()
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	h, err := newHeader(f.fileSystem.keys, f.fileSystem.chunkSize())
+	if err != nil {
+		return err
+	}
+	if err := f.writeAll(h.raw); err != nil {
+		return err
+	}
+	f.writeHeader = h
+	f.writeIndex = 0
+	return nil
+}
+
+// writeChunk encrypts plaintext as the next chunk and writes it to the wrapped file.
+func (f *File) writeChunk(plaintext []byte, final bool) error {
+	sealed, err := f.writeHeader.seal(f.writeIndex, plaintext, final)
+	if err != nil {
+		return err
+	}
+	if err := f.writeAll(sealed); err != nil {
+		return err
+	}
+	f.writeIndex++
+	return nil
+}
+
+func (f *File) writeAll(data []byte) error {
+	n, err := f.file.Write(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return io.ErrShortWrite
+	}
+	return nil
+}
+
+func (f *File) resetRead() {
+	f.loaded = false
+	f.header = nil
+	f.fileSize = 0
+	f.offset = 0
+	f.chunk = nil
+	f.chunkIndex = 0
+}
@@ -0,0 +1,137 @@
+package crypt
+
+import (
+	"errors"
+	"path"
+	"sync"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// SchemePrefix is prepended to the scheme of the wrapped FileSystem, ie: crypt+s3. A distinct scheme stops backends
+// from copying between encrypted and unencrypted files with their native copy, which would skip encryption.
+const SchemePrefix = "crypt+"
+
+// Options holds crypt-specific options.
+type Options struct {
+	// ChunkSize is the amount of plaintext encrypted in each chunk, and so the most that's decrypted to read any
+	// part of a file. Defaults to DefaultChunkSize. Files record their chunk size so it can be changed at any time.
+	ChunkSize int `json:"chunkSize,omitempty"`
+
+	// NameKey enables file name encryption when set. File names are encrypted with a key derived from NameKey while
+	// location paths are left as is. Names are encrypted deterministically so files can still be found by name, which
+	// means NameKey can't be rotated without renaming every file.
+	NameKey []byte `json:"-"`
+}
+
+// FileSystem implements vfs.Filesystem by encrypting the content, and optionally the names, of files stored on
+// another FileSystem.
+type FileSystem struct {
+	fileSystem vfs.FileSystem
+	keys       KeyProvider
+	options    Options
+
+	once       sync.Once
+	names      *nameCipher
+	namesError error
+}
+
+// NewFileSystem initializer returns a FileSystem which encrypts files stored on fileSystem with keys from keys.
+func NewFileSystem(fileSystem vfs.FileSystem, keys KeyProvider) *FileSystem {
+	return &FileSystem{fileSystem: fileSystem, keys: keys}
+}
+
+// WithOptions sets options for the FileSystem. Any options that aren't crypt.Options are ignored.
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+		fs.once = sync.Once{}
+		fs.names = nil
+		fs.namesError = nil
+	}
+	return fs
+}
+
+// Unwrap returns the FileSystem that encrypted files are stored on.
+func (fs *FileSystem) Unwrap() vfs.FileSystem {
+	return fs.fileSystem
+}
+
+// NewFile function returns the crypt implementation of vfs.File, for the file stored on the wrapped FileSystem at
+// name (with an encrypted file name when name encryption is enabled).
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if err := fs.validate(); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, errors.New("non-empty string for name is required")
+	}
+	names, err := fs.nameCipher()
+	if err != nil {
+		return nil, err
+	}
+	file, err := fs.fileSystem.NewFile(volume, names.encryptFilePath(name))
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		fileSystem: fs,
+		file:       file,
+		name:       path.Base(name),
+	}, nil
+}
+
+// NewLocation function returns the crypt implementation of vfs.Location. Location paths are never encrypted.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if err := fs.validate(); err != nil {
+		return nil, err
+	}
+	location, err := fs.fileSystem.NewLocation(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{
+		fileSystem: fs,
+		location:   location,
+	}, nil
+}
+
+// Name returns the name of the wrapped FileSystem prefixed with "encrypted", ie: "encrypted AWS S3"
+func (fs *FileSystem) Name() string {
+	if fs.fileSystem == nil {
+		return "encrypted"
+	}
+	return "encrypted " + fs.fileSystem.Name()
+}
+
+// Scheme returns the scheme of the wrapped FileSystem prefixed with "crypt+", ie: crypt+s3
+func (fs *FileSystem) Scheme() string {
+	if fs.fileSystem == nil {
+		return SchemePrefix
+	}
+	return SchemePrefix + fs.fileSystem.Scheme()
+}
+
+func (fs *FileSystem) chunkSize() int {
+	if fs.options.ChunkSize > 0 {
+		return fs.options.ChunkSize
+	}
+	return DefaultChunkSize
+}
+
+// nameCipher returns the cipher for file names, or nil when names aren't encrypted.
+func (fs *FileSystem) nameCipher() (*nameCipher, error) {
+	fs.once.Do(func() {
+		if len(fs.options.NameKey) > 0 {
+			fs.names, fs.namesError = newNameCipher(fs.options.NameKey)
+		}
+	})
+	return fs.names, fs.namesError
+}
+
+func (fs *FileSystem) validate() error {
+	if fs.fileSystem == nil || fs.keys == nil {
+		return errors.New("crypt FileSystem requires a FileSystem and KeyProvider")
+	}
+	return nil
+}
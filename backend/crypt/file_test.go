@@ -0,0 +1,209 @@
+package crypt
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir string
+	keys   *StaticKeyProvider
+	fs     *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "crypt-test")
+	s.NoError(err)
+	s.tmpDir = dir
+
+	s.keys, err = NewStaticKeyProvider("key-1", bytes.Repeat([]byte{1}, 32))
+	s.NoError(err)
+	s.fs = NewFileSystem(&_os.FileSystem{}, s.keys).WithOptions(Options{ChunkSize: 16})
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) writeFile(name, content string) vfs.File {
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, name))
+	s.NoError(err)
+	_, err = file.Write([]byte(content))
+	s.NoError(err)
+	s.NoError(file.Close())
+	return file
+}
+
+func (s *fileTestSuite) TestRoundTrip() {
+	for _, content := range []string{"", "short", "exactly 16 bytes", "content spanning several sixteen byte chunks"} {
+		file := s.writeFile("roundtrip.txt", content)
+
+		raw, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "roundtrip.txt"))
+		s.NoError(err)
+		if content != "" {
+			s.NotContains(string(raw), content, "content is encrypted")
+		}
+
+		size, err := file.Size()
+		s.NoError(err)
+		s.Equal(uint64(len(content)), size, "plaintext size")
+
+		contents, err := ioutil.ReadAll(file)
+		s.NoError(err)
+		s.Equal(content, string(contents))
+		s.NoError(file.Close())
+	}
+}
+
+func (s *fileTestSuite) TestSeek() {
+	content := "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJ"
+	file := s.writeFile("seek.txt", content)
+
+	for _, offset := range []int64{0, 5, 16, 31, 40} {
+		pos, err := file.Seek(offset, io.SeekStart)
+		s.NoError(err)
+		s.Equal(offset, pos)
+		buf := make([]byte, 4)
+		n, err := io.ReadFull(file, buf)
+		s.NoError(err)
+		s.Equal(content[offset:offset+int64(n)], string(buf), "read at offset %d", offset)
+	}
+
+	_, err := file.Seek(-6, io.SeekEnd)
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("EFGHIJ", string(contents), "seek from end")
+
+	_, err = file.Seek(-1, io.SeekStart)
+	s.Error(err, "negative offset")
+	s.NoError(file.Close())
+}
+
+func (s *fileTestSuite) TestTampering() {
+	content := "content spanning several sixteen byte chunks"
+	file := s.writeFile("tamper.txt", content)
+	name := filepath.Join(s.tmpDir, "tamper.txt")
+	raw, err := ioutil.ReadFile(name)
+	s.NoError(err)
+
+	// flipped bit
+	modified := append([]byte(nil), raw...)
+	modified[len(modified)-20] ^= 1
+	s.NoError(ioutil.WriteFile(name, modified, 0644))
+	_, err = ioutil.ReadAll(file)
+	s.Error(err, "modified chunk fails to decrypt")
+	s.NoError(file.Close())
+
+	// truncated at a chunk boundary
+	h, err := readHeader(bytes.NewReader(raw), s.keys)
+	s.NoError(err)
+	s.NoError(ioutil.WriteFile(name, raw[:h.chunkOffset(2)], 0644))
+	_, err = ioutil.ReadAll(file)
+	s.Error(err, "truncated file fails to decrypt")
+	s.NoError(file.Close())
+
+	// truncated to nothing
+	s.NoError(ioutil.WriteFile(name, nil, 0644))
+	_, err = ioutil.ReadAll(file)
+	s.Error(err, "empty file isn't authentic empty content")
+	s.NoError(file.Close())
+	_, err = file.Size()
+	s.Error(err)
+
+	// not encrypted
+	s.NoError(ioutil.WriteFile(name, []byte("plain text"), 0644))
+	_, err = ioutil.ReadAll(file)
+	s.Error(err, "plaintext file can't be read")
+	s.NoError(file.Close())
+}
+
+func (s *fileTestSuite) TestOverwriteAndKeyRotation() {
+	s.writeFile("rotate.txt", "a long first version of the file, written with key-1")
+	s.NoError(s.keys.Rotate("key-2", bytes.Repeat([]byte{2}, 16)))
+	file := s.writeFile("rotate.txt", "short")
+
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("short", string(contents), "overwrite replaces all content")
+	s.NoError(file.Close())
+
+	old := s.writeFile("old.txt", "written with key-2")
+	other, err := NewStaticKeyProvider("key-1", bytes.Repeat([]byte{1}, 32))
+	s.NoError(err)
+	fs := NewFileSystem(&_os.FileSystem{}, other)
+	oldFile, err := fs.NewFile("", old.Path())
+	s.NoError(err)
+	_, err = ioutil.ReadAll(oldFile)
+	s.Error(err, "unknown key id")
+
+	s.NoError(other.AddKey("key-2", bytes.Repeat([]byte{2}, 16)))
+	contents, err = ioutil.ReadAll(oldFile)
+	s.NoError(err)
+	s.Equal("written with key-2", string(contents), "older keys are looked up by id")
+}
+
+func (s *fileTestSuite) TestWriteState() {
+	file := s.writeFile("state.txt", "some content")
+	_, err := file.Seek(4, io.SeekStart)
+	s.NoError(err)
+	_, err = file.Write([]byte("x"))
+	s.Error(err, "writes must start at 0")
+	s.NoError(file.Close())
+
+	_, err = file.Write([]byte("new"))
+	s.NoError(err)
+	_, err = file.Read(make([]byte, 1))
+	s.Error(err, "can't read while writing")
+	s.NoError(file.Delete())
+
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists, "deleted")
+}
+
+func (s *fileTestSuite) TestCopy() {
+	file := s.writeFile("copy.txt", "copy me please")
+
+	osLocation, err := (&_os.FileSystem{}).NewLocation("", filepath.Join(s.tmpDir, "plain"))
+	s.NoError(err)
+	copied, err := file.CopyToLocation(osLocation)
+	s.NoError(err)
+	contents, err := ioutil.ReadFile(copied.Path())
+	s.NoError(err)
+	s.Equal("copy me please", string(contents), "copies decrypted content to other filesystems")
+
+	encrypted, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "encrypted", "copy.txt"))
+	s.NoError(err)
+	s.NoError(copied.CopyToFile(encrypted))
+	contents, err = ioutil.ReadAll(encrypted)
+	s.NoError(err)
+	s.Equal("copy me please", string(contents), "encrypts content copied in")
+
+	target, err := file.Location().NewLocation("moved/")
+	s.NoError(err)
+	moved, err := file.MoveToLocation(target)
+	s.NoError(err)
+	s.Equal("crypt+file://"+filepath.Join(s.tmpDir, "moved", "copy.txt"), moved.URI())
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists, "source removed by move")
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -0,0 +1,193 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+/*
+Encrypted files are a header followed by one or more chunks:
+
+  header: magic "VFSC" | version (1 byte) | key id length (1 byte) | key id | chunk size (uint32) | nonce prefix (8 bytes)
+  chunk:  AES-GCM ciphertext of chunk size bytes of plaintext, followed by its 16 byte tag
+
+Each chunk is sealed with a nonce of the nonce prefix followed by the chunk's index (uint32), and additional data of the
+header followed by a byte which is 1 for the final chunk and 0 otherwise. Every chunk but the final one holds exactly
+chunk size bytes of plaintext. The final chunk holds between 0 and chunk size bytes, so reordered, truncated or
+extended files fail to decrypt.
+*/
+
+const (
+	magic          = "VFSC"
+	formatVersion  = 1
+	maxKeyIDLength = math.MaxUint8
+	noncePrefixLen = 8
+	tagSize        = 16
+
+	// DefaultChunkSize is the amount of plaintext sealed in each chunk when Options.ChunkSize is not set.
+	DefaultChunkSize = 64 * 1024
+)
+
+// errNotEncrypted is returned when a file doesn't start with a valid header.
+var errNotEncrypted = errors.New("file is not in the encrypted format")
+
+// header describes an encrypted file, and holds the cipher for its chunks.
+type header struct {
+	raw         []byte
+	keyID       string
+	chunkSize   int64
+	noncePrefix []byte
+	aead        cipher.AEAD
+}
+
+// newHeader returns a header for a new file encrypted with the provider's current key.
+func newHeader(keys KeyProvider, chunkSize int) (*header, error) {
+	keyID, key, err := keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(keyID) == 0 || len(keyID) > maxKeyIDLength {
+		return nil, fmt.Errorf("invalid key id %q", keyID)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	noncePrefix := make([]byte, noncePrefixLen)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return nil, err
+	}
+
+	raw := bytes.NewBufferString(magic)
+	raw.WriteByte(formatVersion)
+	raw.WriteByte(byte(len(keyID)))
+	raw.WriteString(keyID)
+	_ = binary.Write(raw, binary.BigEndian, uint32(chunkSize))
+	raw.Write(noncePrefix)
+
+	return &header{
+		raw:         raw.Bytes(),
+		keyID:       keyID,
+		chunkSize:   int64(chunkSize),
+		noncePrefix: noncePrefix,
+		aead:        aead,
+	}, nil
+}
+
+// readHeader reads the header at the start of r, looking up its key with the provider.
+func readHeader(r io.Reader, keys KeyProvider) (*header, error) {
+	start := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(r, start); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errNotEncrypted
+		}
+		return nil, err
+	}
+	if string(start[:len(magic)]) != magic {
+		return nil, errNotEncrypted
+	}
+	if start[len(magic)] != formatVersion {
+		return nil, fmt.Errorf("unsupported encryption format version %d", start[len(magic)])
+	}
+
+	rest := make([]byte, int(start[len(magic)+1])+4+noncePrefixLen)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errNotEncrypted
+		}
+		return nil, err
+	}
+	keyIDLen := len(rest) - 4 - noncePrefixLen
+	keyID := string(rest[:keyIDLen])
+	chunkSize := binary.BigEndian.Uint32(rest[keyIDLen:])
+	if chunkSize == 0 {
+		return nil, errNotEncrypted
+	}
+
+	key, err := keys.Key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &header{
+		raw:         append(start, rest...),
+		keyID:       keyID,
+		chunkSize:   int64(chunkSize),
+		noncePrefix: rest[keyIDLen+4:],
+		aead:        aead,
+	}, nil
+}
+
+// size returns the length of the encoded header.
+func (h *header) size() int64 {
+	return int64(len(h.raw))
+}
+
+// chunkCount returns the number of chunks in a file of the given size, including its header.
+func (h *header) chunkCount(fileSize int64) int64 {
+	sealedSize := h.chunkSize + tagSize
+	return (fileSize - h.size() + sealedSize - 1) / sealedSize
+}
+
+// plaintextSize returns the size of the decrypted content of a file of the given size, including its header.
+func (h *header) plaintextSize(fileSize int64) int64 {
+	return fileSize - h.size() - h.chunkCount(fileSize)*tagSize
+}
+
+// chunkOffset returns the position in the file of the chunk with the given index.
+func (h *header) chunkOffset(index int64) int64 {
+	return h.size() + index*(h.chunkSize+tagSize)
+}
+
+// seal encrypts the plaintext of the chunk with the given index.
+func (h *header) seal(index int64, plaintext []byte, final bool) ([]byte, error) {
+	if index > math.MaxUint32 {
+		return nil, errors.New("file is too large to encrypt with this chunk size")
+	}
+	return h.aead.Seal(nil, h.nonce(index), plaintext, h.additionalData(final)), nil
+}
+
+// open decrypts the chunk with the given index.
+func (h *header) open(index int64, ciphertext []byte, final bool) ([]byte, error) {
+	plaintext, err := h.aead.Open(nil, h.nonce(index), ciphertext, h.additionalData(final))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt chunk %d: %s", index, err)
+	}
+	return plaintext, nil
+}
+
+func (h *header) nonce(index int64) []byte {
+	nonce := make([]byte, noncePrefixLen+4)
+	copy(nonce, h.noncePrefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixLen:], uint32(index))
+	return nonce
+}
+
+func (h *header) additionalData(final bool) []byte {
+	ad := make([]byte, len(h.raw)+1)
+	copy(ad, h.raw)
+	if final {
+		ad[len(h.raw)] = 1
+	}
+	return ad
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,89 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// KeyProvider supplies the AES keys used to encrypt and decrypt file content. Each encrypted file records the ID of
+// the key it was written with, so keys can be rotated while older files remain readable.
+type KeyProvider interface {
+	// CurrentKey returns the ID and key used to encrypt new files.
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key with the given ID, for decrypting files written with it.
+	Key(id string) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider for a fixed set of keys held in memory.
+type StaticKeyProvider struct {
+	mu        sync.RWMutex
+	currentID string
+	keys      map[string][]byte
+}
+
+// NewStaticKeyProvider returns a StaticKeyProvider that encrypts with key, identified by id. Keys must be 16, 24 or
+// 32 bytes to select AES-128, AES-192 or AES-256.
+func NewStaticKeyProvider(id string, key []byte) (*StaticKeyProvider, error) {
+	p := &StaticKeyProvider{keys: make(map[string][]byte)}
+	if err := p.AddKey(id, key); err != nil {
+		return nil, err
+	}
+	p.currentID = id
+	return p, nil
+}
+
+// AddKey makes key available for decrypting files written with id, without using it for new files.
+func (p *StaticKeyProvider) AddKey(id string, key []byte) error {
+	if err := validateKey(id, key); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys[id] = append([]byte(nil), key...)
+	return nil
+}
+
+// Rotate adds key and uses it to encrypt new files from now on.
+func (p *StaticKeyProvider) Rotate(id string, key []byte) error {
+	if err := p.AddKey(id, key); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.currentID = id
+	return nil
+}
+
+// CurrentKey implements KeyProvider
+func (p *StaticKeyProvider) CurrentKey() (string, []byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentID, p.keys[p.currentID], nil
+}
+
+// Key implements KeyProvider
+func (p *StaticKeyProvider) Key(id string) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %q", id)
+	}
+	return key, nil
+}
+
+func validateKey(id string, key []byte) error {
+	if id == "" {
+		return errors.New("key id must not be empty")
+	}
+	if len(id) > maxKeyIDLength {
+		return fmt.Errorf("key id must be at most %d bytes", maxKeyIDLength)
+	}
+	if _, err := aes.NewCipher(key); err != nil {
+		return err
+	}
+	return nil
+}
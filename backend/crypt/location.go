@@ -0,0 +1,180 @@
+package crypt
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface for a location on the wrapped FileSystem.
+type Location struct {
+	fileSystem *FileSystem
+	location   vfs.Location
+}
+
+// Unwrap returns the location on the wrapped FileSystem.
+func (l *Location) Unwrap() vfs.Location {
+	return l.location
+}
+
+// List returns the decrypted names of the files at the location. When name encryption is enabled, files whose names
+// weren't encrypted with the FileSystem's NameKey are left out.
+func (l *Location) List() ([]string, error) {
+	names, err := l.location.List()
+	if err != nil {
+		return nil, err
+	}
+	return l.decryptNames(names)
+}
+
+// ListByPrefix returns the decrypted names of the files at the location starting with prefix.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	if err := utils.ValidateFilePrefix(prefix); err != nil {
+		return nil, err
+	}
+	names, err := l.nameCipher()
+	if err != nil {
+		return nil, err
+	}
+	if names == nil {
+		return l.location.ListByPrefix(prefix)
+	}
+
+	// encrypted names don't share a prefix with each other, so every file must be listed and filtered
+	all, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]string, 0)
+	for _, name := range all {
+		if strings.HasPrefix(name, prefix) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
+
+// ListByRegex returns the decrypted names of the files at the location matching regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	names, err := l.nameCipher()
+	if err != nil {
+		return nil, err
+	}
+	if names == nil {
+		return l.location.ListByRegex(regex)
+	}
+
+	all, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]string, 0)
+	for _, name := range all {
+		if regex.MatchString(name) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
+
+// ListDirs returns the names of the directories directly inside the wrapped location, which must implement
+// utils.DirLister. Directory names aren't encrypted.
+func (l *Location) ListDirs() ([]string, error) {
+	return utils.ListDirs(l.location)
+}
+
+// Volume returns the volume of the wrapped location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path returns the path of the wrapped location, which is never encrypted.
+func (l *Location) Path() string {
+	return l.location.Path()
+}
+
+// Exists returns whether the wrapped location exists.
+func (l *Location) Exists() (bool, error) {
+	return l.location.Exists()
+}
+
+// NewLocation returns a new crypt Location relative to this one.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	location, err := l.location.NewLocation(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{
+		fileSystem: l.fileSystem,
+		location:   location,
+	}, nil
+}
+
+// ChangeDir changes the directory of the wrapped location.
+func (l *Location) ChangeDir(relativePath string) error {
+	return l.location.ChangeDir(relativePath)
+}
+
+// FileSystem returns the crypt FileSystem the location was created by.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a crypt File for fileName, relative to the location.
+func (l *Location) NewFile(fileName string) (vfs.File, error) {
+	names, err := l.nameCipher()
+	if err != nil {
+		return nil, err
+	}
+	file, err := l.location.NewFile(names.encryptFilePath(fileName))
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		fileSystem: l.fileSystem,
+		file:       file,
+		name:       path.Base(fileName),
+	}, nil
+}
+
+// DeleteFile deletes the file of the given name at the location.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
+
+func (l *Location) nameCipher() (*nameCipher, error) {
+	return l.fileSystem.nameCipher()
+}
+
+func (l *Location) decryptNames(encrypted []string) ([]string, error) {
+	names, err := l.nameCipher()
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]string, 0, len(encrypted))
+	for _, name := range encrypted {
+		plaintext, err := names.decrypt(name)
+		if err != nil {
+			continue
+		}
+		decrypted = append(decrypted, plaintext)
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,113 @@
+package crypt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type locationTestSuite struct {
+	suite.Suite
+	tmpDir string
+	fs     *FileSystem
+}
+
+func (s *locationTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "crypt-test")
+	s.NoError(err)
+	s.tmpDir = dir
+
+	keys, err := NewStaticKeyProvider("key-1", bytes.Repeat([]byte{1}, 32))
+	s.NoError(err)
+	s.fs = NewFileSystem(&_os.FileSystem{}, keys).WithOptions(Options{NameKey: []byte("name key")})
+}
+
+func (s *locationTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *locationTestSuite) TestEncryptedNames() {
+	location, err := s.fs.NewLocation("", s.tmpDir)
+	s.NoError(err)
+	for _, name := range []string{"customer-jane.csv", "customer-john.csv", "orders.csv"} {
+		file, err := location.NewFile(name)
+		s.NoError(err)
+		_, err = file.Write([]byte(name))
+		s.NoError(err)
+		s.NoError(file.Close())
+		s.Equal(filepath.Join(s.tmpDir, name), file.Path(), "path has the decrypted name")
+	}
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "stray.txt"), []byte("not ours"), 0644))
+
+	raw, err := ioutil.ReadDir(s.tmpDir)
+	s.NoError(err)
+	for _, info := range raw {
+		s.NotContains(info.Name(), "customer", "names are encrypted")
+	}
+
+	names, err := location.List()
+	s.NoError(err)
+	s.ElementsMatch([]string{"customer-jane.csv", "customer-john.csv", "orders.csv"}, names,
+		"names are decrypted and others are left out")
+
+	names, err = location.ListByPrefix("customer-")
+	s.NoError(err)
+	s.ElementsMatch([]string{"customer-jane.csv", "customer-john.csv"}, names)
+
+	names, err = location.ListByRegex(regexp.MustCompile(`^orders\.`))
+	s.NoError(err)
+	s.Equal([]string{"orders.csv"}, names)
+
+	_, err = location.ListByPrefix("sub/dir")
+	s.Error(err, "prefix must not contain a slash")
+
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "orders.csv"))
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("orders.csv", string(contents), "same name finds the same file")
+
+	s.NoError(location.DeleteFile("orders.csv"))
+	names, err = location.List()
+	s.NoError(err)
+	s.Len(names, 2, "file deleted")
+}
+
+func (s *locationTestSuite) TestLocation() {
+	location, err := s.fs.NewLocation("", s.tmpDir)
+	s.NoError(err)
+	s.Equal(s.fs, location.FileSystem())
+	s.Equal("crypt+file", location.FileSystem().Scheme())
+	s.Equal("encrypted os", location.FileSystem().Name())
+	s.Equal("crypt+file://"+s.tmpDir+"/", location.URI())
+
+	sub, err := location.NewLocation("sub/")
+	s.NoError(err)
+	s.Equal(filepath.Join(s.tmpDir, "sub")+"/", sub.Path())
+	exists, err := sub.Exists()
+	s.NoError(err)
+	s.False(exists)
+
+	file, err := sub.NewFile("data.txt")
+	s.NoError(err)
+	s.Equal(sub.Path(), file.Location().Path())
+	s.Equal(s.fs, file.Location().FileSystem(), "file location stays wrapped")
+
+	_, err = NewFileSystem(nil, nil).NewFile("", "/a.txt")
+	s.Error(err, "wrapped filesystem and keys are required")
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
@@ -0,0 +1,74 @@
+package crypt
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"path"
+)
+
+// nameNonceSize is the size of the nonce prepended to each encrypted name.
+const nameNonceSize = 12
+
+// nameCipher deterministically encrypts file names so that encrypted files can still be found by name. The nonce for a
+// name is derived from an HMAC of the name, so the same name always encrypts to the same value.
+type nameCipher struct {
+	aead   cipher.AEAD
+	macKey []byte
+}
+
+// newNameCipher derives separate encryption and MAC keys from key.
+func newNameCipher(key []byte) (*nameCipher, error) {
+	aead, err := newAEAD(deriveKey(key, "vfs crypt name encryption"))
+	if err != nil {
+		return nil, err
+	}
+	return &nameCipher{
+		aead:   aead,
+		macKey: deriveKey(key, "vfs crypt name nonce"),
+	}, nil
+}
+
+// encryptFilePath encrypts the file name at the end of p, leaving its directory as is. A nil cipher returns p as is.
+func (c *nameCipher) encryptFilePath(p string) string {
+	if c == nil {
+		return p
+	}
+	dir, name := path.Split(p)
+	return dir + c.encrypt(name)
+}
+
+// encrypt returns the encrypted name, encoded with unpadded url-safe base64 so that it's valid on every backend.
+func (c *nameCipher) encrypt(name string) string {
+	mac := hmac.New(sha256.New, c.macKey)
+	_, _ = mac.Write([]byte(name))
+	nonce := mac.Sum(nil)[:nameNonceSize]
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, []byte(name), nil))
+}
+
+// decrypt returns the name an encrypted name was created from. A nil cipher returns name as is.
+func (c *nameCipher) decrypt(name string) (string, error) {
+	if c == nil {
+		return name, nil
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(name)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < nameNonceSize {
+		return "", errors.New("encrypted name is too short")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:nameNonceSize], sealed[nameNonceSize:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
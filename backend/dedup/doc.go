@@ -0,0 +1,49 @@
+/*
+Package dedup content-addressable VFS implementation.
+
+A dedup FileSystem stores file content in a blob store, any vfs.Location, under the SHA-256 of the content, so that
+files with the same content are stored once however many names they have. Callers keep using NewFile, Read and Write
+as with any other backend:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend/dedup"
+      "github.com/c2fo/vfs/v3/vfssimple"
+  )
+
+  func ArtifactFileSystem() (*dedup.FileSystem, error) {
+      store, err := vfssimple.NewLocation("s3://mybucket/artifacts/")
+      if err != nil {
+          return nil, err
+      }
+      return dedup.NewFileSystem(store), nil
+  }
+
+Paths
+
+Dedup FileSystems have no volume, and URIs use the "dedup" scheme, ie: dedup:///builds/1234/app.tar.gz. Location.List
+returns the names of the files at a location, as with any other backend.
+
+Store Layout
+
+The blob store holds four trees:
+
+  blobs/<first 2 hex digits>/<sha256>    the content of every distinct file
+  refs/<path>                            the sha256 of the content of the file at path
+  links/<sha256>/<escaped path>          an empty marker for each path referencing the blob
+  uploads/<sha256>.<random>              content being uploaded, moved to blobs once it's complete
+
+Writing
+
+Write spools content to a local temporary file, in Options.TempDir, and Close hashes it. The blob is only uploaded if
+no file with the same content has been written before. Writing always replaces the whole file. Copying between files
+of the same FileSystem only adds a reference, without copying any content.
+
+Garbage Collection
+
+When a file is overwritten or deleted its marker is removed, and a blob with no markers left is deleted. A process
+that stops part way through a Close or Delete can leave a stale marker or an unreferenced blob behind; FileSystem.GC
+removes them, along with any unfinished uploads. Writes are only serialized within a FileSystem: several processes
+sharing a blob store aren't coordinated, so one process deleting the last reference to a blob can delete it just as
+another references it again, and GC should only run while nothing else is writing.
+*/
+package dedup
@@ -0,0 +1,287 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for a file of a dedup FileSystem. Reads are from the blob its path references,
+// and writes are spooled to a local temporary file until Close.
+type File struct {
+	fileSystem *FileSystem
+	path       string
+
+	// blob is the blob being read, if any
+	blob vfs.File
+
+	// spool holds the content written since the file was last closed, if any
+	spool *os.File
+}
+
+// Info Functions
+
+// LastModified returns the timestamp the file's path was last changed to reference new content.
+func (f *File) LastModified() (*time.Time, error) {
+	ref, err := f.fileSystem.refFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	return ref.LastModified()
+}
+
+// Name returns the base name of the file.
+func (f *File) Name() string {
+	return path.Base(f.path)
+}
+
+// Path returns the full path of the file. IE: "/some/path/to/file.txt"
+func (f *File) Path() string {
+	return f.path
+}
+
+// Exists returns whether the file's path references any content.
+func (f *File) Exists() (bool, error) {
+	ref, err := f.fileSystem.refFile(f.path)
+	if err != nil {
+		return false, err
+	}
+	return ref.Exists()
+}
+
+// Size returns the size of the content the file references.
+func (f *File) Size() (uint64, error) {
+	blob, err := f.resolved()
+	if err != nil {
+		return 0, err
+	}
+	return blob.Size()
+}
+
+// Hash returns the hex encoded SHA-256 of the content the file references.
+func (f *File) Hash() (string, error) {
+	hash, err := f.fileSystem.readRef(f.path)
+	if err != nil {
+		return "", err
+	}
+	if hash == "" {
+		return "", fmt.Errorf("file does not exist at %s", f)
+	}
+	return hash, nil
+}
+
+// Location returns a dedup Location for the directory containing the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		path:       utils.EnsureTrailingSlash(path.Dir(f.path)),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the file's content to the target file. When the target is a File of the same FileSystem only a
+// reference is added, without copying any content.
+func (f *File) CopyToFile(target vfs.File) error {
+	if t, ok := target.(*File); ok && t.fileSystem == f.fileSystem {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		hash, err := f.Hash()
+		if err != nil {
+			return err
+		}
+		if err := t.discard(); err != nil {
+			return err
+		}
+		return f.fileSystem.reference(t.path, hash, nil)
+	}
+
+	if err := utils.TouchCopy(target, f); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := target.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	return f.Close()
+}
+
+// CopyToLocation copies the file to a file of the same name at location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.CopyToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// MoveToFile copies the file to the target file then deletes the file.
+func (f *File) MoveToFile(target vfs.File) error {
+	if err := f.CopyToFile(target); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation copies the file to location then deletes the file, returning the new file.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := f.CopyToLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	return newFile, f.Delete()
+}
+
+// CRUD Operations
+
+// Delete removes the file's reference, discarding anything written but not yet closed. The content is deleted too if
+// no other file references it.
+func (f *File) Delete() error {
+	if err := f.discard(); err != nil {
+		return err
+	}
+	return f.fileSystem.unreference(f.path)
+}
+
+// Close stores anything written since the file was last closed, uploading it only if no file with the same content
+// has been stored before.
+func (f *File) Close() error {
+	if f.spool != nil {
+		return f.commit()
+	}
+	if f.blob == nil {
+		return nil
+	}
+	err := f.blob.Close()
+	f.blob = nil
+	return err
+}
+
+// Read implements the io.Reader interface, reading from the blob the file references.
+func (f *File) Read(p []byte) (int, error) {
+	if f.spool != nil {
+		return 0, errors.New("file must be closed after writing before it can be read")
+	}
+	blob, err := f.resolved()
+	if err != nil {
+		return 0, err
+	}
+	return blob.Read(p)
+}
+
+// Seek implements the io.Seeker interface. While writing, it seeks within the content written so far.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.spool != nil {
+		return f.spool.Seek(offset, whence)
+	}
+	blob, err := f.resolved()
+	if err != nil {
+		return 0, err
+	}
+	return blob.Seek(offset, whence)
+}
+
+// Write implements the io.Writer interface, spooling data to a local temporary file until Close. Writing always
+// replaces the whole file.
+func (f *File) Write(data []byte) (int, error) {
+	if f.spool == nil {
+		if f.blob != nil {
+			if err := f.Close(); err != nil {
+				return 0, err
+			}
+		}
+		spool, err := ioutil.TempFile(f.fileSystem.options.TempDir, "vfs-dedup")
+		if err != nil {
+			return 0, err
+		}
+		f.spool = spool
+	}
+	return f.spool.Write(data)
+}
+
+/*
+	Private helpers
+*/
+
+// resolved returns the blob the file references, opening it if needed.
+func (f *File) resolved() (vfs.File, error) {
+	if f.blob != nil {
+		return f.blob, nil
+	}
+	hash, err := f.Hash()
+	if err != nil {
+		return nil, err
+	}
+	blob, err := f.fileSystem.blobFile(hash)
+	if err != nil {
+		return nil, err
+	}
+	f.blob = blob
+	return blob, nil
+}
+
+// commit hashes the spooled content, uploads it if it isn't already in the blob store and references it.
+func (f *File) commit() error {
+	spool := f.spool
+	f.spool = nil
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, spool); err != nil {
+		return err
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	return f.fileSystem.reference(f.path, hash, func(blob vfs.File) error {
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := utils.TouchCopyReader(blob, spool); err != nil {
+			return err
+		}
+		return blob.Close()
+	})
+}
+
+// discard closes the file, throwing away anything written since it was last closed.
+func (f *File) discard() error {
+	if f.spool != nil {
+		spool := f.spool
+		f.spool = nil
+		_ = spool.Close()
+		if err := os.Remove(spool.Name()); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
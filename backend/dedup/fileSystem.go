@@ -0,0 +1,359 @@
+package dedup
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Scheme defines the filesystem type.
+const Scheme = "dedup"
+const name = "content-addressable"
+
+// Directories of the blob store.
+const (
+	blobsDir   = "blobs"
+	refsDir    = "refs"
+	linksDir   = "links"
+	uploadsDir = "uploads"
+)
+
+// Options holds dedup-specific options.
+type Options struct {
+	// TempDir is the local directory content is spooled to while it's written, so that it can be hashed before it's
+	// uploaded. Defaults to the system temporary directory.
+	TempDir string `json:"tempDir,omitempty"`
+}
+
+// FileSystem implements vfs.Filesystem as a content-addressable store, keeping the content of files in a blob store
+// under its SHA-256 so that duplicates are stored once.
+type FileSystem struct {
+	store   vfs.Location
+	options Options
+
+	// mu serializes changes to references, so that a blob isn't collected while it's being referenced again. It only
+	// does so within this FileSystem: another process sharing the store can release a blob just as it's referenced here.
+	mu sync.Mutex
+}
+
+// NewFileSystem initializer returns a FileSystem that keeps content, and the references to it, at store.
+func NewFileSystem(store vfs.Location) *FileSystem {
+	return &FileSystem{store: store}
+}
+
+// WithOptions sets options for the FileSystem. Any options that aren't dedup.Options are ignored.
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+	}
+	return fs
+}
+
+// NewFile function returns the dedup implementation of vfs.File. Dedup FileSystems have no volume so volume must be
+// empty.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if err := fs.validate(volume); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, errors.New("non-empty string for name is required")
+	}
+	p := cleanPath(name)
+	if p == "/" {
+		return nil, fmt.Errorf("%s is not a file", name)
+	}
+	return &File{fileSystem: fs, path: p}, nil
+}
+
+// NewLocation function returns the dedup implementation of vfs.Location. Dedup FileSystems have no volume so volume
+// must be empty.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if err := fs.validate(volume); err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, path: utils.EnsureTrailingSlash(cleanPath(name))}, nil
+}
+
+// Name returns "content-addressable"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme returns "dedup" as the initial part of a file URI ie: dedup://
+func (fs *FileSystem) Scheme() string {
+	return Scheme
+}
+
+// GC deletes markers whose path no longer references their blob, blobs with no markers left and unfinished uploads,
+// returning the number of blobs deleted. It's only needed after a process stopped part way through a Close or Delete.
+func (fs *FileSystem) GC() (int, error) {
+	if err := fs.validate(""); err != nil {
+		return 0, err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	uploads, err := fs.store.NewLocation(uploadsDir + "/")
+	if err != nil {
+		return 0, err
+	}
+	names, err := uploads.List()
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range names {
+		if err := uploads.DeleteFile(name); err != nil {
+			return 0, err
+		}
+	}
+
+	deleted := 0
+	for i := 0; i < 256; i++ {
+		prefix, err := fs.store.NewLocation(fmt.Sprintf("%s/%02x/", blobsDir, i))
+		if err != nil {
+			return deleted, err
+		}
+		hashes, err := prefix.List()
+		if err != nil {
+			return deleted, err
+		}
+		for _, hash := range hashes {
+			links, err := fs.store.NewLocation(linksDir + "/" + hash + "/")
+			if err != nil {
+				return deleted, err
+			}
+			markers, err := links.List()
+			if err != nil {
+				return deleted, err
+			}
+			referenced := false
+			for _, marker := range markers {
+				p, err := url.PathUnescape(marker)
+				if err != nil {
+					return deleted, err
+				}
+				current, err := fs.readRef(p)
+				if err != nil {
+					return deleted, err
+				}
+				if current == hash {
+					referenced = true
+					continue
+				}
+				if err := links.DeleteFile(marker); err != nil {
+					return deleted, err
+				}
+			}
+			if !referenced {
+				if err := prefix.DeleteFile(hash); err != nil {
+					return deleted, err
+				}
+				deleted++
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// blobFile returns the file in the blob store holding the content with the given hash.
+func (fs *FileSystem) blobFile(hash string) (vfs.File, error) {
+	return fs.store.NewFile(path.Join(blobsDir, hash[:2], hash))
+}
+
+// refFile returns the file in the blob store holding the hash of the file at p.
+func (fs *FileSystem) refFile(p string) (vfs.File, error) {
+	return fs.store.NewFile(refsDir + p)
+}
+
+// refLocation returns the location in the blob store holding the references of the files at location p.
+func (fs *FileSystem) refLocation(p string) (vfs.Location, error) {
+	return fs.store.NewLocation(refsDir + p)
+}
+
+// readRef returns the hash of the content of the file at p, or "" if there's no file at p.
+func (fs *FileSystem) readRef(p string) (string, error) {
+	ref, err := fs.refFile(p)
+	if err != nil {
+		return "", err
+	}
+	exists, err := ref.Exists()
+	if err != nil || !exists {
+		return "", err
+	}
+	content, err := ioutil.ReadAll(ref)
+	if err != nil {
+		return "", err
+	}
+	if err := ref.Close(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// reference makes the file at p refer to the blob with the given hash, releasing the blob it referred to before. If
+// the blob isn't in the store it's written by upload, which may be nil when the blob must already exist.
+func (fs *FileSystem) reference(p, hash string, upload func(blob vfs.File) error) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	blob, err := fs.blobFile(hash)
+	if err != nil {
+		return err
+	}
+	exists, err := blob.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if upload == nil {
+			return fmt.Errorf("blob %s does not exist in %s", hash, fs.store)
+		}
+		if err := fs.uploadBlob(blob, upload); err != nil {
+			return err
+		}
+	}
+
+	marker, err := fs.store.NewFile(markerPath(hash, p))
+	if err != nil {
+		return err
+	}
+	if _, err := marker.Write([]byte{}); err != nil {
+		return err
+	}
+	if err := marker.Close(); err != nil {
+		return err
+	}
+
+	previous, err := fs.readRef(p)
+	if err != nil {
+		return err
+	}
+	if previous == hash {
+		return nil
+	}
+
+	ref, err := fs.refFile(p)
+	if err != nil {
+		return err
+	}
+	if _, err := ref.Write([]byte(hash)); err != nil {
+		return err
+	}
+	if err := ref.Close(); err != nil {
+		return err
+	}
+
+	if previous == "" {
+		return nil
+	}
+	return fs.release(previous, p)
+}
+
+// uploadBlob writes the content of blob with upload under a temporary name, then moves it to blob, so that an upload
+// that fails part way doesn't leave a truncated blob that later writes of the same content would reference.
+func (fs *FileSystem) uploadBlob(blob vfs.File, upload func(blob vfs.File) error) error {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	tmp, err := fs.store.NewFile(path.Join(uploadsDir, blob.Name()+"."+hex.EncodeToString(suffix)))
+	if err != nil {
+		return err
+	}
+	if err := upload(tmp); err != nil {
+		_ = tmp.Close()
+		if exists, existsErr := tmp.Exists(); existsErr == nil && exists {
+			_ = tmp.Delete()
+		}
+		return err
+	}
+	return tmp.MoveToFile(blob)
+}
+
+// unreference deletes the reference of the file at p and releases its blob.
+func (fs *FileSystem) unreference(p string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	hash, err := fs.readRef(p)
+	if err != nil {
+		return err
+	}
+	if hash == "" {
+		return fmt.Errorf("file does not exist at %s://%s", Scheme, p)
+	}
+	ref, err := fs.refFile(p)
+	if err != nil {
+		return err
+	}
+	if err := ref.Delete(); err != nil {
+		return err
+	}
+	return fs.release(hash, p)
+}
+
+// release deletes the marker of p for the blob with the given hash, then the blob if no other markers are left. mu
+// must be held.
+func (fs *FileSystem) release(hash, p string) error {
+	marker, err := fs.store.NewFile(markerPath(hash, p))
+	if err != nil {
+		return err
+	}
+	exists, err := marker.Exists()
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := marker.Delete(); err != nil {
+			return err
+		}
+	}
+
+	links, err := fs.store.NewLocation(linksDir + "/" + hash + "/")
+	if err != nil {
+		return err
+	}
+	markers, err := links.List()
+	if err != nil || len(markers) > 0 {
+		return err
+	}
+	blob, err := fs.blobFile(hash)
+	if err != nil {
+		return err
+	}
+	exists, err = blob.Exists()
+	if err != nil || !exists {
+		return err
+	}
+	return blob.Delete()
+}
+
+func (fs *FileSystem) validate(volume string) error {
+	if fs.store == nil {
+		return errors.New("dedup FileSystem requires a blob store Location")
+	}
+	if volume != "" {
+		return errors.New("dedup FileSystem has no volumes")
+	}
+	return nil
+}
+
+// cleanPath returns p as an absolute path, with ".." elements stopping at the root.
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// markerPath returns the path, relative to the blob store, of the marker for p referencing the blob with the given
+// hash. The whole path is escaped into a single file name so that a blob's markers can be listed.
+func markerPath(hash, p string) string {
+	return path.Join(linksDir, hash, url.PathEscape(p))
+}
@@ -0,0 +1,200 @@
+package dedup
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/mocks"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir string
+	fs     *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "dedup-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	store, err := (&_os.FileSystem{}).NewLocation("", filepath.Join(dir, "store")+"/")
+	s.NoError(err)
+	s.fs = NewFileSystem(store).WithOptions(Options{TempDir: dir})
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) write(name, contents string) *File {
+	file, err := s.fs.NewFile("", name)
+	s.NoError(err)
+	_, err = file.Write([]byte(contents))
+	s.NoError(err)
+	s.NoError(file.Close())
+	return file.(*File)
+}
+
+func (s *fileTestSuite) read(name string) string {
+	file, err := s.fs.NewFile("", name)
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.NoError(file.Close())
+	return string(contents)
+}
+
+func (s *fileTestSuite) location(name string) vfs.Location {
+	location, err := s.fs.NewLocation("", name)
+	s.NoError(err)
+	return location
+}
+
+// blobs returns the names of the blobs in the store.
+func (s *fileTestSuite) blobs() []string {
+	var names []string
+	err := filepath.Walk(filepath.Join(s.tmpDir, "store", blobsDir), func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			names = append(names, info.Name())
+		}
+		return nil
+	})
+	s.NoError(err)
+	return names
+}
+
+func (s *fileTestSuite) TestWriteDeduplicates() {
+	a := s.write("/builds/1/app.tar", "artifact")
+	b := s.write("/builds/2/app.tar", "artifact")
+	s.write("/builds/2/other.tar", "different")
+
+	s.Equal("artifact", s.read("/builds/1/app.tar"))
+	s.Equal("artifact", s.read("/builds/2/app.tar"))
+	s.Len(s.blobs(), 2)
+
+	hashA, err := a.Hash()
+	s.NoError(err)
+	hashB, err := b.Hash()
+	s.NoError(err)
+	s.Equal(hashA, hashB)
+	s.Equal("dedup:///builds/1/app.tar", a.URI())
+
+	size, err := a.Size()
+	s.NoError(err)
+	s.Equal(uint64(8), size)
+
+	location, err := s.fs.NewLocation("", "/builds/2/")
+	s.NoError(err)
+	names, err := location.List()
+	s.NoError(err)
+	s.Equal([]string{"app.tar", "other.tar"}, names)
+}
+
+func (s *fileTestSuite) TestOverwriteAndDeleteCollect() {
+	a := s.write("/a.txt", "shared")
+	s.write("/b.txt", "shared")
+
+	s.write("/a.txt", "changed")
+	s.Equal("changed", s.read("/a.txt"))
+	s.Equal("shared", s.read("/b.txt"))
+	s.Len(s.blobs(), 2, "shared blob is still referenced by b.txt")
+
+	s.NoError(s.location("/").DeleteFile("b.txt"))
+	s.Len(s.blobs(), 1, "shared blob is collected")
+
+	s.NoError(a.Delete())
+	s.Empty(s.blobs())
+	exists, err := a.Exists()
+	s.NoError(err)
+	s.False(exists)
+	s.Error(a.Delete(), "already deleted")
+}
+
+func (s *fileTestSuite) TestCopyAddsReference() {
+	a := s.write("/a.txt", "content")
+	target, err := s.fs.NewFile("", "/copy/a.txt")
+	s.NoError(err)
+	s.NoError(a.CopyToFile(target))
+	s.Equal("content", s.read("/copy/a.txt"))
+	s.Len(s.blobs(), 1)
+
+	moved, err := a.MoveToLocation(s.location("/moved/"))
+	s.NoError(err)
+	s.Equal("/moved/a.txt", moved.Path())
+	s.Equal("content", s.read("/moved/a.txt"))
+	s.Len(s.blobs(), 1)
+
+	osTarget, err := (&_os.FileSystem{}).NewFile("", filepath.Join(s.tmpDir, "out.txt"))
+	s.NoError(err)
+	s.NoError(moved.CopyToFile(osTarget))
+	contents, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "out.txt"))
+	s.NoError(err)
+	s.Equal("content", string(contents))
+}
+
+func (s *fileTestSuite) TestEmptyFile() {
+	s.write("/empty.txt", "")
+	s.Equal("", s.read("/empty.txt"))
+	s.Len(s.blobs(), 1)
+}
+
+func (s *fileTestSuite) TestGC() {
+	s.write("/a.txt", "kept")
+	// a blob left behind without any reference
+	orphan := s.write("/orphan.txt", "orphaned")
+	ref, err := s.fs.refFile(orphan.path)
+	s.NoError(err)
+	s.NoError(ref.Delete())
+	s.Len(s.blobs(), 2)
+	// an upload left behind by a process that stopped
+	s.NoError(os.MkdirAll(filepath.Join(s.tmpDir, "store", uploadsDir), 0755))
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "store", uploadsDir, "abc.123"), []byte("part"), 0644))
+
+	deleted, err := s.fs.GC()
+	s.NoError(err)
+	s.Equal(1, deleted)
+	s.Len(s.blobs(), 1)
+	s.Equal("kept", s.read("/a.txt"))
+	uploads, err := ioutil.ReadDir(filepath.Join(s.tmpDir, "store", uploadsDir))
+	s.NoError(err)
+	s.Empty(uploads, "unfinished uploads are deleted")
+}
+
+func (s *fileTestSuite) TestFailedUpload() {
+	faulty := mocks.NewFaultyFileSystem(&_os.FileSystem{}, 1, mocks.Fault{
+		Ops:     []mocks.FaultOp{mocks.FaultOpWrite},
+		Path:    regexp.MustCompile("/" + uploadsDir + "/"),
+		Times:   1,
+		ShortIO: true,
+		Err:     errors.New("upload failed"),
+	})
+	store, err := faulty.NewLocation("", filepath.Join(s.tmpDir, "store")+"/")
+	s.NoError(err)
+	s.fs = NewFileSystem(store).WithOptions(Options{TempDir: s.tmpDir})
+
+	file, err := s.fs.NewFile("", "/a.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("content"))
+	s.NoError(err)
+	s.Error(file.Close(), "the upload fails part way")
+	s.Empty(s.blobs(), "no truncated blob is left under the hash")
+
+	s.write("/b.txt", "content")
+	s.Equal("content", s.read("/b.txt"), "the same content is uploaded again in full")
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
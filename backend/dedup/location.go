@@ -0,0 +1,113 @@
+package dedup
+
+import (
+	"path"
+	"regexp"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface for a path of a dedup FileSystem.
+type Location struct {
+	fileSystem *FileSystem
+	path       string
+}
+
+// List returns the names of the files at the location.
+func (l *Location) List() ([]string, error) {
+	location, err := l.fileSystem.refLocation(l.path)
+	if err != nil {
+		return nil, err
+	}
+	return location.List()
+}
+
+// ListByPrefix returns the names of the files at the location starting with prefix.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	location, err := l.fileSystem.refLocation(l.path)
+	if err != nil {
+		return nil, err
+	}
+	return location.ListByPrefix(prefix)
+}
+
+// ListByRegex returns the names of the files at the location matching regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	location, err := l.fileSystem.refLocation(l.path)
+	if err != nil {
+		return nil, err
+	}
+	return location.ListByRegex(regex)
+}
+
+// ListDirs returns the names of the directories directly inside the location.
+func (l *Location) ListDirs() ([]string, error) {
+	location, err := l.fileSystem.refLocation(l.path)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ListDirs(location)
+}
+
+// Volume returns "" since dedup FileSystems have no volume.
+func (l *Location) Volume() string {
+	return ""
+}
+
+// Path returns the location's path with leading and trailing slashes.
+func (l *Location) Path() string {
+	return l.path
+}
+
+// Exists returns whether any file has been stored at the location.
+func (l *Location) Exists() (bool, error) {
+	location, err := l.fileSystem.refLocation(l.path)
+	if err != nil {
+		return false, err
+	}
+	return location.Exists()
+}
+
+// NewLocation returns a new Location relative to this one.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	return &Location{
+		fileSystem: l.fileSystem,
+		path:       utils.EnsureTrailingSlash(cleanPath(path.Join(l.path, relativePath))),
+	}, nil
+}
+
+// ChangeDir changes the location to a path relative to it.
+func (l *Location) ChangeDir(relativePath string) error {
+	l.path = utils.EnsureTrailingSlash(cleanPath(path.Join(l.path, relativePath)))
+	return nil
+}
+
+// FileSystem returns the dedup FileSystem the location was created by.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a dedup File for fileName, relative to the location.
+func (l *Location) NewFile(fileName string) (vfs.File, error) {
+	return l.fileSystem.NewFile("", path.Join(l.path, fileName))
+}
+
+// DeleteFile deletes the file of the given name at the location, as File.Delete does.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
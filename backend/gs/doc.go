@@ -47,12 +47,31 @@ would have to be cast as gs.Filesystem to use the following:
           },
       )
 
-      // to pass specific client, for instance no-auth client
+      // options may be combined, for instance credentials from bytes, a private endpoint and a billing project for
+      // requester pays buckets
+      fs = fs.WithOptions(
+          gs.Options{
+              CredentialJSON: credentialBytes,
+              Endpoint:       "https://storage.example.com/storage/v1/",
+              UserProject:    "my-billing-project",
+          },
+      )
+
+      // to access public buckets without any credentials
+      fs = fs.WithOptions(gs.Options{WithoutAuthentication: true})
+
+      // to pass specific client
       ctx := context.Background()
-      client, _ := storage.NewClient(ctx, option.WithoutAuthentication())
+      client, _ := storage.NewClient(ctx, option.WithHTTPClient(myHTTPClient))
       fs = fs.WithClient(client)
   }
 
+Emulators
+
+When the STORAGE_EMULATOR_HOST environment variable is set (ie: STORAGE_EMULATOR_HOST=localhost:4443), all requests,
+including object reads and uploads, are sent to that host over http without authentication. This allows testing
+against an emulator such as fake-gcs-server.
+
 Authentication
 
 Authentication, by default, occurs automatically when Client() is called. It looks for credentials in the following places,
@@ -3,6 +3,7 @@ package gs
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -28,20 +29,16 @@ type File struct {
 	key         string
 	tempFile    *os.File
 	writeBuffer *bytes.Buffer
+
+	// streamed is set once ReadFrom has uploaded the file, until it's closed
+	streamed bool
 }
 
 // Close cleans up underlying mechanisms for reading from and writing to the file. Closes and removes the
 // local temp file, and triggers a write to GCS of anything in the f.writeBuffer if it has been created.
 func (f *File) Close() error {
-	if f.tempFile != nil {
-		defer f.tempFile.Close()
-
-		err := os.Remove(f.tempFile.Name())
-		if err != nil && !os.IsNotExist(err) {
-			return err
-		}
-
-		f.tempFile = nil
+	if err := f.removeTempFile(); err != nil {
+		return err
 	}
 
 	if f.writeBuffer != nil {
@@ -62,6 +59,7 @@ func (f *File) Close() error {
 	}
 
 	f.writeBuffer = nil
+	f.streamed = false
 	return nil
 }
 
@@ -86,6 +84,9 @@ func (f *File) Seek(offset int64, whence int) (int64, error) {
 // Write implements the standard for io.Writer. A buffer is added to with each subsequent
 // write. Calling Close() will write the contents back to GCS.
 func (f *File) Write(data []byte) (n int, err error) {
+	if f.streamed {
+		return 0, f.streamedError()
+	}
 	if f.writeBuffer == nil {
 		//note, initializing with 'data' and returning len(data), nil
 		//causes issues with some Write usages, notably csv.Writer
@@ -102,6 +103,72 @@ func (f *File) Write(data []byte) (n int, err error) {
 	return f.writeBuffer.Write(data)
 }
 
+// ReadFrom implements the io.ReaderFrom interface, so that io.Copy to a file that hasn't been written to yet streams r
+// straight to GCS rather than buffering all of it until Close. A file that's already been written to appends r to its
+// buffer as Write does. Once r has been streamed the object is complete, so further writes fail until the file is
+// closed, rather than replacing it.
+func (f *File) ReadFrom(r io.Reader) (int64, error) {
+	if f.streamed {
+		return 0, f.streamedError()
+	}
+	if f.writeBuffer != nil {
+		return f.writeBuffer.ReadFrom(r)
+	}
+	// content read before is replaced
+	if err := f.removeTempFile(); err != nil {
+		return 0, err
+	}
+	handle, err := f.getObjectHandle()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(f.fileSystem.ctx)
+	defer cancel()
+	w := handle.NewWriter(ctx)
+	n, err := io.Copy(w, r)
+	if err != nil {
+		// cancelling the context, by returning, abandons the upload
+		return n, err
+	}
+	if err := w.Close(); err != nil {
+		return n, err
+	}
+	f.streamed = true
+	return n, nil
+}
+
+// WriteTo implements the io.WriterTo interface, so that io.Copy from a file that hasn't been read yet streams the
+// object straight to w without first downloading it to a temporary local file.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	if f.tempFile != nil {
+		return io.Copy(w, f.tempFile)
+	}
+	handle, err := f.getObjectHandle()
+	if err != nil {
+		return 0, err
+	}
+	reader, err := handle.NewReader(f.fileSystem.ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return io.Copy(w, reader)
+}
+
+// ReadRange returns a reader of length bytes of the object's content starting at offset, or of the rest of the content
+// when length is negative. Only the range is fetched, rather than downloading the whole object as Seek does.
+func (f *File) ReadRange(offset, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+	handle, err := f.getObjectHandle()
+	if err != nil {
+		return nil, err
+	}
+	return handle.NewRangeReader(f.fileSystem.ctx, offset, length)
+}
+
 //String returns the file URI string.
 func (f *File) String() string {
 	return f.URI()
@@ -140,8 +207,14 @@ func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
 		if err != nil {
 			return nil, err
 		}
-		cerr := f.copyWithinGCSToFile(dest.(*File))
-		if cerr != nil {
+		if gsDest, ok := dest.(*File); ok {
+			if cerr := f.copyWithinGCSToFile(gsDest); cerr != nil {
+				return nil, cerr
+			}
+			return dest, nil
+		}
+		// a wrapper using the gs scheme, ie: a cached location
+		if cerr := f.CopyToFile(dest); cerr != nil {
 			return nil, cerr
 		}
 		return dest, nil
@@ -231,6 +304,26 @@ func (f *File) LastModified() (*time.Time, error) {
 	return &attr.Updated, nil
 }
 
+// Generation returns the 'Generation' property from the GCS attributes, which changes whenever the object's content
+// does.
+func (f *File) Generation() (int64, error) {
+	attr, err := f.getObjectAttrs()
+	if err != nil {
+		return 0, err
+	}
+	return attr.Generation, nil
+}
+
+// MD5 returns the hex encoded 'MD5' property from the GCS attributes. Composite objects have no MD5, so "" is
+// returned for those.
+func (f *File) MD5() (string, error) {
+	attr, err := f.getObjectAttrs()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(attr.MD5), nil
+}
+
 // Size returns the 'Size' property from the GCS attributes.
 func (f *File) Size() (uint64, error) {
 	attr, err := f.getObjectAttrs()
@@ -255,6 +348,27 @@ func (f *File) URI() string {
 	return utils.GetFileURI(vfs.File(f))
 }
 
+// removeTempFile closes and removes the local temp file used for reads, if there is one.
+func (f *File) removeTempFile() error {
+	if f.tempFile == nil {
+		return nil
+	}
+	defer f.tempFile.Close()
+
+	err := os.Remove(f.tempFile.Name())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f.tempFile = nil
+	return nil
+}
+
+// streamedError returns the error for a write to a file whose content was already uploaded by ReadFrom.
+func (f *File) streamedError() error {
+	return fmt.Errorf("%s was streamed by io.Copy and must be closed before it's written again", f)
+}
+
 func (f *File) checkTempFile() error {
 	if f.tempFile == nil {
 		localTempFile, err := f.copyToLocalTempReader()
@@ -49,7 +49,10 @@ func (fs *FileSystem) Scheme() string {
 // See Overview for authentication resolution
 func (fs *FileSystem) Client() (*storage.Client, error) {
 	if fs.client == nil {
-		gsClientOpts := parseClientOptions(fs.options)
+		gsClientOpts, err := parseClientOptions(fs.ctx, fs.options)
+		if err != nil {
+			return nil, err
+		}
 		client, err := storage.NewClient(fs.ctx, gsClientOpts...)
 		if err != nil {
 			return nil, err
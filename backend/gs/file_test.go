@@ -0,0 +1,177 @@
+package gs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3/backend/cache"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	cacheDir string
+	mu       sync.Mutex
+	objects  map[string]string
+}
+
+func (s *fileTestSuite) SetupTest() {
+	s.objects = map[string]string{"bucket/src/file.txt": "hello"}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	u, err := url.Parse(s.server.URL)
+	s.NoError(err)
+	s.NoError(os.Setenv(emulatorHostEnv, u.Host))
+
+	s.cacheDir, err = ioutil.TempDir("", "gs-cache")
+	s.NoError(err)
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.server.Close()
+	_ = os.Unsetenv(emulatorHostEnv)
+	s.NoError(os.RemoveAll(s.cacheDir))
+}
+
+// handle is a minimal stand-in for the storage JSON and XML APIs, keeping objects in memory.
+func (s *fileTestSuite) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/"):
+		bucket := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/upload/storage/v1/b/"), "/", 2)[0]
+		name, content, err := readUpload(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.objects[bucket+"/"+name] = content
+		s.writeAttrs(w, bucket, name)
+	case strings.HasPrefix(r.URL.Path, "/storage/v1/b/"):
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/storage/v1/b/"), "/o/", 2)
+		if len(parts) != 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.writeAttrs(w, parts[0], parts[1])
+	default:
+		content, ok := s.objects[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}
+}
+
+func (s *fileTestSuite) writeAttrs(w http.ResponseWriter, bucket, name string) {
+	w.Header().Set("Content-Type", "application/json")
+	content, ok := s.objects[bucket+"/"+name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Not Found"}}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"bucket":     bucket,
+		"name":       name,
+		"size":       fmt.Sprint(len(content)),
+		"generation": fmt.Sprint(len(s.objects)),
+		"updated":    "2019-04-01T12:00:00Z",
+	})
+}
+
+// readUpload returns the object name and content of a multipart upload.
+func readUpload(r *http.Request) (string, string, error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", "", err
+	}
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	var metadata struct {
+		Name string `json:"name"`
+	}
+	part, err := reader.NextPart()
+	if err != nil {
+		return "", "", err
+	}
+	if err := json.NewDecoder(part).Decode(&metadata); err != nil {
+		return "", "", err
+	}
+	part, err = reader.NextPart()
+	if err != nil {
+		return "", "", err
+	}
+	content, err := ioutil.ReadAll(part)
+	return metadata.Name, string(content), err
+}
+
+func (s *fileTestSuite) TestCopyToCachedLocation() {
+	fs := NewFileSystem()
+	file, err := fs.NewFile("bucket", "/src/file.txt")
+	s.NoError(err)
+
+	// a cache location has the gs scheme, but its files aren't gs Files
+	cached := cache.NewFileSystem(fs).WithOptions(cache.Options{Dir: s.cacheDir})
+	location, err := cached.NewLocation("bucket", "/dest/")
+	s.NoError(err)
+
+	newFile, err := file.CopyToLocation(location)
+	s.NoError(err)
+	s.Equal("gs://bucket/dest/file.txt", newFile.URI())
+	s.Equal("hello", s.objects["bucket/dest/file.txt"])
+
+	target, err := cached.NewFile("bucket", "/dest/copy.txt")
+	s.NoError(err)
+	s.NoError(file.CopyToFile(target))
+	s.Equal("hello", s.objects["bucket/dest/copy.txt"])
+}
+
+func (s *fileTestSuite) TestReadFromStreamed() {
+	file, err := NewFileSystem().NewFile("bucket", "/src/file.txt")
+	s.NoError(err)
+	content, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("hello", string(content))
+
+	// readers without WriteTo, so that io.Copy calls ReadFrom
+	_, err = io.Copy(file, struct{ io.Reader }{strings.NewReader("first")})
+	s.NoError(err)
+	_, err = io.Copy(file, struct{ io.Reader }{strings.NewReader("second")})
+	s.Error(err, "a second io.Copy doesn't replace the first")
+	_, err = file.Write([]byte("third"))
+	s.Error(err, "a Write doesn't replace what was streamed")
+	s.Equal("first", s.objects["bucket/src/file.txt"])
+
+	content, err = ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("first", string(content), "content read before streaming isn't served")
+	s.NoError(file.Close())
+	s.Equal("first", s.objects["bucket/src/file.txt"])
+
+	_, err = io.Copy(file, struct{ io.Reader }{strings.NewReader("again")})
+	s.NoError(err, "a closed file can be streamed to again")
+	s.NoError(file.Close())
+	s.Equal("again", s.objects["bucket/src/file.txt"])
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
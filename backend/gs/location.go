@@ -65,6 +65,38 @@ func (l *Location) ListByPrefix(filenamePrefix string) ([]string, error) {
 	return fileNames, nil
 }
 
+// ListDirs returns the names of the "directories" directly inside the location, ie: the distinct next path elements of
+// object names with more than one path element after the location's prefix.
+func (l *Location) ListDirs() ([]string, error) {
+	q := &storage.Query{
+		Delimiter: "/",
+		Prefix:    l.prefix,
+		Versions:  false,
+	}
+
+	handle, err := l.getBucketHandle()
+	if err != nil {
+		return nil, err
+	}
+
+	it := handle.Objects(l.fileSystem.ctx, q)
+
+	var dirs []string
+	for {
+		objAttrs, err := it.Next()
+		if err != nil {
+			if err == iterator.Done {
+				break
+			}
+			return nil, err
+		}
+		if objAttrs.Prefix != "" {
+			dirs = append(dirs, strings.TrimSuffix(strings.TrimPrefix(objAttrs.Prefix, l.prefix), "/"))
+		}
+	}
+	return dirs, nil
+}
+
 // ListByRegex returns a list of file names at the location which match the provided regular expression.
 func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
 	keys, err := l.List()
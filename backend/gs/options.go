@@ -1,34 +1,166 @@
 package gs
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
 
 	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
 )
 
+// EnvPrefix is the prefix of the environment variables read by OptionsFromEnv for the default gs FileSystem.
+const EnvPrefix = "VFS_GS_"
+
+// emulatorHostEnv is the environment variable used by Google's client libraries to point at a storage emulator such
+// as fake-gcs-server, ie: STORAGE_EMULATOR_HOST=localhost:4443
+const emulatorHostEnv = "STORAGE_EMULATOR_HOST"
+
+// storageHost is the host used by the storage client for object reads.
+const storageHost = "storage.googleapis.com"
+
 // Options holds Google Cloud Storage -specific options.  Currently only client options are used.
+//
+// All options may be combined. When more than one source of credentials is given, APIKey takes precedence over
+// CredentialFile, which takes precedence over CredentialJSON. WithoutAuthentication ignores all of them.
 type Options struct {
-	APIKey         string   `json:"apiKey,omitempty"`
-	CredentialFile string   `json:"credentialFilePath,omitempty"`
-	Endpoint       string   `json:"endpoint,omitempty"`
-	Scopes         []string `json:"WithoutAuthentication,omitempty"`
+	APIKey         string `json:"apiKey,omitempty"`
+	CredentialFile string `json:"credentialFilePath,omitempty"`
+	// CredentialJSON is the content of a service account or authorized user JSON key file.
+	CredentialJSON json.RawMessage `json:"credentialJSON,omitempty"`
+	Endpoint       string          `json:"endpoint,omitempty"`
+	Scopes         []string        `json:"scopes,omitempty"`
+	// WithoutAuthentication sends requests without any credentials, for public buckets or emulators.
+	WithoutAuthentication bool `json:"withoutAuthentication,omitempty"`
+	// UserProject is the project billed for requests, which is required to access requester pays buckets.
+	UserProject string `json:"userProject,omitempty"`
 }
 
-func parseClientOptions(opts vfs.Options) []option.ClientOption {
+// OptionsFromEnv returns opts with each option that's set in the environment replaced. Variables are named prefix
+// followed by:
+//
+//   API_KEY, CREDENTIALS_FILE, CREDENTIALS_JSON, ENDPOINT, SCOPES, WITHOUT_AUTHENTICATION, USER_PROJECT
+//
+// ie: VFS_GS_CREDENTIALS_FILE with EnvPrefix. SCOPES is a comma separated list.
+func OptionsFromEnv(prefix string, opts Options) (Options, error) {
+	env := &utils.EnvReader{Prefix: prefix}
+	credentialJSON := ""
+	env.String("API_KEY", &opts.APIKey)
+	env.String("CREDENTIALS_FILE", &opts.CredentialFile)
+	env.String("CREDENTIALS_JSON", &credentialJSON)
+	env.String("ENDPOINT", &opts.Endpoint)
+	env.Strings("SCOPES", &opts.Scopes)
+	env.Bool("WITHOUT_AUTHENTICATION", &opts.WithoutAuthentication)
+	env.String("USER_PROJECT", &opts.UserProject)
+	if credentialJSON != "" {
+		opts.CredentialJSON = json.RawMessage(credentialJSON)
+	}
+	return opts, env.Err()
+}
+
+// parseClientOptions converts gs.Options (any other vfs.Options are ignored) into storage client options. When the
+// STORAGE_EMULATOR_HOST environment variable is set, all requests are sent to the emulator without authentication.
+func parseClientOptions(ctx context.Context, opts vfs.Options) ([]option.ClientOption, error) {
+	gsOpts, _ := opts.(Options)
+	emulatorHost := os.Getenv(emulatorHostEnv)
+
 	var googleClientOpts []option.ClientOption
+	endpoint := gsOpts.Endpoint
+	if endpoint == "" && emulatorHost != "" {
+		endpoint = "http://" + emulatorHost + "/storage/v1/"
+	}
+	if endpoint != "" {
+		googleClientOpts = append(googleClientOpts, option.WithEndpoint(endpoint))
+	}
+
+	withoutAuthentication := gsOpts.WithoutAuthentication || emulatorHost != ""
+	if !withoutAuthentication {
+		// mirror storage.NewClient's default scope so it isn't lost when the http client is built here
+		scopes := gsOpts.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{storage.ScopeFullControl}
+		}
+		googleClientOpts = append(googleClientOpts, option.WithScopes(scopes...))
+
+		if len(gsOpts.CredentialJSON) > 0 {
+			creds, err := google.CredentialsFromJSON(ctx, gsOpts.CredentialJSON, scopes...)
+			if err != nil {
+				return nil, err
+			}
+			googleClientOpts = append(googleClientOpts, option.WithTokenSource(creds.TokenSource))
+		}
+		if gsOpts.CredentialFile != "" {
+			googleClientOpts = append(googleClientOpts, option.WithServiceAccountFile(gsOpts.CredentialFile))
+		}
+		if gsOpts.APIKey != "" {
+			googleClientOpts = append(googleClientOpts, option.WithAPIKey(gsOpts.APIKey))
+		}
+	}
+
+	// nothing to add to requests, so the storage client can build its own http client from the options
+	if !withoutAuthentication && gsOpts.UserProject == "" {
+		return googleClientOpts, nil
+	}
+
+	var base http.RoundTripper = http.DefaultTransport
+	if !withoutAuthentication {
+		authenticated, _, err := transport.NewHTTPClient(ctx, googleClientOpts...)
+		if err != nil {
+			return nil, err
+		}
+		base = authenticated.Transport
+	}
+
+	client := &http.Client{
+		Transport: &requestTransport{
+			base:         base,
+			userProject:  gsOpts.UserProject,
+			emulatorHost: emulatorHost,
+		},
+	}
+	googleClientOpts = append(googleClientOpts, option.WithHTTPClient(client))
+	return googleClientOpts, nil
+}
+
+// requestTransport adds what the storage client can't to each request: the userProject parameter for requester pays
+// buckets, and redirection of object reads and uploads to an emulator.
+type requestTransport struct {
+	base         http.RoundTripper
+	userProject  string
+	emulatorHost string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *requestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := new(http.Request)
+	*r = *req
+	u := *req.URL
+	r.URL = &u
 
-	// we only care about 'gs.Options' types, skip anything else
-	if opts, ok := opts.(Options); ok {
-		switch {
-		case opts.APIKey != "":
-			googleClientOpts = append(googleClientOpts, option.WithAPIKey(opts.APIKey))
-		case opts.CredentialFile != "":
-			googleClientOpts = append(googleClientOpts, option.WithServiceAccountFile(opts.CredentialFile))
-		case opts.Endpoint != "":
-			googleClientOpts = append(googleClientOpts, option.WithEndpoint(opts.Endpoint))
-		case len(opts.Scopes) > 0:
-			googleClientOpts = append(googleClientOpts, option.WithScopes(opts.Scopes...))
+	if t.userProject != "" {
+		q := u.Query()
+		q.Set("userProject", t.userProject)
+		u.RawQuery = q.Encode()
+	}
+
+	if t.emulatorHost != "" {
+		if u.Host == storageHost {
+			u.Scheme = "http"
+			u.Host = t.emulatorHost
+			r.Host = t.emulatorHost
+		}
+		// the storage client only rewrites upload urls for the default endpoint
+		if u.Host == t.emulatorHost && u.Query().Get("uploadType") != "" && strings.HasPrefix(u.Path, "/storage/") {
+			u.Path = "/upload" + u.Path
 		}
 	}
-	return googleClientOpts
+
+	return t.base.RoundTrip(r)
 }
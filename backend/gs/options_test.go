@@ -0,0 +1,182 @@
+package gs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type optionsTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (s *optionsTestSuite) SetupTest() {
+	s.requests = nil
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	_ = os.Unsetenv(emulatorHostEnv)
+}
+
+func (s *optionsTestSuite) TearDownTest() {
+	s.server.Close()
+	_ = os.Unsetenv(emulatorHostEnv)
+}
+
+// handle is a minimal stand-in for the storage JSON and XML APIs, plus an oauth2 token endpoint.
+func (s *optionsTestSuite) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/token":
+		_, _ = w.Write([]byte(`{"access_token":"secret-token","token_type":"Bearer","expires_in":3600}`))
+	case strings.HasPrefix(r.URL.Path, "/storage/v1/b/"), strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/"):
+		_, _ = ioutil.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"bucket":"bucket","name":"file.txt","size":"5","updated":"2019-04-01T12:00:00Z"}`))
+	case r.URL.Path == "/bucket/file.txt":
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("hello"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *optionsTestSuite) host() string {
+	u, _ := url.Parse(s.server.URL)
+	return u.Host
+}
+
+func (s *optionsTestSuite) TestJSONTags() {
+	data, err := json.Marshal(Options{Scopes: []string{"scope"}, WithoutAuthentication: true, UserProject: "p"})
+	s.NoError(err)
+	s.Equal(`{"scopes":["scope"],"withoutAuthentication":true,"userProject":"p"}`, string(data))
+}
+
+func (s *optionsTestSuite) TestEmulatorWithUserProject() {
+	s.NoError(os.Setenv(emulatorHostEnv, s.host()))
+	fs := NewFileSystem().WithOptions(Options{UserProject: "billing-project"})
+
+	file, err := fs.NewFile("bucket", "/file.txt")
+	s.NoError(err)
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("hello", string(contents))
+	s.NoError(file.Close())
+
+	_, err = file.Write([]byte("hello"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	s.Require().Len(s.requests, 3)
+	s.Equal("/storage/v1/b/bucket/o/file.txt", s.requests[0].URL.Path, "attrs sent to emulator")
+	s.Equal("/bucket/file.txt", s.requests[1].URL.Path, "reads sent to emulator")
+	s.Equal("/upload/storage/v1/b/bucket/o", s.requests[2].URL.Path, "uploads sent to emulator")
+	for _, r := range s.requests {
+		s.Equal("billing-project", r.URL.Query().Get("userProject"), "user project on every request")
+		s.Empty(r.Header.Get("Authorization"), "emulator requests are unauthenticated")
+	}
+}
+
+func (s *optionsTestSuite) TestCredentialJSONWithEndpoint() {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	s.NoError(err)
+	credentials, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "vfs@example.iam.gserviceaccount.com",
+		"private_key_id": "1",
+		"private_key": string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		})),
+		"token_uri": s.server.URL + "/token",
+	})
+	s.NoError(err)
+
+	fs := NewFileSystem().WithOptions(Options{
+		CredentialJSON: credentials,
+		Endpoint:       s.server.URL + "/storage/v1/",
+	})
+	file, err := fs.NewFile("bucket", "/file.txt")
+	s.NoError(err)
+	size, err := file.Size()
+	s.NoError(err, "both credentials and endpoint applied")
+	s.Equal(uint64(5), size)
+
+	s.Require().Len(s.requests, 2)
+	s.Equal("/token", s.requests[0].URL.Path, "token fetched with json credentials")
+	s.Equal("/storage/v1/b/bucket/o/file.txt", s.requests[1].URL.Path, "request sent to endpoint")
+	s.Equal("Bearer secret-token", s.requests[1].Header.Get("Authorization"))
+}
+
+func (s *optionsTestSuite) TestWithoutAuthentication() {
+	fs := NewFileSystem().WithOptions(Options{
+		WithoutAuthentication: true,
+		Endpoint:              s.server.URL + "/storage/v1/",
+	})
+	file, err := fs.NewFile("bucket", "/file.txt")
+	s.NoError(err)
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.True(exists)
+	s.Require().Len(s.requests, 1)
+	s.Empty(s.requests[0].Header.Get("Authorization"))
+	s.Empty(s.requests[0].URL.Query().Get("userProject"))
+}
+
+func (s *optionsTestSuite) TestBadCredentialJSON() {
+	fs := NewFileSystem().WithOptions(Options{CredentialJSON: []byte("not json")})
+	_, err := fs.Client()
+	s.Error(err)
+}
+
+func (s *optionsTestSuite) TestOptionsFromEnv() {
+	defer func() {
+		for _, name := range []string{"CREDENTIALS_FILE", "CREDENTIALS_JSON", "SCOPES", "WITHOUT_AUTHENTICATION"} {
+			_ = os.Unsetenv(EnvPrefix + name)
+		}
+	}()
+	s.NoError(os.Setenv("VFS_GS_CREDENTIALS_FILE", "/secrets/key.json"))
+	s.NoError(os.Setenv("VFS_GS_CREDENTIALS_JSON", `{"type": "service_account"}`))
+	s.NoError(os.Setenv("VFS_GS_SCOPES", "a, b"))
+
+	opts, err := OptionsFromEnv(EnvPrefix, Options{UserProject: "billing"})
+	s.NoError(err)
+	s.Equal(Options{
+		CredentialFile: "/secrets/key.json",
+		CredentialJSON: json.RawMessage(`{"type": "service_account"}`),
+		Scopes:         []string{"a", "b"},
+		UserProject:    "billing",
+	}, opts)
+
+	s.NoError(os.Setenv("VFS_GS_WITHOUT_AUTHENTICATION", "maybe"))
+	_, err = OptionsFromEnv(EnvPrefix, Options{})
+	s.Error(err)
+}
+
+func TestOptions(t *testing.T) {
+	suite.Run(t, new(optionsTestSuite))
+}
@@ -0,0 +1,79 @@
+/*
+Package instrument metrics and tracing VFS implementation.
+
+An instrument FileSystem wraps another vfs.FileSystem and reports each operation on its files and locations, such as
+a Read, an Exists or a List, to an Observer as an Event. Events have the op, the scheme and volume of the wrapped
+FileSystem, ie: "s3" and "mybucket", the start time and duration, the bytes read, written or copied and any error.
+
+Usage
+
+Instrument filesystems wrap another FileSystem so they are not registered with backend. Wrap a registered backend
+directly:
+
+  import(
+      "net/http"
+
+      "github.com/c2fo/vfs/v3/backend"
+      "github.com/c2fo/vfs/v3/backend/instrument"
+      _ "github.com/c2fo/vfs/v3/backend/s3"
+  )
+
+  var metrics = instrument.NewMetrics()
+
+  func init() {
+      http.Handle("/metrics/vfs", metrics)
+  }
+
+  func DoSomething() error {
+      fs := instrument.NewFileSystem(backend.Backend("s3"), metrics)
+      file, err := fs.NewFile("mybucket", "/path/to/file.txt")
+      ...
+  }
+
+Observers
+
+Observer is a single method interface, and ObserverFunc adapts a function to it. MultiObserver passes each Event to
+several observers. Two observers are included:
+
+Metrics aggregates Events into Prometheus-style counters and a duration histogram, labeled by op, scheme and volume,
+and serves them in the Prometheus text exposition format. It has no dependency on a Prometheus client library.
+
+NewTracingObserver records a span for each Event through a Tracer. Tracer and Span mirror the parts of the
+OpenTelemetry API they need, so an adapter is short:
+
+  type otelTracer struct {
+      tracer trace.Tracer
+  }
+
+  func (t otelTracer) Start(name string, start time.Time, attributes map[string]string) instrument.Span {
+      kvs := make([]attribute.KeyValue, 0, len(attributes))
+      for k, v := range attributes {
+          kvs = append(kvs, attribute.String(k, v))
+      }
+      _, span := t.tracer.Start(context.Background(), name, trace.WithTimestamp(start), trace.WithAttributes(kvs...))
+      return otelSpan{span}
+  }
+
+  type otelSpan struct {
+      span trace.Span
+  }
+
+  func (s otelSpan) RecordError(err error) {
+      s.span.RecordError(err)
+      s.span.SetStatus(codes.Error, err.Error())
+  }
+
+  func (s otelSpan) End(end time.Time) {
+      s.span.End(trace.WithTimestamp(end))
+  }
+
+  observer := instrument.NewTracingObserver(otelTracer{otel.Tracer("vfs")}, instrument.OpList, instrument.OpCopy)
+
+Scheme
+
+The scheme is the wrapped FileSystem's scheme, since files are unchanged, and copies and moves unwrap instrumented
+targets, so backends still copy natively between instrumented files. A copy or move is then one OpCopy or OpMove event,
+with the file's size as its Bytes, whether the backend copied natively or not: its reads and writes aren't observed,
+so they aren't in the read and written byte counts.
+*/
+package instrument
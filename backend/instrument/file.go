@@ -0,0 +1,203 @@
+package instrument
+
+import (
+	"io"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for an instrumented file.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+	volume     string
+}
+
+// Unwrap returns the instrumented file.
+func (f *File) Unwrap() vfs.File {
+	return f.file
+}
+
+// Info Functions
+
+// LastModified calls LastModified on the wrapped file, observed as OpLastModified.
+func (f *File) LastModified() (*time.Time, error) {
+	start := time.Now()
+	modified, err := f.file.LastModified()
+	f.fileSystem.observe(OpLastModified, f.volume, start, 0, err)
+	return modified, err
+}
+
+// Name returns the name of the wrapped file.
+func (f *File) Name() string {
+	return f.file.Name()
+}
+
+// Path returns the path of the wrapped file.
+func (f *File) Path() string {
+	return f.file.Path()
+}
+
+// Exists calls Exists on the wrapped file, observed as OpExists.
+func (f *File) Exists() (bool, error) {
+	start := time.Now()
+	exists, err := f.file.Exists()
+	f.fileSystem.observe(OpExists, f.volume, start, 0, err)
+	return exists, err
+}
+
+// Size calls Size on the wrapped file, observed as OpSize.
+func (f *File) Size() (uint64, error) {
+	start := time.Now()
+	size, err := f.file.Size()
+	f.fileSystem.observe(OpSize, f.volume, start, 0, err)
+	return size, err
+}
+
+// Location returns an instrument Location for the wrapped file's location.
+func (f *File) Location() vfs.Location {
+	return &Location{fileSystem: f.fileSystem, location: f.file.Location()}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile calls CopyToFile on the wrapped file, observed as OpCopy with the file's size. An instrumented target is
+// unwrapped first, so the backend may copy natively.
+func (f *File) CopyToFile(target vfs.File) error {
+	if t, ok := target.(*File); ok {
+		target = t.file
+	}
+	size := f.size()
+	start := time.Now()
+	err := f.file.CopyToFile(target)
+	f.fileSystem.observe(OpCopy, f.volume, start, copied(size, err), err)
+	return err
+}
+
+// CopyToLocation calls CopyToLocation on the wrapped file, observed as OpCopy with the file's size. An instrumented
+// location is unwrapped first, so the backend may copy natively, and the new file is instrumented like the location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	size := f.size()
+	start := time.Now()
+	newFile, err := f.toLocation(location, f.file.CopyToLocation)
+	f.fileSystem.observe(OpCopy, f.volume, start, copied(size, err), err)
+	return newFile, err
+}
+
+// MoveToFile calls MoveToFile on the wrapped file, observed as OpMove with the file's size. An instrumented target is
+// unwrapped first, so the backend may move natively.
+func (f *File) MoveToFile(target vfs.File) error {
+	if t, ok := target.(*File); ok {
+		target = t.file
+	}
+	size := f.size()
+	start := time.Now()
+	err := f.file.MoveToFile(target)
+	f.fileSystem.observe(OpMove, f.volume, start, copied(size, err), err)
+	return err
+}
+
+// MoveToLocation calls MoveToLocation on the wrapped file, observed as OpMove with the file's size. An instrumented
+// location is unwrapped first, so the backend may move natively, and the new file is instrumented like the location.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	size := f.size()
+	start := time.Now()
+	newFile, err := f.toLocation(location, f.file.MoveToLocation)
+	f.fileSystem.observe(OpMove, f.volume, start, copied(size, err), err)
+	return newFile, err
+}
+
+// CRUD Operations
+
+// Delete calls Delete on the wrapped file, observed as OpDelete.
+func (f *File) Delete() error {
+	start := time.Now()
+	err := f.file.Delete()
+	f.fileSystem.observe(OpDelete, f.volume, start, 0, err)
+	return err
+}
+
+// Close calls Close on the wrapped file, observed as OpClose. Closing a file that was written is when most backends
+// upload it, so OpClose durations include uploads.
+func (f *File) Close() error {
+	start := time.Now()
+	err := f.file.Close()
+	f.fileSystem.observe(OpClose, f.volume, start, 0, err)
+	return err
+}
+
+// Read implements the io.Reader interface, observed as OpRead with the number of bytes read.
+func (f *File) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := f.file.Read(p)
+	observed := err
+	if observed == io.EOF {
+		observed = nil
+	}
+	f.fileSystem.observe(OpRead, f.volume, start, n, observed)
+	return n, err
+}
+
+// Seek implements the io.Seeker interface, observed as OpSeek.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	start := time.Now()
+	pos, err := f.file.Seek(offset, whence)
+	f.fileSystem.observe(OpSeek, f.volume, start, 0, err)
+	return pos, err
+}
+
+// Write implements the io.Writer interface, observed as OpWrite with the number of bytes written.
+func (f *File) Write(data []byte) (int, error) {
+	start := time.Now()
+	n, err := f.file.Write(data)
+	f.fileSystem.observe(OpWrite, f.volume, start, n, err)
+	return n, err
+}
+
+/*
+	Private helpers
+*/
+
+// size returns the size of the wrapped file before it's copied or moved, unobserved, or 0 if it can't be found. It's
+// got first since a moved file no longer has one.
+func (f *File) size() int {
+	size, err := f.file.Size()
+	if err != nil {
+		return 0
+	}
+	return int(size)
+}
+
+// copied returns the bytes of a copy or move of a file of the given size, which are none if it failed.
+func copied(size int, err error) int {
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// toLocation calls fn, a CopyToLocation or MoveToLocation of the wrapped file, with the unwrapped location,
+// instrumenting the new file if the location was instrumented.
+func (f *File) toLocation(location vfs.Location, fn func(vfs.Location) (vfs.File, error)) (vfs.File, error) {
+	l, instrumented := location.(*Location)
+	if instrumented {
+		location = l.location
+	}
+	newFile, err := fn(location)
+	if newFile == nil || !instrumented {
+		return newFile, err
+	}
+	return l.fileSystem.wrapFile(newFile), err
+}
@@ -0,0 +1,86 @@
+package instrument
+
+import (
+	"errors"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// FileSystem implements vfs.Filesystem by passing an Event to an Observer for each operation on the files and
+// locations of another FileSystem.
+type FileSystem struct {
+	fileSystem vfs.FileSystem
+	observer   Observer
+}
+
+// NewFileSystem initializer returns a FileSystem which reports the operations on fileSystem to observer.
+func NewFileSystem(fileSystem vfs.FileSystem, observer Observer) *FileSystem {
+	return &FileSystem{fileSystem: fileSystem, observer: observer}
+}
+
+// Unwrap returns the instrumented FileSystem.
+func (fs *FileSystem) Unwrap() vfs.FileSystem {
+	return fs.fileSystem
+}
+
+// NewFile function returns the instrument implementation of vfs.File.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("instrument FileSystem requires a FileSystem")
+	}
+	file, err := fs.fileSystem.NewFile(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.wrapFile(file), nil
+}
+
+// NewLocation function returns the instrument implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("instrument FileSystem requires a FileSystem")
+	}
+	location, err := fs.fileSystem.NewLocation(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, location: location}, nil
+}
+
+// Name returns the name of the wrapped FileSystem prefixed with "instrumented", ie: "instrumented AWS S3"
+func (fs *FileSystem) Name() string {
+	if fs.fileSystem == nil {
+		return "instrumented"
+	}
+	return "instrumented " + fs.fileSystem.Name()
+}
+
+// Scheme returns the scheme of the wrapped FileSystem. Files are unchanged, so backends may copy to and from
+// instrumented files natively.
+func (fs *FileSystem) Scheme() string {
+	if fs.fileSystem == nil {
+		return ""
+	}
+	return fs.fileSystem.Scheme()
+}
+
+func (fs *FileSystem) wrapFile(file vfs.File) *File {
+	return &File{fileSystem: fs, file: file, volume: file.Location().Volume()}
+}
+
+// observe passes an Event for an operation on volume that began at start to the observer.
+func (fs *FileSystem) observe(op Op, volume string, start time.Time, bytes int, err error) {
+	if fs.observer == nil {
+		return
+	}
+	fs.observer.Observe(Event{
+		Op:       op,
+		Scheme:   fs.fileSystem.Scheme(),
+		Volume:   volume,
+		Start:    start,
+		Duration: time.Since(start),
+		Bytes:    bytes,
+		Err:      err,
+	})
+}
@@ -0,0 +1,158 @@
+package instrument
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) Observe(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) ops() []Op {
+	ops := make([]Op, len(r.events))
+	for i, event := range r.events {
+		ops[i] = event.Op
+	}
+	return ops
+}
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir   string
+	recorder *recorder
+	fs       *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "instrument-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.recorder = &recorder{}
+	s.fs = NewFileSystem(&_os.FileSystem{}, s.recorder)
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) TestReadWrite() {
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "data.txt"))
+	s.NoError(err)
+	s.Equal("file://"+filepath.Join(s.tmpDir, "data.txt"), file.URI())
+	s.Equal("instrumented os", file.Location().FileSystem().Name())
+
+	_, err = file.Write([]byte("hello"))
+	s.NoError(err)
+	_, err = file.Write([]byte(" world"))
+	s.NoError(err)
+	s.NoError(file.Close())
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("hello world", string(contents))
+
+	var read, written int
+	for _, event := range s.recorder.events {
+		s.Equal("file", event.Scheme)
+		s.NoError(event.Err, "io.EOF isn't an error")
+		s.False(event.Start.IsZero())
+		switch event.Op {
+		case OpRead:
+			read += event.Bytes
+		case OpWrite:
+			written += event.Bytes
+		}
+	}
+	s.Equal(11, read)
+	s.Equal(11, written)
+	s.Equal([]Op{OpWrite, OpWrite, OpClose}, s.recorder.ops()[:3])
+}
+
+func (s *fileTestSuite) TestErrors() {
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "missing.txt"))
+	s.NoError(err)
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists)
+	_, err = file.Size()
+	s.Error(err)
+
+	s.Equal([]Op{OpExists, OpSize}, s.recorder.ops())
+	s.NoError(s.recorder.events[0].Err)
+	s.Error(s.recorder.events[1].Err)
+}
+
+func (s *fileTestSuite) TestLocation() {
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "a.txt"), []byte("a"), 0644))
+	location, err := s.fs.NewLocation("", s.tmpDir+"/")
+	s.NoError(err)
+
+	names, err := location.List()
+	s.NoError(err)
+	s.Equal([]string{"a.txt"}, names)
+	_, err = location.ListByPrefix("a")
+	s.NoError(err)
+	exists, err := location.Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	file, err := location.NewFile("a.txt")
+	s.NoError(err)
+	sub, err := location.NewLocation("sub/")
+	s.NoError(err)
+	moved, err := file.MoveToLocation(sub)
+	s.NoError(err)
+	s.IsType(&File{}, moved, "new file in an instrumented location is instrumented")
+	s.NoError(sub.DeleteFile("a.txt"))
+
+	s.Equal([]Op{OpList, OpList, OpExists, OpMove, OpDelete}, s.recorder.ops())
+}
+
+func (s *fileTestSuite) TestCopyMoveBytes() {
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "a.txt"), []byte("hello"), 0644))
+	location, err := s.fs.NewLocation("", s.tmpDir+"/")
+	s.NoError(err)
+	file, err := location.NewFile("a.txt")
+	s.NoError(err)
+	sub, err := location.NewLocation("sub/")
+	s.NoError(err)
+
+	copied, err := file.CopyToLocation(sub)
+	s.NoError(err)
+	target, err := sub.NewFile("b.txt")
+	s.NoError(err)
+	s.NoError(copied.MoveToFile(target))
+	missing, err := location.NewFile("missing.txt")
+	s.NoError(err)
+	s.Error(missing.CopyToFile(target))
+
+	var bytes []int
+	for _, event := range s.recorder.events {
+		if event.Op == OpCopy || event.Op == OpMove {
+			bytes = append(bytes, event.Bytes)
+		}
+	}
+	s.Equal([]int{5, 5, 0}, bytes, "a copy or move has the file's size, a failed one has none")
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
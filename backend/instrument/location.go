@@ -0,0 +1,116 @@
+package instrument
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface for an instrumented location.
+type Location struct {
+	fileSystem *FileSystem
+	location   vfs.Location
+}
+
+// Unwrap returns the instrumented location.
+func (l *Location) Unwrap() vfs.Location {
+	return l.location
+}
+
+// List calls List on the wrapped location, observed as OpList.
+func (l *Location) List() ([]string, error) {
+	start := time.Now()
+	names, err := l.location.List()
+	l.fileSystem.observe(OpList, l.location.Volume(), start, 0, err)
+	return names, err
+}
+
+// ListByPrefix calls ListByPrefix on the wrapped location, observed as OpList.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	start := time.Now()
+	names, err := l.location.ListByPrefix(prefix)
+	l.fileSystem.observe(OpList, l.location.Volume(), start, 0, err)
+	return names, err
+}
+
+// ListByRegex calls ListByRegex on the wrapped location, observed as OpList.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	start := time.Now()
+	names, err := l.location.ListByRegex(regex)
+	l.fileSystem.observe(OpList, l.location.Volume(), start, 0, err)
+	return names, err
+}
+
+// ListDirs calls ListDirs on the wrapped location, which must implement utils.DirLister, observed as OpList.
+func (l *Location) ListDirs() ([]string, error) {
+	start := time.Now()
+	dirs, err := utils.ListDirs(l.location)
+	l.fileSystem.observe(OpList, l.location.Volume(), start, 0, err)
+	return dirs, err
+}
+
+// Volume returns the volume of the wrapped location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path returns the path of the wrapped location.
+func (l *Location) Path() string {
+	return l.location.Path()
+}
+
+// Exists calls Exists on the wrapped location, observed as OpExists.
+func (l *Location) Exists() (bool, error) {
+	start := time.Now()
+	exists, err := l.location.Exists()
+	l.fileSystem.observe(OpExists, l.location.Volume(), start, 0, err)
+	return exists, err
+}
+
+// NewLocation returns a new instrument Location relative to this one.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	location, err := l.location.NewLocation(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: l.fileSystem, location: location}, nil
+}
+
+// ChangeDir changes the directory of the wrapped location.
+func (l *Location) ChangeDir(relativePath string) error {
+	return l.location.ChangeDir(relativePath)
+}
+
+// FileSystem returns the instrument FileSystem the location was created by.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns an instrument File for fileName, relative to the location.
+func (l *Location) NewFile(fileName string) (vfs.File, error) {
+	file, err := l.location.NewFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return l.fileSystem.wrapFile(file), nil
+}
+
+// DeleteFile calls DeleteFile on the wrapped location, observed as OpDelete.
+func (l *Location) DeleteFile(fileName string) error {
+	start := time.Now()
+	err := l.location.DeleteFile(fileName)
+	l.fileSystem.observe(OpDelete, l.location.Volume(), start, 0, err)
+	return err
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
@@ -0,0 +1,248 @@
+package instrument
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the operation duration histogram when NewMetrics is given none.
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// Metrics is an Observer that aggregates Events into Prometheus-style counters and histograms, labeled by op, scheme
+// and volume. It serves them in the Prometheus text exposition format, so it can be scraped directly:
+//
+//	metrics := instrument.NewMetrics()
+//	fs := instrument.NewFileSystem(s3.NewFileSystem(), metrics)
+//	http.Handle("/metrics/vfs", metrics)
+//
+// The metrics are:
+//
+//	vfs_operations_total{op,scheme,volume}              counter
+//	vfs_operation_errors_total{op,scheme,volume}        counter
+//	vfs_operation_duration_seconds{op,scheme,volume}    histogram
+//	vfs_read_bytes_total{scheme,volume}                 counter
+//	vfs_written_bytes_total{scheme,volume}              counter
+type Metrics struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	operations map[operationKey]*operationStats
+	bytes      map[volumeKey]*byteStats
+}
+
+type operationKey struct {
+	op     Op
+	scheme string
+	volume string
+}
+
+type volumeKey struct {
+	scheme string
+	volume string
+}
+
+type operationStats struct {
+	count        uint64
+	errors       uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+type byteStats struct {
+	read    uint64
+	written uint64
+}
+
+// NewMetrics returns empty Metrics whose duration histogram has the given bucket upper bounds, in seconds, or
+// DefaultBuckets if none are given.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Metrics{
+		buckets:    sorted,
+		operations: make(map[operationKey]*operationStats),
+		bytes:      make(map[volumeKey]*byteStats),
+	}
+}
+
+// Observe adds the event to the metrics.
+func (m *Metrics) Observe(event Event) {
+	seconds := event.Duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := operationKey{op: event.Op, scheme: event.Scheme, volume: event.Volume}
+	stats, ok := m.operations[key]
+	if !ok {
+		stats = &operationStats{bucketCounts: make([]uint64, len(m.buckets))}
+		m.operations[key] = stats
+	}
+	stats.count++
+	if event.Err != nil {
+		stats.errors++
+	}
+	stats.sum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			stats.bucketCounts[i]++
+		}
+	}
+
+	if event.Bytes > 0 && (event.Op == OpRead || event.Op == OpWrite) {
+		vkey := volumeKey{scheme: event.Scheme, volume: event.Volume}
+		b, ok := m.bytes[vkey]
+		if !ok {
+			b = &byteStats{}
+			m.bytes[vkey] = b
+		}
+		if event.Op == OpRead {
+			b.read += uint64(event.Bytes)
+		} else {
+			b.written += uint64(event.Bytes)
+		}
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := m.WriteTo(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format, implementing io.WriterTo.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	opKeys := make([]operationKey, 0, len(m.operations))
+	ops := make(map[operationKey]operationStats, len(m.operations))
+	for key, stats := range m.operations {
+		opKeys = append(opKeys, key)
+		copied := *stats
+		copied.bucketCounts = append([]uint64(nil), stats.bucketCounts...)
+		ops[key] = copied
+	}
+	volKeys := make([]volumeKey, 0, len(m.bytes))
+	bytes := make(map[volumeKey]byteStats, len(m.bytes))
+	for key, stats := range m.bytes {
+		volKeys = append(volKeys, key)
+		bytes[key] = *stats
+	}
+	m.mu.Unlock()
+
+	sort.Slice(opKeys, func(i, j int) bool {
+		a, b := opKeys[i], opKeys[j]
+		if a.op != b.op {
+			return a.op < b.op
+		}
+		if a.scheme != b.scheme {
+			return a.scheme < b.scheme
+		}
+		return a.volume < b.volume
+	})
+	sort.Slice(volKeys, func(i, j int) bool {
+		a, b := volKeys[i], volKeys[j]
+		if a.scheme != b.scheme {
+			return a.scheme < b.scheme
+		}
+		return a.volume < b.volume
+	})
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	fmt.Fprintln(cw, "# HELP vfs_operations_total Number of vfs operations.")
+	fmt.Fprintln(cw, "# TYPE vfs_operations_total counter")
+	for _, key := range opKeys {
+		fmt.Fprintf(cw, "vfs_operations_total%s %d\n", key.labels(), ops[key].count)
+	}
+
+	fmt.Fprintln(cw, "# HELP vfs_operation_errors_total Number of vfs operations that returned an error.")
+	fmt.Fprintln(cw, "# TYPE vfs_operation_errors_total counter")
+	for _, key := range opKeys {
+		fmt.Fprintf(cw, "vfs_operation_errors_total%s %d\n", key.labels(), ops[key].errors)
+	}
+
+	fmt.Fprintln(cw, "# HELP vfs_operation_duration_seconds Duration of vfs operations.")
+	fmt.Fprintln(cw, "# TYPE vfs_operation_duration_seconds histogram")
+	for _, key := range opKeys {
+		stats := ops[key]
+		for i, bound := range m.buckets {
+			fmt.Fprintf(cw, "vfs_operation_duration_seconds_bucket%s %d\n",
+				key.labels(label{"le", formatFloat(bound)}), stats.bucketCounts[i])
+		}
+		fmt.Fprintf(cw, "vfs_operation_duration_seconds_bucket%s %d\n", key.labels(label{"le", "+Inf"}), stats.count)
+		fmt.Fprintf(cw, "vfs_operation_duration_seconds_sum%s %s\n", key.labels(), formatFloat(stats.sum))
+		fmt.Fprintf(cw, "vfs_operation_duration_seconds_count%s %d\n", key.labels(), stats.count)
+	}
+
+	fmt.Fprintln(cw, "# HELP vfs_read_bytes_total Number of bytes read from vfs files.")
+	fmt.Fprintln(cw, "# TYPE vfs_read_bytes_total counter")
+	for _, key := range volKeys {
+		fmt.Fprintf(cw, "vfs_read_bytes_total%s %d\n", key.labels(), bytes[key].read)
+	}
+
+	fmt.Fprintln(cw, "# HELP vfs_written_bytes_total Number of bytes written to vfs files.")
+	fmt.Fprintln(cw, "# TYPE vfs_written_bytes_total counter")
+	for _, key := range volKeys {
+		fmt.Fprintf(cw, "vfs_written_bytes_total%s %d\n", key.labels(), bytes[key].written)
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+func (k operationKey) labels(extra ...label) string {
+	return formatLabels(append([]label{{"op", string(k.op)}, {"scheme", k.scheme}, {"volume", k.volume}}, extra...))
+}
+
+func (k volumeKey) labels() string {
+	return formatLabels([]label{{"scheme", k.scheme}, {"volume", k.volume}})
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels []label) string {
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.name + `="` + labelEscaper.Replace(l.value) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countingWriter counts bytes written and keeps the first error, so WriteTo can write without checking each line.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
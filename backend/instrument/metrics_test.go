@@ -0,0 +1,99 @@
+package instrument
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type metricsTestSuite struct {
+	suite.Suite
+}
+
+func (s *metricsTestSuite) TestMetrics() {
+	metrics := NewMetrics(0.1, 1)
+	metrics.Observe(Event{Op: OpRead, Scheme: "s3", Volume: "bucket", Duration: 50 * time.Millisecond, Bytes: 100})
+	metrics.Observe(Event{Op: OpRead, Scheme: "s3", Volume: "bucket", Duration: 500 * time.Millisecond, Bytes: 20})
+	metrics.Observe(Event{Op: OpWrite, Scheme: "s3", Volume: "bucket", Duration: 2 * time.Second, Bytes: 7})
+	metrics.Observe(Event{Op: OpList, Scheme: "gs", Volume: `a"b`, Err: errors.New("denied")})
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	s.True(strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+	out := recorder.Body.String()
+
+	for _, line := range []string{
+		"# TYPE vfs_operations_total counter",
+		`vfs_operations_total{op="read",scheme="s3",volume="bucket"} 2`,
+		`vfs_operations_total{op="list",scheme="gs",volume="a\"b"} 1`,
+		`vfs_operation_errors_total{op="list",scheme="gs",volume="a\"b"} 1`,
+		`vfs_operation_errors_total{op="read",scheme="s3",volume="bucket"} 0`,
+		"# TYPE vfs_operation_duration_seconds histogram",
+		`vfs_operation_duration_seconds_bucket{op="read",scheme="s3",volume="bucket",le="0.1"} 1`,
+		`vfs_operation_duration_seconds_bucket{op="read",scheme="s3",volume="bucket",le="1"} 2`,
+		`vfs_operation_duration_seconds_bucket{op="write",scheme="s3",volume="bucket",le="1"} 0`,
+		`vfs_operation_duration_seconds_bucket{op="write",scheme="s3",volume="bucket",le="+Inf"} 1`,
+		`vfs_operation_duration_seconds_sum{op="read",scheme="s3",volume="bucket"} 0.55`,
+		`vfs_operation_duration_seconds_count{op="read",scheme="s3",volume="bucket"} 2`,
+		`vfs_read_bytes_total{scheme="s3",volume="bucket"} 120`,
+		`vfs_written_bytes_total{scheme="s3",volume="bucket"} 7`,
+	} {
+		s.Contains(out, line+"\n")
+	}
+	s.True(strings.Index(out, `op="list"`) < strings.Index(out, `op="read"`), "sorted by op")
+}
+
+type span struct {
+	name       string
+	start, end time.Time
+	attributes map[string]string
+	err        error
+}
+
+type tracer struct {
+	spans []*span
+}
+
+func (t *tracer) Start(name string, start time.Time, attributes map[string]string) Span {
+	sp := &span{name: name, start: start, attributes: attributes}
+	t.spans = append(t.spans, sp)
+	return sp
+}
+
+func (sp *span) RecordError(err error) { sp.err = err }
+func (sp *span) End(end time.Time)     { sp.end = end }
+
+func (s *metricsTestSuite) TestTracingObserver() {
+	t := &tracer{}
+	observer := NewTracingObserver(t, OpList, OpRead)
+	start := time.Now()
+	observer.Observe(Event{Op: OpList, Scheme: "s3", Volume: "bucket", Start: start, Duration: time.Second,
+		Err: errors.New("denied")})
+	observer.Observe(Event{Op: OpRead, Scheme: "s3", Volume: "bucket", Start: start, Bytes: 3})
+	observer.Observe(Event{Op: OpExists, Scheme: "s3", Volume: "bucket", Start: start})
+
+	s.Len(t.spans, 2, "only the given ops are traced")
+	s.Equal("vfs.list", t.spans[0].name)
+	s.Equal(start, t.spans[0].start)
+	s.Equal(start.Add(time.Second), t.spans[0].end)
+	s.EqualError(t.spans[0].err, "denied")
+	s.Equal(map[string]string{"vfs.scheme": "s3", "vfs.volume": "bucket"}, t.spans[0].attributes)
+	s.Equal("3", t.spans[1].attributes["vfs.bytes"])
+	s.NoError(t.spans[1].err)
+
+	all := &tracer{}
+	MultiObserver(NewTracingObserver(all), ObserverFunc(func(Event) {})).Observe(Event{Op: OpExists})
+	s.Len(all.spans, 1, "every op is traced when none are given")
+}
+
+func TestMetrics(t *testing.T) {
+	suite.Run(t, new(metricsTestSuite))
+}
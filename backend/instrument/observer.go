@@ -0,0 +1,70 @@
+package instrument
+
+import (
+	"time"
+)
+
+// Op names an instrumented operation.
+type Op string
+
+// Instrumented operations. Location and File operations of the same kind share an Op, ie: Location.DeleteFile and
+// File.Delete are both OpDelete.
+const (
+	OpRead         Op = "read"
+	OpWrite        Op = "write"
+	OpSeek         Op = "seek"
+	OpClose        Op = "close"
+	OpExists       Op = "exists"
+	OpSize         Op = "size"
+	OpLastModified Op = "last_modified"
+	OpDelete       Op = "delete"
+	OpCopy         Op = "copy"
+	OpMove         Op = "move"
+	OpList         Op = "list"
+)
+
+// Event describes one completed operation.
+type Event struct {
+	Op Op
+
+	// Scheme and Volume of the wrapped FileSystem the operation was on, ie: "s3" and "mybucket"
+	Scheme string
+	Volume string
+
+	Start    time.Time
+	Duration time.Duration
+
+	// Bytes is the number of bytes read or written, for OpRead and OpWrite, or the size of the file copied or moved, for
+	// a successful OpCopy or OpMove.
+	Bytes int
+
+	// Err is the error returned by the operation, if any. io.EOF from Read isn't an error.
+	Err error
+}
+
+// Observer receives an Event for every operation on an instrumented FileSystem. Observe is called synchronously, once
+// per call to Read and Write among others, so it should be cheap and safe for concurrent use.
+type Observer interface {
+	Observe(event Event)
+}
+
+// ObserverFunc adapts a function to an Observer.
+type ObserverFunc func(event Event)
+
+// Observe calls fn(event).
+func (fn ObserverFunc) Observe(event Event) {
+	fn(event)
+}
+
+// MultiObserver returns an Observer passing each Event to all of observers, in order.
+func MultiObserver(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) Observe(event Event) {
+	for _, observer := range m {
+		observer.Observe(event)
+	}
+}
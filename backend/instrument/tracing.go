@@ -0,0 +1,58 @@
+package instrument
+
+import (
+	"strconv"
+	"time"
+)
+
+// Tracer creates spans, in the style of an OpenTelemetry trace.Tracer. Spans are created once the operation has
+// completed, so start is in the past. See the package documentation for an OpenTelemetry adapter.
+type Tracer interface {
+	Start(name string, start time.Time, attributes map[string]string) Span
+}
+
+// Span is the part of an OpenTelemetry trace.Span the tracing Observer uses.
+type Span interface {
+	RecordError(err error)
+	End(end time.Time)
+}
+
+// NewTracingObserver returns an Observer that records a span named "vfs." followed by the op, ie: "vfs.list", with
+// tracer for each Event of the given ops, or of every op when none are given. Since Read and Write are observed per
+// call, tracing them can create a great many spans.
+//
+// Span attributes are "vfs.scheme", "vfs.volume" and, for OpRead, OpWrite, OpCopy and OpMove, "vfs.bytes".
+func NewTracingObserver(tracer Tracer, ops ...Op) Observer {
+	t := &tracingObserver{tracer: tracer}
+	if len(ops) > 0 {
+		t.ops = make(map[Op]bool, len(ops))
+		for _, op := range ops {
+			t.ops[op] = true
+		}
+	}
+	return t
+}
+
+type tracingObserver struct {
+	tracer Tracer
+	ops    map[Op]bool
+}
+
+func (t *tracingObserver) Observe(event Event) {
+	if t.ops != nil && !t.ops[event.Op] {
+		return
+	}
+	attributes := map[string]string{
+		"vfs.scheme": event.Scheme,
+		"vfs.volume": event.Volume,
+	}
+	switch event.Op {
+	case OpRead, OpWrite, OpCopy, OpMove:
+		attributes["vfs.bytes"] = strconv.Itoa(event.Bytes)
+	}
+	span := t.tracer.Start("vfs."+string(event.Op), event.Start, attributes)
+	if event.Err != nil {
+		span.RecordError(event.Err)
+	}
+	span.End(event.Start.Add(event.Duration))
+}
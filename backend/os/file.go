@@ -1,7 +1,10 @@
 package os
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -74,6 +77,21 @@ func (f *File) Size() (uint64, error) {
 	return uint64(stats.Size()), err
 }
 
+// MD5 returns the hex encoded MD5 of the file's content, which is calculated by reading the whole file.
+func (f *File) MD5() (string, error) {
+	file, err := os.Open(f.Path())
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // Close implements the io.Closer interface, closing the underlying *os.File. its an error, if any.
 func (f *File) Close() error {
 	if f.file == nil {
@@ -96,7 +114,7 @@ func (f *File) Read(p []byte) (int, error) {
 		return 0, fmt.Errorf("failed to read. File does not exist at %s", f)
 	}
 
-	file, err := f.openFile()
+	file, err := f.openFile(0)
 	if err != nil {
 		return 0, err
 	}
@@ -108,7 +126,7 @@ func (f *File) Read(p []byte) (int, error) {
 // the file, 1 means relative to the current offset, and 2 means relative to the end.  It returns the new offset and
 // an error, if any.
 func (f *File) Seek(offset int64, whence int) (int64, error) {
-	file, err := f.openFile()
+	file, err := f.openFile(0)
 	if err != nil {
 		return 0, err
 	}
@@ -132,8 +150,10 @@ func (f *File) Exists() (bool, error) {
 }
 
 //Write implements the io.Writer interface.  It accepts a slice of bytes and returns the number of bytes written and an error, if any.
+// Writing to a file that hasn't been read or seeked since it was last closed replaces its content, as it does on other
+// backends.
 func (f *File) Write(p []byte) (n int, err error) {
-	file, err := f.openFile()
+	file, err := f.openFile(os.O_TRUNC)
 	if err != nil {
 		return 0, err
 	}
@@ -215,7 +235,8 @@ func (f *File) copyWithName(name string, location vfs.Location) (vfs.File, error
 	return newFile, nil
 }
 
-func (f *File) openFile() (*os.File, error) {
+// openFile returns the open file, opening it with flag added to os.O_RDWR|os.O_CREATE if it isn't already open.
+func (f *File) openFile(flag int) (*os.File, error) {
 	if f.file != nil {
 		return f.file, nil
 	}
@@ -226,7 +247,7 @@ func (f *File) openFile() (*os.File, error) {
 		return nil, err
 	}
 
-	file, err := os.OpenFile(f.Path(), os.O_RDWR|os.O_CREATE, fileMode)
+	file, err := os.OpenFile(f.Path(), os.O_RDWR|os.O_CREATE|flag, fileMode)
 	f.file = file
 	return file, err
 }
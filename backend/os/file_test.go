@@ -1,6 +1,9 @@
 package os
 
 import (
+	"crypto/md5"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -317,6 +320,33 @@ func (s *osFileTest) TestWrite() {
 	s.False(found2)
 }
 
+func (s *osFileTest) TestWriteReplaces() {
+	file, err := s.fileSystem.NewFile("", "test_files/replaced.txt")
+	s.NoError(err)
+	defer func() { s.NoError(file.Delete()) }()
+
+	_, err = file.Write([]byte("longer content"))
+	s.NoError(err)
+	s.NoError(file.Close())
+	_, err = file.Write([]byte("short"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	data, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("short", string(data), "writing replaces the content rather than overwriting the start of it")
+
+	_, err = file.Seek(0, io.SeekEnd)
+	s.NoError(err)
+	_, err = file.Write([]byte(" appended"))
+	s.NoError(err)
+	s.NoError(file.Close())
+	data, err = ioutil.ReadAll(file)
+	s.NoError(err)
+	s.NoError(file.Close())
+	s.Equal("short appended", string(data), "writing after a seek keeps the content")
+}
+
 func (s *osFileTest) TestLastModified() {
 	file, _ := s.fileSystem.NewFile("", "test_files/test.txt")
 
@@ -355,6 +385,16 @@ func (s *osFileTest) TestSize() {
 	s.Equal(osStats.Size(), int64(size))
 }
 
+func (s *osFileTest) TestMD5() {
+	file, _ := s.fileSystem.NewFile("", "test_files/test.txt")
+	contents, err := ioutil.ReadFile("test_files/test.txt")
+	s.NoError(err)
+
+	sum, err := file.(*File).MD5()
+	s.NoError(err)
+	s.Equal(fmt.Sprintf("%x", md5.Sum(contents)), sum)
+}
+
 func (s *osFileTest) TestPath() {
 	file, _ := s.fileSystem.NewFile("", "test_files/test.txt")
 	s.Equal(filepath.Join(file.Location().Path(), file.Name()), file.Path())
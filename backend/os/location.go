@@ -58,6 +58,26 @@ func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
 	})
 }
 
+// ListDirs returns the names of the directories in the top directory of the location.
+func (l *Location) ListDirs() ([]string, error) {
+	dirs := make([]string, 0)
+	exists, err := l.Exists()
+	if err != nil || !exists {
+		return dirs, err
+	}
+
+	entries, err := ioutil.ReadDir(l.Path())
+	if err != nil {
+		return dirs, err
+	}
+	for _, info := range entries {
+		if info.IsDir() {
+			dirs = append(dirs, info.Name())
+		}
+	}
+	return dirs, nil
+}
+
 func (l *Location) fileList(testEval fileTest) ([]string, error) {
 	files := make([]string, 0)
 	exists, err := l.Exists()
@@ -0,0 +1,31 @@
+/*
+Package readonly read-only VFS implementation.
+
+A readonly FileSystem wraps any other vfs.FileSystem so that its files and locations can be read but not changed.
+Write, Delete, MoveToFile, MoveToLocation and Location.DeleteFile return a *PermissionError, which IsPermission
+reports. Copying from a read-only file is allowed; copying to one fails.
+
+The wrapped FileSystem, Locations and Files are never exposed, so read-only views can safely be handed to code that
+shouldn't make changes:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend/readonly"
+      "github.com/c2fo/vfs/v3/vfssimple"
+  )
+
+  func RunPlugin(p Plugin) error {
+      location, err := vfssimple.NewLocation("s3://mybucket/reference/")
+      if err != nil {
+          return err
+      }
+      return p.Run(readonly.WrapLocation(location))
+  }
+
+Combine with the chroot package to also stop the code from reading outside a base location.
+
+Scheme
+
+The scheme of a readonly FileSystem is the wrapped scheme prefixed with "readonly+", ie: readonly+s3://mybucket/file.txt.
+The distinct scheme stops backends from using their native copy to write to a read-only location.
+*/
+package readonly
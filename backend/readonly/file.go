@@ -0,0 +1,113 @@
+package readonly
+
+import (
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface as a read-only view of a file on the wrapped FileSystem.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+}
+
+// Info Functions
+
+// LastModified returns the timestamp of the wrapped file.
+func (f *File) LastModified() (*time.Time, error) {
+	return f.file.LastModified()
+}
+
+// Name returns the base name of the file.
+func (f *File) Name() string {
+	return f.file.Name()
+}
+
+// Path returns the full path of the file.
+func (f *File) Path() string {
+	return f.file.Path()
+}
+
+// Exists returns whether the wrapped file exists.
+func (f *File) Exists() (bool, error) {
+	return f.file.Exists()
+}
+
+// Size returns the size of the wrapped file.
+func (f *File) Size() (uint64, error) {
+	return f.file.Size()
+}
+
+// Location returns a read-only Location for the directory containing the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		location:   f.file.Location(),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the file to the target file, which fails if the target is also read-only.
+func (f *File) CopyToFile(target vfs.File) error {
+	return f.file.CopyToFile(target)
+}
+
+// CopyToLocation copies the file to a file of the same name at location, which fails if the location is also
+// read-only.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	return f.file.CopyToLocation(location)
+}
+
+// MoveToFile always returns a PermissionError, since moving deletes the file.
+func (f *File) MoveToFile(target vfs.File) error {
+	return f.permissionError("move")
+}
+
+// MoveToLocation always returns a PermissionError, since moving deletes the file.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	return nil, f.permissionError("move")
+}
+
+// CRUD Operations
+
+// Delete always returns a PermissionError.
+func (f *File) Delete() error {
+	return f.permissionError("delete")
+}
+
+// Close closes the wrapped file.
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// Read implements the io.Reader interface, reading from the wrapped file.
+func (f *File) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// Seek implements the io.Seeker interface, seeking within the wrapped file.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Write always returns a PermissionError.
+func (f *File) Write(data []byte) (int, error) {
+	return 0, f.permissionError("write")
+}
+
+func (f *File) permissionError(op string) error {
+	return &PermissionError{Op: op, URI: f.URI()}
+}
@@ -0,0 +1,108 @@
+package readonly
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// SchemePrefix is prepended to the scheme of the wrapped FileSystem, ie: readonly+s3. A distinct scheme stops backends
+// from using their native copy to write to a read-only location.
+const SchemePrefix = "readonly+"
+
+// PermissionError is returned by any operation that would modify a read-only FileSystem. Use IsPermission to check for
+// one.
+type PermissionError struct {
+	// Op is the operation that was refused, ie: "write"
+	Op string
+	// URI is the URI of the file or location the operation was attempted on.
+	URI string
+}
+
+// Error implements error
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s %s: read-only filesystem: %s", e.Op, e.URI, os.ErrPermission)
+}
+
+// Unwrap returns os.ErrPermission
+func (e *PermissionError) Unwrap() error {
+	return os.ErrPermission
+}
+
+// IsPermission returns whether err is a *PermissionError, returned by an operation that would modify a read-only
+// FileSystem, or is otherwise a permission error according to os.IsPermission.
+func IsPermission(err error) bool {
+	if _, ok := err.(*PermissionError); ok {
+		return true
+	}
+	return os.IsPermission(err)
+}
+
+// FileSystem implements vfs.Filesystem as a read-only view of another FileSystem. The wrapped FileSystem is never
+// exposed, so code given a read-only FileSystem, Location or File can't use it to make changes.
+type FileSystem struct {
+	fileSystem vfs.FileSystem
+}
+
+// NewFileSystem initializer returns a read-only FileSystem for fileSystem.
+func NewFileSystem(fileSystem vfs.FileSystem) *FileSystem {
+	return &FileSystem{fileSystem: fileSystem}
+}
+
+// WrapLocation returns a read-only view of location.
+func WrapLocation(location vfs.Location) *Location {
+	return &Location{
+		fileSystem: NewFileSystem(location.FileSystem()),
+		location:   location,
+	}
+}
+
+// WrapFile returns a read-only view of file.
+func WrapFile(file vfs.File) *File {
+	return &File{
+		fileSystem: NewFileSystem(file.Location().FileSystem()),
+		file:       file,
+	}
+}
+
+// NewFile function returns the read-only implementation of vfs.File.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("readonly FileSystem requires a FileSystem")
+	}
+	file, err := fs.fileSystem.NewFile(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: fs, file: file}, nil
+}
+
+// NewLocation function returns the read-only implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("readonly FileSystem requires a FileSystem")
+	}
+	location, err := fs.fileSystem.NewLocation(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, location: location}, nil
+}
+
+// Name returns the name of the wrapped FileSystem prefixed with "read-only", ie: "read-only AWS S3"
+func (fs *FileSystem) Name() string {
+	if fs.fileSystem == nil {
+		return "read-only"
+	}
+	return "read-only " + fs.fileSystem.Name()
+}
+
+// Scheme returns the scheme of the wrapped FileSystem prefixed with "readonly+", ie: readonly+s3
+func (fs *FileSystem) Scheme() string {
+	if fs.fileSystem == nil {
+		return SchemePrefix
+	}
+	return SchemePrefix + fs.fileSystem.Scheme()
+}
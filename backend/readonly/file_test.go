@@ -0,0 +1,108 @@
+package readonly
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir string
+	fs     *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "readonly-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.NoError(ioutil.WriteFile(filepath.Join(dir, "data.txt"), []byte("read me"), 0644))
+	s.fs = NewFileSystem(&_os.FileSystem{})
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) assertPermissionError(err error, op string) {
+	s.Error(err, op)
+	s.True(IsPermission(err), "%s error is a permission error", op)
+	permissionError, ok := err.(*PermissionError)
+	if s.True(ok, "%s error is a PermissionError", op) {
+		s.Equal(op, permissionError.Op)
+	}
+}
+
+func (s *fileTestSuite) TestRead() {
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "data.txt"))
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("read me", string(contents))
+	s.NoError(file.Close())
+
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(7), size)
+	s.Equal("readonly+file://"+filepath.Join(s.tmpDir, "data.txt"), file.URI())
+	s.Equal("read-only os", file.Location().FileSystem().Name())
+}
+
+func (s *fileTestSuite) TestWrites() {
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "data.txt"))
+	s.NoError(err)
+
+	_, err = file.Write([]byte("overwrite"))
+	s.assertPermissionError(err, "write")
+	s.assertPermissionError(file.Delete(), "delete")
+	s.assertPermissionError(file.Location().DeleteFile("data.txt"), "delete")
+
+	osLocation, err := (&_os.FileSystem{}).NewLocation("", filepath.Join(s.tmpDir, "out"))
+	s.NoError(err)
+	_, err = file.MoveToLocation(osLocation)
+	s.assertPermissionError(err, "move")
+	s.assertPermissionError(file.MoveToFile(file), "move")
+
+	contents, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "data.txt"))
+	s.NoError(err)
+	s.Equal("read me", string(contents), "file unchanged")
+}
+
+func (s *fileTestSuite) TestCopy() {
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "data.txt"))
+	s.NoError(err)
+
+	osLocation, err := (&_os.FileSystem{}).NewLocation("", filepath.Join(s.tmpDir, "out"))
+	s.NoError(err)
+	copied, err := file.CopyToLocation(osLocation)
+	s.NoError(err, "copying from a read-only file is allowed")
+	contents, err := ioutil.ReadFile(copied.Path())
+	s.NoError(err)
+	s.Equal("read me", string(contents))
+
+	// copying into a read-only location fails, including from the same backend
+	target := WrapLocation(osLocation)
+	_, err = copied.CopyToLocation(target)
+	s.assertPermissionError(err, "write")
+	s.NoError(copied.Close())
+	readonlyFile, err := target.NewFile("new.txt")
+	s.NoError(err)
+	s.assertPermissionError(copied.CopyToFile(readonlyFile), "write")
+
+	exists, err := WrapFile(copied).Exists()
+	s.NoError(err)
+	s.True(exists)
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
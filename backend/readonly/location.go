@@ -0,0 +1,97 @@
+package readonly
+
+import (
+	"regexp"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface as a read-only view of a location on the wrapped FileSystem.
+type Location struct {
+	fileSystem *FileSystem
+	location   vfs.Location
+}
+
+// List returns the names of the files at the location.
+func (l *Location) List() ([]string, error) {
+	return l.location.List()
+}
+
+// ListByPrefix returns the names of the files at the location starting with prefix.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	return l.location.ListByPrefix(prefix)
+}
+
+// ListByRegex returns the names of the files at the location matching regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.location.ListByRegex(regex)
+}
+
+// ListDirs returns the names of the directories directly inside the wrapped location, which must implement
+// utils.DirLister.
+func (l *Location) ListDirs() ([]string, error) {
+	return utils.ListDirs(l.location)
+}
+
+// Volume returns the volume of the wrapped location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path returns the path of the wrapped location.
+func (l *Location) Path() string {
+	return l.location.Path()
+}
+
+// Exists returns whether the wrapped location exists.
+func (l *Location) Exists() (bool, error) {
+	return l.location.Exists()
+}
+
+// NewLocation returns a new read-only Location relative to this one.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	location, err := l.location.NewLocation(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: l.fileSystem, location: location}, nil
+}
+
+// ChangeDir changes the directory of the wrapped location.
+func (l *Location) ChangeDir(relativePath string) error {
+	return l.location.ChangeDir(relativePath)
+}
+
+// FileSystem returns the read-only FileSystem the location was created by.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a read-only File for fileName, relative to the location.
+func (l *Location) NewFile(fileName string) (vfs.File, error) {
+	file, err := l.location.NewFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: l.fileSystem, file: file}, nil
+}
+
+// DeleteFile always returns a PermissionError.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
@@ -0,0 +1,220 @@
+package s3
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+const (
+	// webIdentityTokenFileEnv and roleARNEnv are set by EKS for IAM roles for service accounts (IRSA).
+	webIdentityTokenFileEnv = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	roleARNEnv              = "AWS_ROLE_ARN"
+	roleSessionNameEnv      = "AWS_ROLE_SESSION_NAME"
+
+	// defaultSTSRegion is used for STS requests when no region is configured.
+	defaultSTSRegion = "us-east-1"
+)
+
+// newSTSClient returns the STS client used to assume roles. It's a variable so tests can replace it.
+var newSTSClient = func(config *aws.Config) (stsiface.STSAPI, error) {
+	s, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return sts.New(s), nil
+}
+
+// credentialCacheKey holds every option that affects which credentials are returned.
+type credentialCacheKey struct {
+	accessKeyID          string
+	secretAccessKey      string
+	sessionToken         string
+	profile              string
+	region               string
+	roleARN              string
+	externalID           string
+	roleSessionName      string
+	assumeRoleDuration   time.Duration
+	webIdentityTokenFile string
+}
+
+// credentialCache holds assumed role credentials so that FileSystems with the same options share them, and
+// refresh them, rather than calling STS for each new client.
+var credentialCache = struct {
+	sync.Mutex
+	creds map[credentialCacheKey]*credentials.Credentials
+}{creds: make(map[credentialCacheKey]*credentials.Credentials)}
+
+// getCredentials returns the credentials for opt: the provider chain, the role assumed with the chain's credentials,
+// or the role assumed with a web identity token.
+func getCredentials(opt Options) (*credentials.Credentials, error) {
+	tokenFile := opt.WebIdentityTokenFile
+	// the env token file is set in every EKS pod using IAM roles for service accounts, so it mustn't override
+	// credentials given explicitly
+	if tokenFile == "" && !hasExplicitCredentials(opt) {
+		tokenFile = os.Getenv(webIdentityTokenFileEnv)
+	}
+	roleARN := opt.RoleARN
+	if roleARN == "" && tokenFile != "" {
+		roleARN = os.Getenv(roleARNEnv)
+	}
+
+	if roleARN == "" {
+		if opt.WebIdentityTokenFile != "" {
+			return nil, fmt.Errorf("a role ARN is required to use the web identity token file %s", opt.WebIdentityTokenFile)
+		}
+		return getProviderChainCredentials(opt)
+	}
+
+	key := credentialCacheKey{
+		accessKeyID:          opt.AccessKeyID,
+		secretAccessKey:      opt.SecretAccessKey,
+		sessionToken:         opt.SessionToken,
+		profile:              opt.Profile,
+		region:               stsRegion(opt),
+		roleARN:              roleARN,
+		externalID:           opt.ExternalID,
+		roleSessionName:      opt.RoleSessionName,
+		assumeRoleDuration:   opt.AssumeRoleDuration,
+		webIdentityTokenFile: tokenFile,
+	}
+
+	credentialCache.Lock()
+	defer credentialCache.Unlock()
+	if creds, ok := credentialCache.creds[key]; ok {
+		return creds, nil
+	}
+
+	var creds *credentials.Credentials
+	if tokenFile != "" {
+		// AssumeRoleWithWebIdentity is an unsigned request, the token file is the only credential
+		client, err := newSTSClient(stsConfig(opt).WithCredentials(credentials.AnonymousCredentials))
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewCredentials(&webIdentityProvider{
+			client:          client,
+			roleARN:         roleARN,
+			roleSessionName: roleSessionName(opt),
+			tokenFile:       tokenFile,
+			duration:        opt.AssumeRoleDuration,
+		})
+	} else {
+		baseCreds, err := getProviderChainCredentials(opt)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newSTSClient(stsConfig(opt).WithCredentials(baseCreds))
+		if err != nil {
+			return nil, err
+		}
+		creds = stscreds.NewCredentialsWithClient(client, roleARN, func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = roleSessionName(opt)
+			if opt.ExternalID != "" {
+				p.ExternalID = aws.String(opt.ExternalID)
+			}
+			if opt.AssumeRoleDuration > 0 {
+				p.Duration = opt.AssumeRoleDuration
+			}
+		})
+	}
+
+	credentialCache.creds[key] = creds
+	return creds, nil
+}
+
+// hasExplicitCredentials returns whether opt has static credentials or a profile, rather than leaving the credentials
+// to the environment.
+func hasExplicitCredentials(opt Options) bool {
+	return opt.AccessKeyID != "" || opt.SecretAccessKey != "" || opt.SessionToken != "" || opt.Profile != ""
+}
+
+// getProviderChainCredentials returns credentials from the first provider in the chain that has them.
+func getProviderChainCredentials(opt Options) (*credentials.Credentials, error) {
+	credentialProviders, err := initCredentialProviderChain(opt)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewChainCredentials(credentialProviders), nil
+}
+
+// stsConfig returns the config for STS requests. The s3 Endpoint is never used for STS.
+func stsConfig(opt Options) *aws.Config {
+	config := aws.NewConfig().WithRegion(stsRegion(opt))
+	if opt.HTTPClient != nil {
+		config.WithHTTPClient(opt.HTTPClient)
+	}
+	return config
+}
+
+func stsRegion(opt Options) string {
+	if region := getRegion(opt); region != "" {
+		return region
+	}
+	return defaultSTSRegion
+}
+
+func roleSessionName(opt Options) string {
+	if opt.RoleSessionName != "" {
+		return opt.RoleSessionName
+	}
+	if name := os.Getenv(roleSessionNameEnv); name != "" {
+		return name
+	}
+	return fmt.Sprintf("vfs-%d", time.Now().UTC().UnixNano())
+}
+
+// webIdentityProvider retrieves credentials by assuming a role with the OIDC token in tokenFile, which is re-read on
+// each refresh since it is rotated by the platform.
+type webIdentityProvider struct {
+	credentials.Expiry
+
+	client          stsiface.STSAPI
+	roleARN         string
+	roleSessionName string
+	tokenFile       string
+	duration        time.Duration
+}
+
+// Retrieve implements credentials.Provider
+func (p *webIdentityProvider) Retrieve() (credentials.Value, error) {
+	value := credentials.Value{ProviderName: "WebIdentityProvider"}
+
+	token, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return value, fmt.Errorf("unable to read web identity token file: %s", err)
+	}
+
+	input := &sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(p.roleARN),
+		RoleSessionName:  aws.String(p.roleSessionName),
+		WebIdentityToken: aws.String(strings.TrimSpace(string(token))),
+	}
+	if p.duration > 0 {
+		input.DurationSeconds = aws.Int64(int64(p.duration / time.Second))
+	}
+
+	output, err := p.client.AssumeRoleWithWebIdentity(input)
+	if err != nil {
+		return value, err
+	}
+
+	// refresh a little before the credentials actually expire
+	p.SetExpiration(*output.Credentials.Expiration, 10*time.Second)
+
+	value.AccessKeyID = *output.Credentials.AccessKeyId
+	value.SecretAccessKey = *output.Credentials.SecretAccessKey
+	value.SessionToken = *output.Credentials.SessionToken
+	return value, nil
+}
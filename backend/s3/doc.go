@@ -52,6 +52,29 @@ would have to be cast as s3.Filesystem to use the following:
       fs = fs.WithClient(s3apiMock)
   }
 
+S3-Compatible Services
+
+To use an S3-compatible service such as MinIO or LocalStack, point Endpoint at it and force path-style addressing.
+Such services often run without TLS and without server-side encryption support:
+
+  fs = fs.WithOptions(
+      s3.Options{
+          AccessKeyID:                 "minioadmin",
+          SecretAccessKey:             "minioadmin",
+          Region:                      "us-east-1",
+          Endpoint:                    "localhost:9000",
+          S3ForcePathStyle:            true,
+          DisableSSL:                  true,
+          DisableServerSideEncryption: true,
+          HTTPClientTimeout:           30 * time.Second,
+          MaxRetries:                  2,
+      },
+  )
+
+An integration test suite that runs against such an endpoint is behind the vfsintegration build tag:
+
+  VFS_S3_INTEGRATION_ENDPOINT=localhost:9000 go test -tags vfsintegration ./backend/s3/
+
 Authentication
 
 Authentication, by default, occurs automatically when Client() is called. It looks for credentials in the following places,
@@ -74,6 +97,24 @@ preferring the first location found:
   4. RemoteCredProvider - default remote endpoints such as EC2 or ECS IAM Roles
   5. EC2RoleProvider - credentials from the EC2 service, and keeps track if those credentials are expired
 
+Options.Profile selects a profile from the shared credentials file. The EnvProvider is skipped when it's set, so
+that the profile is used even when AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are set.
+
+When Options.RoleARN is set, the credentials found above are used to assume the role with STS, passing ExternalID,
+RoleSessionName and AssumeRoleDuration when set. When Options.WebIdentityTokenFile (or the AWS_WEB_IDENTITY_TOKEN_FILE
+env variable, as set by EKS for IAM roles for service accounts) is set, its token is exchanged for the role instead,
+with RoleARN defaulting to the AWS_ROLE_ARN env variable. The env variables are ignored when static credentials or a
+Profile are set in Options, so that explicit credentials are always used. Assumed role credentials are cached and refreshed before they
+expire, and are shared by every FileSystem with the same credential options, so one process can access buckets in
+several accounts:
+
+  prod := s3.NewFileSystem().WithOptions(s3.Options{RoleARN: "arn:aws:iam::111111111111:role/reader"})
+  audit := s3.NewFileSystem().WithOptions(s3.Options{
+      Profile:    "audit",
+      RoleARN:    "arn:aws:iam::222222222222:role/auditor",
+      ExternalID: "example-external-id",
+  })
+
 See the following for more auth info: https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-envvars.html
 and https://docs.aws.amazon.com/sdk-for-go/v1/developer-guide/configuring-sdk.html
 
@@ -8,8 +8,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -26,6 +28,9 @@ type File struct {
 	key         string
 	tempFile    *os.File
 	writeBuffer *bytes.Buffer
+
+	// streamed is set once ReadFrom has uploaded the file, until it's closed
+	streamed bool
 }
 
 // newFile initializer returns a pointer to File.
@@ -91,6 +96,29 @@ func (f *File) Size() (uint64, error) {
 	return uint64(*head.ContentLength), nil
 }
 
+// ETag returns the object's entity tag, which changes whenever its content does.
+func (f *File) ETag() (string, error) {
+	head, err := f.getHeadObject()
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(head.ETag), nil
+}
+
+// MD5 returns the hex encoded MD5 of the object's content, taken from its ETag. The ETag of an object uploaded in
+// parts isn't an MD5, so "" is returned for those.
+func (f *File) MD5() (string, error) {
+	etag, err := f.ETag()
+	if err != nil {
+		return "", err
+	}
+	etag = strings.Trim(etag, `"`)
+	if strings.Contains(etag, "-") {
+		return "", nil
+	}
+	return etag, nil
+}
+
 // Location returns a vfs.Location at the location of the object. IE: if file is at
 // s3://bucket/here/is/the/file.txt the location points to s3://bucket/here/is/the/
 func (f *File) Location() vfs.Location {
@@ -199,15 +227,8 @@ func (f *File) Delete() error {
 // local temp file, and triggers a write to s3 of anything in the f.writeBuffer if it has been created.
 func (f *File) Close() error {
 
-	if f.tempFile != nil {
-		defer f.tempFile.Close()
-
-		err := os.Remove(f.tempFile.Name())
-		if err != nil && !os.IsNotExist(err) {
-			return err
-		}
-
-		f.tempFile = nil
+	if err := f.removeTempFile(); err != nil {
+		return err
 	}
 
 	if f.writeBuffer != nil {
@@ -227,6 +248,7 @@ func (f *File) Close() error {
 	}
 
 	f.writeBuffer = nil
+	f.streamed = false
 
 	return waitUntilFileExists(f, 5)
 }
@@ -254,6 +276,9 @@ func (f *File) Seek(offset int64, whence int) (int64, error) {
 // PutObject to s3. The underlying implementation uses s3manager which will determine whether
 // it is appropriate to call PutObject, or initiate a multi-part upload.
 func (f *File) Write(data []byte) (res int, err error) {
+	if f.streamed {
+		return 0, f.streamedError()
+	}
 	if f.writeBuffer == nil {
 		//note, initializing with 'data' and returning len(data), nil
 		//causes issues with some Write usages, notably csv.Writer
@@ -269,6 +294,74 @@ func (f *File) Write(data []byte) (res int, err error) {
 	return f.writeBuffer.Write(data)
 }
 
+// ReadFrom implements the io.ReaderFrom interface, so that io.Copy to a file that hasn't been written to yet streams r
+// straight to s3, uploading it in parts as it's read rather than buffering all of it until Close. A file that's
+// already been written to appends r to its buffer as Write does. Once r has been streamed the object is complete, so
+// further writes fail until the file is closed, rather than replacing it.
+func (f *File) ReadFrom(r io.Reader) (int64, error) {
+	if f.streamed {
+		return 0, f.streamedError()
+	}
+	if f.writeBuffer != nil {
+		return f.writeBuffer.ReadFrom(r)
+	}
+	// content read before is replaced
+	if err := f.removeTempFile(); err != nil {
+		return 0, err
+	}
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return 0, err
+	}
+	uploader := s3manager.NewUploaderWithClient(client)
+	counter := &countingReader{reader: r}
+	input := uploadInput(f)
+	input.Body = counter
+	if _, err := uploader.Upload(input); err != nil {
+		return counter.count, err
+	}
+	f.streamed = true
+	return counter.count, waitUntilFileExists(f, 5)
+}
+
+// WriteTo implements the io.WriterTo interface, so that io.Copy from a file that hasn't been read yet streams the
+// object straight to w without first downloading it to a temporary local file.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	if f.tempFile != nil {
+		return io.Copy(w, f.tempFile)
+	}
+	reader, err := f.getObject()
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return io.Copy(w, reader)
+}
+
+// ReadRange returns a reader of length bytes of the object's content starting at offset, or of the rest of the content
+// when length is negative. Only the range is fetched, with a ranged GET, rather than downloading the whole object as
+// Seek does.
+func (f *File) ReadRange(offset, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+	input := f.getObjectInput()
+	if length < 0 {
+		input.SetRange(fmt.Sprintf("bytes=%d-", offset))
+	} else {
+		input.SetRange(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	}
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+	getOutput, err := client.GetObject(input)
+	if err != nil {
+		return nil, err
+	}
+	return getOutput.Body, nil
+}
+
 // URI returns the File's URI as a string.
 func (f *File) URI() string {
 	return utils.GetFileURI(f)
@@ -317,6 +410,27 @@ func (f *File) copyWithinS3ToLocation(location vfs.Location) (vfs.File, error) {
 	return location.FileSystem().NewFile(location.Volume(), path.Join(location.Path(), f.Name()))
 }
 
+// removeTempFile closes and removes the local temp file used for reads, if there is one.
+func (f *File) removeTempFile() error {
+	if f.tempFile == nil {
+		return nil
+	}
+	defer f.tempFile.Close()
+
+	err := os.Remove(f.tempFile.Name())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f.tempFile = nil
+	return nil
+}
+
+// streamedError returns the error for a write to a file whose content was already uploaded by ReadFrom.
+func (f *File) streamedError() error {
+	return fmt.Errorf("%s was streamed by io.Copy and must be closed before it's written again", f)
+}
+
 func (f *File) checkTempFile() error {
 	if f.tempFile == nil {
 		localTempFile, err := f.copyToLocalTempReader()
@@ -370,14 +484,18 @@ func (f *File) getObject() (io.ReadCloser, error) {
 	return getOutput.Body, nil
 }
 
-//TODO: need to provide an implementation-agnostic container for providing config options such as SSE
+// uploadInput returns the s3manager.UploadInput for the file, requesting AES256 server-side encryption unless
+// Options.DisableServerSideEncryption is set.
 func uploadInput(f *File) *s3manager.UploadInput {
-	sseType := "AES256"
-	return &s3manager.UploadInput{
-		Bucket:               &f.bucket,
-		Key:                  &f.key,
-		ServerSideEncryption: &sseType,
+	input := &s3manager.UploadInput{
+		Bucket: &f.bucket,
+		Key:    &f.key,
+	}
+	if opts, ok := f.fileSystem.options.(Options); !ok || !opts.DisableServerSideEncryption {
+		sseType := "AES256"
+		input.ServerSideEncryption = &sseType
 	}
+	return input
 }
 
 //WaitUntilFileExists attempts to ensure that a recently written file is available before moving on.  This is helpful for
@@ -417,3 +535,15 @@ func waitUntilFileExists(file vfs.File, retries int) error {
 
 	return nil
 }
+
+// countingReader counts the bytes read from reader.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -80,6 +85,87 @@ func (ts *fileTestSuite) TestWrite() {
 	ts.Nil(err, "Error should be nil when calling Write")
 }
 
+func (ts *fileTestSuite) TestReadFromAfterWrite() {
+	file, err := fs.NewFile("bucket", "hello.txt")
+	ts.NoError(err)
+
+	_, err = file.Write([]byte("Hello "))
+	ts.NoError(err)
+	count, err := file.(*File).ReadFrom(bytes.NewBufferString("world!"))
+	ts.NoError(err)
+	ts.Equal(int64(6), count)
+	ts.Equal("Hello world!", file.(*File).writeBuffer.String(), "ReadFrom appends to content already written")
+	s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *fileTestSuite) TestReadFromStreamed() {
+	var uploaded []string
+	s3apiMock.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).
+		Return(func(input *s3.PutObjectInput) *request.Request {
+			content, _ := ioutil.ReadAll(input.Body)
+			uploaded = append(uploaded, string(content))
+			return request.New(aws.Config{}, metadata.ClientInfo{}, request.Handlers{}, nil,
+				&request.Operation{Name: "PutObject"}, input, &s3.PutObjectOutput{})
+		}, func(*s3.PutObjectInput) *s3.PutObjectOutput {
+			return &s3.PutObjectOutput{}
+		})
+	s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(func(*s3.GetObjectInput) *s3.GetObjectOutput {
+			return &s3.GetObjectOutput{Body: nopCloser{strings.NewReader(uploaded[len(uploaded)-1])}}
+		}, nil)
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	file, err := fs.NewFile("bucket", "hello.txt")
+	ts.NoError(err)
+	// readers without WriteTo, so that io.Copy calls ReadFrom
+	_, err = io.Copy(file, nopCloser{strings.NewReader("first")})
+	ts.NoError(err)
+	_, err = io.Copy(file, nopCloser{strings.NewReader("second")})
+	ts.Error(err, "a second io.Copy doesn't replace the first")
+	_, err = file.Write([]byte("third"))
+	ts.Error(err, "a Write doesn't replace what was streamed")
+	ts.NoError(file.Close())
+	ts.Equal([]string{"first"}, uploaded)
+
+	content, err := ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("first", string(content))
+	_, err = io.Copy(file, nopCloser{strings.NewReader("fourth")})
+	ts.NoError(err, "a closed file can be streamed to again")
+	content, err = ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("fourth", string(content), "content read before streaming isn't served")
+	ts.NoError(file.Close())
+	ts.Equal([]string{"first", "fourth"}, uploaded)
+}
+
+func (ts *fileTestSuite) TestReadRange() {
+	file, err := fs.NewFile("bucket", "hello.txt")
+	ts.NoError(err)
+
+	var ranges []string
+	s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).Run(func(args mock.Arguments) {
+		ranges = append(ranges, *args.Get(0).(*s3.GetObjectInput).Range)
+	}).Return(&s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString("world")}}, nil)
+
+	reader, err := file.(*File).ReadRange(6, 5)
+	ts.NoError(err)
+	content, err := ioutil.ReadAll(reader)
+	ts.NoError(err)
+	ts.Equal("world", string(content))
+
+	_, err = file.(*File).ReadRange(6, -1)
+	ts.NoError(err)
+	ts.Equal([]string{"bytes=6-10", "bytes=6-"}, ranges)
+
+	reader, err = file.(*File).ReadRange(6, 0)
+	ts.NoError(err)
+	content, err = ioutil.ReadAll(reader)
+	ts.NoError(err)
+	ts.Empty(content, "an empty range doesn't request anything")
+	s3apiMock.AssertNumberOfCalls(ts.T(), "GetObject", 2)
+}
+
 func (ts *fileTestSuite) TestSeek() {
 	contents := "hello world!"
 	file, err := fs.NewFile("bucket", "hello.txt")
@@ -185,6 +271,31 @@ func (ts *fileTestSuite) TestEmptyCopyToFile() {
 	targetFile.AssertExpectations(ts.T())
 }
 
+func (ts *fileTestSuite) TestETag() {
+	etag := `"d41d8cd98f00b204e9800998ecf8427e"`
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{ETag: &etag}, nil)
+
+	actual, err := testFile.(*File).ETag()
+	ts.Nil(err, "Error should be nil when requesting the ETag of an existing file")
+	ts.Equal(etag, actual, "ETag is returned from the head request")
+	s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *fileTestSuite) TestMD5() {
+	etag := `"d41d8cd98f00b204e9800998ecf8427e"`
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{ETag: &etag}, nil).Once()
+	actual, err := testFile.(*File).MD5()
+	ts.Nil(err, "Error should be nil when requesting the MD5 of an existing file")
+	ts.Equal("d41d8cd98f00b204e9800998ecf8427e", actual, "MD5 is the unquoted ETag")
+
+	multipart := `"9b2cf535f27731c974343645a3985328-2"`
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{ETag: &multipart}, nil).Once()
+	actual, err = testFile.(*File).MD5()
+	ts.Nil(err, "Error should be nil when requesting the MD5 of a multipart upload")
+	ts.Equal("", actual, "ETag of a multipart upload isn't an MD5")
+	s3apiMock.AssertExpectations(ts.T())
+}
+
 func (ts *fileTestSuite) TestMoveToFile() {
 	targetFile := &File{
 		fileSystem: &FileSystem{
@@ -423,6 +534,10 @@ func (ts *fileTestSuite) TestUploadInput() {
 	ts.Equal("AES256", *uploadInput(file.(*File)).ServerSideEncryption, "sse was set")
 	ts.Equal("some/file/test.txt", *uploadInput(file.(*File)).Key, "key was set")
 	ts.Equal("mybucket", *uploadInput(file.(*File)).Bucket, "bucket was set")
+
+	fs = FileSystem{client: &mocks.S3API{}, options: Options{DisableServerSideEncryption: true}}
+	file, _ = fs.NewFile("mybucket", "/some/file/test.txt")
+	ts.Nil(uploadInput(file.(*File)).ServerSideEncryption, "sse was not set")
 }
 
 func (ts *fileTestSuite) TestNewFile() {
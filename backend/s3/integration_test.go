@@ -0,0 +1,186 @@
+//go:build vfsintegration
+// +build vfsintegration
+
+package s3
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/mocks"
+)
+
+// integrationTestSuite runs against a real S3-compatible endpoint, such as a local MinIO:
+//
+//   docker run -p 9000:9000 -e MINIO_ACCESS_KEY=minioadmin -e MINIO_SECRET_KEY=minioadmin minio/minio server /data
+//   VFS_S3_INTEGRATION_ENDPOINT=localhost:9000 go test -tags vfsintegration ./backend/s3/
+//
+// Credentials default to minioadmin/minioadmin and can be set with VFS_S3_INTEGRATION_ACCESS_KEY and
+// VFS_S3_INTEGRATION_SECRET_KEY. A new bucket is created for each run and removed afterwards.
+type integrationTestSuite struct {
+	suite.Suite
+	fs     *FileSystem
+	bucket string
+}
+
+func (s *integrationTestSuite) SetupSuite() {
+	endpoint := os.Getenv("VFS_S3_INTEGRATION_ENDPOINT")
+	if endpoint == "" {
+		s.T().Skip("VFS_S3_INTEGRATION_ENDPOINT is not set")
+	}
+
+	s.fs = NewFileSystem().WithOptions(Options{
+		AccessKeyID:                 getenv("VFS_S3_INTEGRATION_ACCESS_KEY", "minioadmin"),
+		SecretAccessKey:             getenv("VFS_S3_INTEGRATION_SECRET_KEY", "minioadmin"),
+		Region:                      getenv("VFS_S3_INTEGRATION_REGION", "us-east-1"),
+		Endpoint:                    endpoint,
+		S3ForcePathStyle:            true,
+		DisableSSL:                  true,
+		HTTPClientTimeout:           30 * time.Second,
+		MaxRetries:                  2,
+		DisableServerSideEncryption: true,
+	})
+
+	s.bucket = fmt.Sprintf("vfs-integration-%d", time.Now().UnixNano())
+	client, err := s.fs.Client()
+	s.Require().NoError(err)
+	_, err = client.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(s.bucket)})
+	s.Require().NoError(err, "creating test bucket")
+}
+
+func (s *integrationTestSuite) TearDownSuite() {
+	client, err := s.fs.Client()
+	s.Require().NoError(err)
+	output, err := client.ListObjects(&s3.ListObjectsInput{Bucket: aws.String(s.bucket)})
+	s.Require().NoError(err)
+	for _, object := range output.Contents {
+		_, err := client.DeleteObject(&s3.DeleteObjectInput{Bucket: aws.String(s.bucket), Key: object.Key})
+		s.NoError(err)
+	}
+	_, err = client.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(s.bucket)})
+	s.NoError(err)
+}
+
+func (s *integrationTestSuite) newFile(name string) vfs.File {
+	file, err := s.fs.NewFile(s.bucket, name)
+	s.Require().NoError(err)
+	return file
+}
+
+func (s *integrationTestSuite) TestWriteReadSeek() {
+	file := s.newFile("/rw/hello.txt")
+	_, err := file.Write([]byte("hello world"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(11), size)
+
+	_, err = file.Seek(6, io.SeekStart)
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("world", string(contents))
+	s.NoError(file.Close())
+}
+
+func (s *integrationTestSuite) TestStreaming() {
+	file := s.newFile("/stream/data.txt")
+	n, err := io.Copy(file, strings.NewReader("streamed content"))
+	s.NoError(err)
+	s.Equal(int64(16), n)
+	s.NoError(file.Close())
+
+	var out bytes.Buffer
+	_, err = io.Copy(&out, s.newFile("/stream/data.txt"))
+	s.NoError(err)
+	s.Equal("streamed content", out.String())
+}
+
+func (s *integrationTestSuite) TestList() {
+	for _, name := range []string{"/list/a.csv", "/list/b.csv", "/list/sub/c.csv"} {
+		file := s.newFile(name)
+		_, err := file.Write([]byte(name))
+		s.NoError(err)
+		s.NoError(file.Close())
+	}
+
+	location, err := s.fs.NewLocation(s.bucket, "/list/")
+	s.NoError(err)
+	exists, err := location.Exists()
+	s.NoError(err)
+	s.True(exists, "bucket exists")
+
+	files, err := location.List()
+	s.NoError(err)
+	s.Equal([]string{"a.csv", "b.csv"}, files)
+
+	files, err = location.ListByPrefix("b")
+	s.NoError(err)
+	s.Equal([]string{"b.csv"}, files)
+}
+
+func (s *integrationTestSuite) TestCopyAndMove() {
+	src := s.newFile("/copy/src.txt")
+	_, err := src.Write([]byte("copy me"))
+	s.NoError(err)
+	s.NoError(src.Close())
+
+	copied := s.newFile("/copy/dst.txt")
+	s.NoError(src.CopyToFile(copied), "s3 to s3 copy")
+	contents, err := ioutil.ReadAll(copied)
+	s.NoError(err)
+	s.Equal("copy me", string(contents))
+	s.NoError(copied.Close())
+
+	location, err := s.fs.NewLocation(s.bucket, "/moved/")
+	s.NoError(err)
+	moved, err := src.MoveToLocation(location)
+	s.NoError(err)
+	s.Equal("/moved/src.txt", moved.Path())
+	exists, err := src.Exists()
+	s.NoError(err)
+	s.False(exists, "source removed by move")
+
+	local := mocks.NewStringFile("", "local.txt")
+	s.NoError(moved.CopyToFile(local), "s3 to other backend copy")
+	s.Equal("copy me", local.Content())
+}
+
+func (s *integrationTestSuite) TestDelete() {
+	file := s.newFile("/delete/me.txt")
+	_, err := file.Write([]byte("x"))
+	s.NoError(err)
+	s.NoError(file.Close())
+	s.NoError(file.Delete())
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func getenv(key, fallback string) string {
+	if val, ok := os.LookupEnv(key); ok {
+		return val
+	}
+	return fallback
+}
+
+func TestIntegration(t *testing.T) {
+	suite.Run(t, new(integrationTestSuite))
+}
@@ -38,6 +38,37 @@ func (l *Location) ListByPrefix(prefix string) ([]string, error) {
 	return l.fullLocationList(listObjectsInput)
 }
 
+// ListDirs calls the s3 API to list the common prefixes at the location's path, returning the names of the
+// "directories" directly inside the location. The resource considerations of List() apply here as well.
+func (l *Location) ListDirs() ([]string, error) {
+	var dirs []string
+	client, err := l.fileSystem.Client()
+	if err != nil {
+		return dirs, err
+	}
+	locationPrefix := utils.EnsureTrailingSlash(l.prefix)
+	input := l.getListObjectsInput().SetPrefix(locationPrefix)
+	for {
+		listObjectsOutput, err := client.ListObjects(input)
+		if err != nil {
+			return []string{}, err
+		}
+		for _, commonPrefix := range listObjectsOutput.CommonPrefixes {
+			dir := strings.TrimSuffix(strings.TrimPrefix(*commonPrefix.Prefix, locationPrefix), "/")
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+
+		if *listObjectsOutput.IsTruncated {
+			input.SetMarker(*listObjectsOutput.NextMarker)
+		} else {
+			break
+		}
+	}
+	return dirs, nil
+}
+
 // ListByRegex retrieves the keys of all the files at the location's current path, then filters out all those
 // that don't match the given regex. The resource considerations of List() apply here as well.
 func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
@@ -52,6 +52,31 @@ func (lt *locationTestSuite) TestList() {
 	lt.s3apiMock.AssertExpectations(lt.T())
 }
 
+func (lt *locationTestSuite) TestListDirs() {
+	bucket := "bucket"
+	locPath := "dir1/"
+	delimiter := "/"
+	isTruncated := false
+	sub1 := "dir1/sub1/"
+	sub2 := "dir1/sub2/"
+	lt.s3apiMock.On("ListObjects", &s3.ListObjectsInput{
+		Bucket:    &bucket,
+		Prefix:    &locPath,
+		Delimiter: &delimiter,
+	}).Return(&s3.ListObjectsOutput{
+		Contents:       convertKeysToS3Objects([]string{"dir1/file.txt"}),
+		CommonPrefixes: []*s3.CommonPrefix{{Prefix: &sub1}, {Prefix: &sub2}},
+		IsTruncated:    &isTruncated,
+		Prefix:         &locPath,
+	}, nil).Once()
+
+	loc := &Location{lt.fs, locPath, bucket}
+	dirs, err := loc.ListDirs()
+	lt.NoError(err, "Shouldn't return an error when successfully listing directories.")
+	lt.Equal([]string{"sub1", "sub2"}, dirs, "Should return the common prefixes without the location's prefix.")
+	lt.s3apiMock.AssertExpectations(lt.T())
+}
+
 func (lt *locationTestSuite) TestList_pagedCall() {
 	firstKeyList := []string{"dir1/file.txt", "dir1/file2.txt"}
 	firstCallOutputMarker := firstKeyList[len(firstKeyList)-1]
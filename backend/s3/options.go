@@ -13,8 +13,13 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	"github.com/c2fo/vfs/v3/utils"
 )
 
+// EnvPrefix is the prefix of the environment variables read by OptionsFromEnv for the default s3 FileSystem.
+const EnvPrefix = "VFS_S3_"
+
 // Options holds s3-specific options.  Currently only client options are used.
 type Options struct {
 	AccessKeyID     string `json:"accessKeyId,omitempty"`
@@ -22,6 +27,78 @@ type Options struct {
 	SessionToken    string `json:"sessionToken,omitempty"`
 	Region          string `json:"region,omitempty"`
 	Endpoint        string `json:"endpoint,omitempty"`
+
+	// S3ForcePathStyle addresses buckets as endpoint/bucket/key rather than bucket.endpoint/key, which is
+	// required by most S3-compatible services such as MinIO and LocalStack.
+	S3ForcePathStyle bool `json:"s3ForcePathStyle,omitempty"`
+
+	// DisableSSL uses http rather than https when the Endpoint doesn't specify a scheme.
+	DisableSSL bool `json:"disableSSL,omitempty"`
+
+	// HTTPClient is used for all requests to s3 when set, taking precedence over HTTPClientTimeout.
+	HTTPClient *http.Client `json:"-"`
+
+	// HTTPClientTimeout sets a timeout on the http client used for requests to s3. Zero means no timeout.
+	HTTPClientTimeout time.Duration `json:"httpClientTimeout,omitempty"`
+
+	// MaxRetries is the maximum number of times a failed request is retried. Zero uses the SDK's default and a
+	// negative value disables retries.
+	MaxRetries int `json:"maxRetries,omitempty"`
+
+	// DisableServerSideEncryption stops uploads from requesting AES256 server-side encryption, for services that
+	// don't support it.
+	DisableServerSideEncryption bool `json:"disableServerSideEncryption,omitempty"`
+
+	// Profile selects a profile from the shared credentials file, otherwise AWS_PROFILE or "default" is used. When it's
+	// set, credentials in the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY env variables are ignored.
+	Profile string `json:"profile,omitempty"`
+
+	// RoleARN is a role to assume with STS, using the credentials found by the provider chain or, when a web identity
+	// token file is set, the token.
+	RoleARN string `json:"roleARN,omitempty"`
+
+	// ExternalID is passed to STS when assuming RoleARN, for roles that require one.
+	ExternalID string `json:"externalID,omitempty"`
+
+	// RoleSessionName identifies the assumed role session. Defaults to AWS_ROLE_SESSION_NAME or a generated name.
+	RoleSessionName string `json:"roleSessionName,omitempty"`
+
+	// AssumeRoleDuration is how long assumed role credentials are valid for. Zero uses the STS default.
+	AssumeRoleDuration time.Duration `json:"assumeRoleDuration,omitempty"`
+
+	// WebIdentityTokenFile is a file containing an OIDC token exchanged for RoleARN credentials, such as the one
+	// mounted by EKS for IAM roles for service accounts. Defaults to AWS_WEB_IDENTITY_TOKEN_FILE, in which case
+	// RoleARN defaults to AWS_ROLE_ARN, unless AccessKeyID, SecretAccessKey, SessionToken or Profile is set.
+	WebIdentityTokenFile string `json:"webIdentityTokenFile,omitempty"`
+}
+
+// OptionsFromEnv returns opts with each option that's set in the environment replaced. Variables are named prefix
+// followed by:
+//
+//   ACCESS_KEY_ID, SECRET_ACCESS_KEY, SESSION_TOKEN, REGION, ENDPOINT, FORCE_PATH_STYLE, DISABLE_SSL,
+//   HTTP_CLIENT_TIMEOUT, MAX_RETRIES, DISABLE_SERVER_SIDE_ENCRYPTION, PROFILE, ROLE_ARN, EXTERNAL_ID,
+//   ROLE_SESSION_NAME, ASSUME_ROLE_DURATION, WEB_IDENTITY_TOKEN_FILE
+//
+// ie: VFS_S3_ENDPOINT with EnvPrefix. Durations are parsed by time.ParseDuration, ie: 30s.
+func OptionsFromEnv(prefix string, opts Options) (Options, error) {
+	env := &utils.EnvReader{Prefix: prefix}
+	env.String("ACCESS_KEY_ID", &opts.AccessKeyID)
+	env.String("SECRET_ACCESS_KEY", &opts.SecretAccessKey)
+	env.String("SESSION_TOKEN", &opts.SessionToken)
+	env.String("REGION", &opts.Region)
+	env.String("ENDPOINT", &opts.Endpoint)
+	env.Bool("FORCE_PATH_STYLE", &opts.S3ForcePathStyle)
+	env.Bool("DISABLE_SSL", &opts.DisableSSL)
+	env.Duration("HTTP_CLIENT_TIMEOUT", &opts.HTTPClientTimeout)
+	env.Int("MAX_RETRIES", &opts.MaxRetries)
+	env.Bool("DISABLE_SERVER_SIDE_ENCRYPTION", &opts.DisableServerSideEncryption)
+	env.String("PROFILE", &opts.Profile)
+	env.String("ROLE_ARN", &opts.RoleARN)
+	env.String("EXTERNAL_ID", &opts.ExternalID)
+	env.String("ROLE_SESSION_NAME", &opts.RoleSessionName)
+	env.Duration("ASSUME_ROLE_DURATION", &opts.AssumeRoleDuration)
+	env.String("WEB_IDENTITY_TOKEN_FILE", &opts.WebIdentityTokenFile)
+	return opts, env.Err()
 }
 
 // getClient setup S3 client
@@ -31,23 +108,37 @@ func getClient(opt Options) (s3iface.S3API, error) {
 	awsConfig := defaults.Config()
 
 	//setup region using opt or env
-	if opt.Region != "" {
-		awsConfig.WithRegion(opt.Region)
-	} else if val, ok := os.LookupEnv("AWS_DEFAULT_REGION"); ok {
-		awsConfig.WithRegion(val)
+	if region := getRegion(opt); region != "" {
+		awsConfig.WithRegion(region)
 	}
 
 	//use specific endpoint, otherwise, will use aws "default endpoint resolver" based on region
 	awsConfig.WithEndpoint(opt.Endpoint)
 
-	//set up credential provider chain
-	credentialProviders, err := initCredentialProviderChain(opt)
+	//settings needed by s3-compatible services
+	awsConfig.WithS3ForcePathStyle(opt.S3ForcePathStyle)
+	awsConfig.WithDisableSSL(opt.DisableSSL)
+
+	//use provided http client, or the default one with a timeout
+	if opt.HTTPClient != nil {
+		awsConfig.WithHTTPClient(opt.HTTPClient)
+	} else if opt.HTTPClientTimeout > 0 {
+		awsConfig.WithHTTPClient(&http.Client{Timeout: opt.HTTPClientTimeout})
+	}
+
+	//retries, leaving the sdk default in place when unset
+	if opt.MaxRetries > 0 {
+		awsConfig.WithMaxRetries(opt.MaxRetries)
+	} else if opt.MaxRetries < 0 {
+		awsConfig.WithMaxRetries(0)
+	}
+
+	//set up credentials, assuming a role when one is configured
+	creds, err := getCredentials(opt)
 	if err != nil {
 		return nil, err
 	}
-	awsConfig.WithCredentials(
-		credentials.NewChainCredentials(credentialProviders),
-	)
+	awsConfig.WithCredentials(creds)
 
 	// create new session with config
 	s, err := session.NewSessionWithOptions(
@@ -88,7 +179,11 @@ func initCredentialProviderChain(opt Options) ([]credentials.Provider, error) {
 	// * Access Key ID:     AWS_ACCESS_KEY_ID or AWS_ACCESS_KEY
 	//
 	// * Secret Access Key: AWS_SECRET_ACCESS_KEY or AWS_SECRET_KEY
-	p = append(p, &credentials.EnvProvider{})
+	//
+	// It's skipped when a profile is named, so that the profile isn't ignored whenever they're set.
+	if opt.Profile == "" {
+		p = append(p, &credentials.EnvProvider{})
+	}
 
 	// Path to the shared credentials file.
 	//
@@ -96,7 +191,9 @@ func initCredentialProviderChain(opt Options) ([]credentials.Provider, error) {
 	// env value is empty will default to current user's home directory.
 	// Linux/OSX: "$HOME/.aws/credentials"
 	// Windows:   "%USERPROFILE%\.aws\credentials"
-	p = append(p, &credentials.SharedCredentialsProvider{})
+	//
+	// Profile defaults to the "AWS_PROFILE" env variable, then "default".
+	p = append(p, &credentials.SharedCredentialsProvider{Profile: opt.Profile})
 
 	lowTimeoutClient := &http.Client{Timeout: 1 * time.Second} // low timeout to ec2 metadata service
 
@@ -119,3 +216,11 @@ func initCredentialProviderChain(opt Options) ([]credentials.Provider, error) {
 
 	return p, nil
 }
+
+// getRegion returns the region from opt, otherwise from the AWS_DEFAULT_REGION env variable.
+func getRegion(opt Options) string {
+	if opt.Region != "" {
+		return opt.Region
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
@@ -1,19 +1,71 @@
 package s3
 
 import (
+	"errors"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/stretchr/testify/suite"
 )
 
+// fakeSTS records assume role requests and returns fixed credentials.
+type fakeSTS struct {
+	stsiface.STSAPI
+	config            *aws.Config
+	assumeRole        []*sts.AssumeRoleInput
+	assumeWebIdentity []*sts.AssumeRoleWithWebIdentityInput
+}
+
+func (f *fakeSTS) credentials() *sts.Credentials {
+	return &sts.Credentials{
+		AccessKeyId:     aws.String("assumed-key"),
+		SecretAccessKey: aws.String("assumed-secret"),
+		SessionToken:    aws.String("assumed-token"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}
+}
+
+func (f *fakeSTS) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	f.assumeRole = append(f.assumeRole, input)
+	return &sts.AssumeRoleOutput{Credentials: f.credentials()}, nil
+}
+
+func (f *fakeSTS) AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	f.assumeWebIdentity = append(f.assumeWebIdentity, input)
+	if *input.WebIdentityToken == "" {
+		return nil, errors.New("empty token")
+	}
+	return &sts.AssumeRoleWithWebIdentityOutput{Credentials: f.credentials()}, nil
+}
+
 type optionsTestSuite struct {
 	suite.Suite
+	sts          *fakeSTS
+	newSTSClient func(*aws.Config) (stsiface.STSAPI, error)
 }
 
 func (o *optionsTestSuite) SetupTest() {
 	os.Clearenv()
+	o.sts = &fakeSTS{}
+	o.newSTSClient = newSTSClient
+	newSTSClient = func(config *aws.Config) (stsiface.STSAPI, error) {
+		o.sts.config = config
+		return o.sts, nil
+	}
+	credentialCache.creds = make(map[credentialCacheKey]*credentials.Credentials)
+}
+
+func (o *optionsTestSuite) TearDownTest() {
+	newSTSClient = o.newSTSClient
 }
 
 func (o *optionsTestSuite) TestGetClient() {
@@ -44,6 +96,246 @@ func (o *optionsTestSuite) TestGetClient() {
 	o.Equal("set-by-envvar", *client.(*s3.S3).Config.Region, "region is set by env var")
 }
 
+func (o *optionsTestSuite) TestGetClient_S3Compatible() {
+	//defaults
+	client, err := getClient(Options{})
+	o.NoError(err)
+	config := client.(*s3.S3).Config
+	o.False(*config.S3ForcePathStyle, "virtual-host style by default")
+	o.False(*config.DisableSSL, "ssl by default")
+	o.Equal(-1, *config.MaxRetries, "sdk default retries")
+
+	//local endpoint
+	client, err = getClient(Options{
+		Endpoint:          "localhost:9000",
+		S3ForcePathStyle:  true,
+		DisableSSL:        true,
+		HTTPClientTimeout: 5 * time.Second,
+		MaxRetries:        2,
+	})
+	o.NoError(err)
+	config = client.(*s3.S3).Config
+	o.True(*config.S3ForcePathStyle, "path style is set")
+	o.True(*config.DisableSSL, "ssl is disabled")
+	o.Equal(5*time.Second, config.HTTPClient.Timeout, "timeout is set")
+	o.Equal(2, *config.MaxRetries, "retries are set")
+	o.Equal("http://localhost:9000", client.(*s3.S3).Endpoint, "endpoint uses http")
+
+	//custom http client and no retries
+	httpClient := &http.Client{}
+	client, err = getClient(Options{HTTPClient: httpClient, HTTPClientTimeout: time.Second, MaxRetries: -1})
+	o.NoError(err)
+	config = client.(*s3.S3).Config
+	o.Equal(httpClient, config.HTTPClient, "http client is used as is")
+	o.Equal(0, *config.MaxRetries, "retries are disabled")
+}
+
+func (o *optionsTestSuite) TestGetClient_AssumeRole() {
+	opts := Options{
+		AccessKeyID:        "mykey",
+		SecretAccessKey:    "mysecret",
+		Endpoint:           "localhost:9000",
+		RoleARN:            "arn:aws:iam::123456789012:role/reader",
+		ExternalID:         "external",
+		RoleSessionName:    "session",
+		AssumeRoleDuration: 30 * time.Minute,
+	}
+	client, err := getClient(opts)
+	o.NoError(err)
+	value, err := client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("assumed-key", value.AccessKeyID, "assumed role credentials are used")
+	o.Equal("assumed-token", value.SessionToken)
+
+	o.Require().Len(o.sts.assumeRole, 1)
+	input := o.sts.assumeRole[0]
+	o.Equal("arn:aws:iam::123456789012:role/reader", *input.RoleArn)
+	o.Equal("external", *input.ExternalId)
+	o.Equal("session", *input.RoleSessionName)
+	o.Equal(int64(1800), *input.DurationSeconds)
+	o.Equal("us-east-1", *o.sts.config.Region, "sts defaults to us-east-1")
+	o.Nil(o.sts.config.Endpoint, "s3 endpoint isn't used for sts")
+	baseValue, err := o.sts.config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("mykey", baseValue.AccessKeyID, "role is assumed with the provider chain's credentials")
+
+	//same options share cached credentials
+	client, err = getClient(opts)
+	o.NoError(err)
+	_, err = client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Len(o.sts.assumeRole, 1, "credentials are cached")
+
+	//a different account is assumed separately
+	opts.RoleARN = "arn:aws:iam::210987654321:role/reader"
+	client, err = getClient(opts)
+	o.NoError(err)
+	_, err = client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Len(o.sts.assumeRole, 2, "other role is assumed")
+}
+
+func (o *optionsTestSuite) TestGetClient_WebIdentity() {
+	dir, err := ioutil.TempDir("", "s3-web-identity")
+	o.NoError(err)
+	defer func() { o.NoError(os.RemoveAll(dir)) }()
+	tokenFile := filepath.Join(dir, "token")
+	o.NoError(ioutil.WriteFile(tokenFile, []byte("my-oidc-token\n"), 0600))
+
+	//from env, as set by EKS
+	_ = os.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)
+	_ = os.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/pod")
+	_ = os.Setenv("AWS_ROLE_SESSION_NAME", "pod-session")
+	client, err := getClient(Options{Region: "us-west-2"})
+	o.NoError(err)
+	value, err := client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("assumed-key", value.AccessKeyID, "web identity credentials are used")
+
+	o.Require().Len(o.sts.assumeWebIdentity, 1)
+	input := o.sts.assumeWebIdentity[0]
+	o.Equal("my-oidc-token", *input.WebIdentityToken, "token is read from file")
+	o.Equal("arn:aws:iam::123456789012:role/pod", *input.RoleArn)
+	o.Equal("pod-session", *input.RoleSessionName)
+	o.Equal("us-west-2", *o.sts.config.Region)
+	o.Equal(credentials.AnonymousCredentials, o.sts.config.Credentials, "request isn't signed")
+
+	//token file without a role
+	os.Clearenv()
+	_, err = getClient(Options{WebIdentityTokenFile: tokenFile})
+	o.Error(err, "role is required")
+
+	//missing token file
+	client, err = getClient(Options{WebIdentityTokenFile: filepath.Join(dir, "missing"), RoleARN: "arn:aws:iam::123456789012:role/pod"})
+	o.NoError(err)
+	_, err = client.(*s3.S3).Config.Credentials.Get()
+	o.Error(err, "token file can't be read")
+}
+
+func (o *optionsTestSuite) TestGetClient_ExplicitCredentialsWithWebIdentityEnv() {
+	dir, err := ioutil.TempDir("", "s3-web-identity")
+	o.NoError(err)
+	defer func() { o.NoError(os.RemoveAll(dir)) }()
+	tokenFile := filepath.Join(dir, "token")
+	o.NoError(ioutil.WriteFile(tokenFile, []byte("my-oidc-token"), 0600))
+	credentialsFile := filepath.Join(dir, "credentials")
+	o.NoError(ioutil.WriteFile(credentialsFile, []byte(
+		"[other]\naws_access_key_id = other-key\naws_secret_access_key = other-secret\n"), 0600))
+
+	//as set in an EKS pod
+	_ = os.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)
+	_ = os.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/pod")
+	_ = os.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+
+	//static keys
+	client, err := getClient(Options{AccessKeyID: "mykey", SecretAccessKey: "mysecret"})
+	o.NoError(err)
+	value, err := client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("mykey", value.AccessKeyID, "static keys are used")
+
+	//profile
+	client, err = getClient(Options{Profile: "other"})
+	o.NoError(err)
+	value, err = client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("other-key", value.AccessKeyID, "profile is used")
+
+	//role assumed with static keys
+	client, err = getClient(Options{
+		AccessKeyID:     "mykey",
+		SecretAccessKey: "mysecret",
+		RoleARN:         "arn:aws:iam::210987654321:role/reader",
+	})
+	o.NoError(err)
+	value, err = client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("assumed-key", value.AccessKeyID)
+	o.Require().Len(o.sts.assumeRole, 1)
+	o.Equal("arn:aws:iam::210987654321:role/reader", *o.sts.assumeRole[0].RoleArn)
+	baseValue, err := o.sts.config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("mykey", baseValue.AccessKeyID, "role is assumed with the static keys")
+
+	o.Empty(o.sts.assumeWebIdentity, "the env web identity is never used")
+}
+
+func (o *optionsTestSuite) TestGetClient_Profile() {
+	dir, err := ioutil.TempDir("", "s3-profile")
+	o.NoError(err)
+	defer func() { o.NoError(os.RemoveAll(dir)) }()
+	credentialsFile := filepath.Join(dir, "credentials")
+	o.NoError(ioutil.WriteFile(credentialsFile, []byte(
+		"[default]\naws_access_key_id = default-key\naws_secret_access_key = default-secret\n"+
+			"[other]\naws_access_key_id = other-key\naws_secret_access_key = other-secret\n"), 0600))
+	_ = os.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+
+	client, err := getClient(Options{})
+	o.NoError(err)
+	value, err := client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("default-key", value.AccessKeyID, "default profile")
+
+	client, err = getClient(Options{Profile: "other"})
+	o.NoError(err)
+	value, err = client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("other-key", value.AccessKeyID, "named profile")
+}
+
+func (o *optionsTestSuite) TestGetClient_ProfileOverEnv() {
+	dir, err := ioutil.TempDir("", "s3-profile")
+	o.NoError(err)
+	defer func() { o.NoError(os.RemoveAll(dir)) }()
+	credentialsFile := filepath.Join(dir, "credentials")
+	o.NoError(ioutil.WriteFile(credentialsFile, []byte(
+		"[other]\naws_access_key_id = other-key\naws_secret_access_key = other-secret\n"), 0600))
+	_ = os.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	_ = os.Setenv("AWS_ACCESS_KEY_ID", "env-key")
+	_ = os.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+
+	client, err := getClient(Options{})
+	o.NoError(err)
+	value, err := client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("env-key", value.AccessKeyID, "env credentials without a profile")
+
+	client, err = getClient(Options{Profile: "other"})
+	o.NoError(err)
+	value, err = client.(*s3.S3).Config.Credentials.Get()
+	o.NoError(err)
+	o.Equal("other-key", value.AccessKeyID, "a named profile is used over env credentials")
+}
+
+func (o *optionsTestSuite) TestOptionsFromEnv() {
+	_ = os.Setenv("VFS_S3_REGION", "us-west-2")
+	_ = os.Setenv("VFS_S3_ENDPOINT", "http://localhost:9000")
+	_ = os.Setenv("VFS_S3_FORCE_PATH_STYLE", "true")
+	_ = os.Setenv("VFS_S3_HTTP_CLIENT_TIMEOUT", "30s")
+	_ = os.Setenv("VFS_S3_MAX_RETRIES", "-1")
+	_ = os.Setenv("VFS_S3_BUCKET_ARCHIVE_ENDPOINT", "http://archive:9000")
+
+	opts, err := OptionsFromEnv(EnvPrefix, Options{Profile: "base", Region: "us-east-1"})
+	o.NoError(err)
+	o.Equal(Options{
+		Profile:           "base",
+		Region:            "us-west-2",
+		Endpoint:          "http://localhost:9000",
+		S3ForcePathStyle:  true,
+		HTTPClientTimeout: 30 * time.Second,
+		MaxRetries:        -1,
+	}, opts, "set variables replace options, others are kept")
+
+	opts, err = OptionsFromEnv(EnvPrefix+"BUCKET_ARCHIVE_", opts)
+	o.NoError(err)
+	o.Equal("http://archive:9000", opts.Endpoint, "later prefixes override")
+	o.Equal("us-west-2", opts.Region)
+
+	_ = os.Setenv("VFS_S3_MAX_RETRIES", "lots")
+	_, err = OptionsFromEnv(EnvPrefix, Options{})
+	o.EqualError(err, `invalid VFS_S3_MAX_RETRIES: strconv.Atoi: parsing "lots": invalid syntax`)
+}
+
 func TestOptions(t *testing.T) {
 	suite.Run(t, new(optionsTestSuite))
 }
@@ -0,0 +1,52 @@
+/*
+Package throttle bandwidth and request rate limiting VFS implementation.
+
+A throttle FileSystem wraps another vfs.FileSystem and limits the bytes per second read and written through its files,
+and the rate of metadata and list requests such as Exists, Size and List. Limits are shared by every File and Location
+created from one FileSystem, so they cap the total across goroutines.
+
+Usage
+
+Throttle filesystems wrap another FileSystem so they are not registered with backend. Wrap a registered backend
+directly:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend"
+      "github.com/c2fo/vfs/v3/backend/throttle"
+      _ "github.com/c2fo/vfs/v3/backend/s3"
+  )
+
+  func DoSomething() error {
+      fs := throttle.NewFileSystem(backend.Backend("s3")).WithOptions(throttle.Options{
+          ReadBytesPerSecond:  20 << 20, // 20 MiB/s
+          WriteBytesPerSecond: 10 << 20, // 10 MiB/s
+          RequestsPerSecond:   100,
+      })
+      ...
+  }
+
+Each limit is a token bucket holding up to a second's worth, so short bursts run at full speed. Large reads and writes
+are split into chunks of at most a second's worth so they're spread over time.
+
+Streaming
+
+s3 and gs files download the whole file to a temporary file on the first Read, and upload what's written on Close, so
+limiting Read and Write only limits how fast the client gets through the local copy. Throttle files implement
+io.WriterTo and io.ReaderFrom, streaming to and from s3 and gs files behind the limits, so io.Copy from or to a
+throttled file limits the download or upload itself:
+
+  _, err := io.Copy(localFile, throttledFile) // downloads at ReadBytesPerSecond
+
+Copies and Moves
+
+Copies and moves between s3 files, or between gs files, use the backend's own copy, which doesn't pass the content
+through the client, so they count as one request rather than bytes. That's unless the target is a file of another
+throttle FileSystem, whose limits would be skipped. Other copies stream from the throttled file, limited to
+ReadBytesPerSecond, to the target, limited to its WriteBytesPerSecond when it's throttled.
+
+Scheme
+
+The scheme is the wrapped FileSystem's scheme, since files are unchanged, so s3 and gs may copy to and from throttled
+files natively.
+*/
+package throttle
@@ -0,0 +1,230 @@
+package throttle
+
+import (
+	"io"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for a throttled file.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+}
+
+// Unwrap returns the throttled file.
+func (f *File) Unwrap() vfs.File {
+	return f.file
+}
+
+// Info Functions
+
+// LastModified calls LastModified on the wrapped file, once a request is allowed.
+func (f *File) LastModified() (*time.Time, error) {
+	f.fileSystem.requests.wait(1)
+	return f.file.LastModified()
+}
+
+// Name returns the name of the wrapped file.
+func (f *File) Name() string {
+	return f.file.Name()
+}
+
+// Path returns the path of the wrapped file.
+func (f *File) Path() string {
+	return f.file.Path()
+}
+
+// Exists calls Exists on the wrapped file, once a request is allowed.
+func (f *File) Exists() (bool, error) {
+	f.fileSystem.requests.wait(1)
+	return f.file.Exists()
+}
+
+// Size calls Size on the wrapped file, once a request is allowed.
+func (f *File) Size() (uint64, error) {
+	f.fileSystem.requests.wait(1)
+	return f.file.Size()
+}
+
+// Location returns a throttle Location for the wrapped file's location.
+func (f *File) Location() vfs.Location {
+	return &Location{fileSystem: f.fileSystem, location: f.file.Location()}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the file to the target file. When both are s3 or gs files, and the target isn't limited by
+// another throttle FileSystem, the backend copies without the content passing through the client, which counts as
+// one request. Otherwise the content is streamed from the file, limited to ReadBytesPerSecond, to the target, limited
+// to its WriteBytesPerSecond if it's throttled.
+func (f *File) CopyToFile(target vfs.File) error {
+	if inner, ok := f.native(target); ok {
+		f.fileSystem.requests.wait(1)
+		return f.file.CopyToFile(inner)
+	}
+
+	if err := f.stream(target); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := target.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	return f.Close()
+}
+
+// CopyToLocation copies the file to a file of the same name at location, as CopyToFile does.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.CopyToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// MoveToFile moves the file to the target file. When CopyToFile would use the backend's copy, the backend's move is
+// used and counts as one request. Otherwise the file is copied as CopyToFile does, then deleted.
+func (f *File) MoveToFile(target vfs.File) error {
+	if inner, ok := f.native(target); ok {
+		f.fileSystem.requests.wait(1)
+		return f.file.MoveToFile(inner)
+	}
+
+	if err := f.CopyToFile(target); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation moves the file to a file of the same name at location, as MoveToFile does.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.MoveToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// CRUD Operations
+
+// Delete calls Delete on the wrapped file, once a request is allowed.
+func (f *File) Delete() error {
+	f.fileSystem.requests.wait(1)
+	return f.file.Delete()
+}
+
+// Close closes the wrapped file.
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// Read implements the io.Reader interface, limited to ReadBytesPerSecond. Large reads are split so the bytes are
+// spread over time. Backends such as s3 and gs download the whole file before the first Read returns, so use io.Copy,
+// which streams with WriteTo, to limit the download itself.
+func (f *File) Read(p []byte) (int, error) {
+	return (&limitedReader{reader: f.file, limiter: f.fileSystem.reads}).Read(p)
+}
+
+// Seek calls Seek on the wrapped file.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Write implements the io.Writer interface, limited to WriteBytesPerSecond. Large writes are split so the bytes are
+// spread over time. Backends such as s3 and gs upload what's written when the file is closed, so use io.Copy, which
+// streams with ReadFrom, to limit the upload itself.
+func (f *File) Write(data []byte) (int, error) {
+	return (&limitedWriter{writer: f.file, limiter: f.fileSystem.writes}).Write(data)
+}
+
+// WriteTo implements the io.WriterTo interface, limited to ReadBytesPerSecond. When the wrapped file streams its
+// content with WriteTo, as s3 and gs files do, the limit applies to the download itself.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	limited := &limitedWriter{writer: w, limiter: f.fileSystem.reads}
+	if writerTo, ok := f.file.(io.WriterTo); ok {
+		return writerTo.WriteTo(limited)
+	}
+	return io.Copy(limited, f.file)
+}
+
+// ReadFrom implements the io.ReaderFrom interface, limited to WriteBytesPerSecond. When the wrapped file streams r with
+// ReadFrom, as s3 and gs files do, the limit applies to the upload itself.
+func (f *File) ReadFrom(r io.Reader) (int64, error) {
+	limited := &limitedReader{reader: r, limiter: f.fileSystem.writes}
+	if readerFrom, ok := f.file.(io.ReaderFrom); ok {
+		return readerFrom.ReadFrom(limited)
+	}
+	return io.Copy(f.file, limited)
+}
+
+/*
+	Private helpers
+*/
+
+// remoteCopySchemes are the schemes of backends that copy and move to another file of the same scheme without the
+// content passing through the client.
+var remoteCopySchemes = map[string]bool{"s3": true, "gs": true}
+
+// native returns target, unwrapped if it's a throttle File, and whether the backend's own copy or move can be used:
+// the backend copies remotely, and the target isn't limited by another throttle FileSystem.
+func (f *File) native(target vfs.File) (vfs.File, bool) {
+	if t, ok := target.(*File); ok {
+		if t.fileSystem != f.fileSystem {
+			return target, false
+		}
+		target = t.file
+	}
+	scheme := f.file.Location().FileSystem().Scheme()
+	return target, remoteCopySchemes[scheme] && target.Location().FileSystem().Scheme() == scheme
+}
+
+// stream copies the file to target, piping WriteTo to the target's ReadFrom when both stream, as s3 and gs files do,
+// so neither the download nor the upload is buffered in full before it's limited.
+func (f *File) stream(target vfs.File) error {
+	_, writesTo := f.file.(io.WriterTo)
+	readerFrom, readsFrom := target.(io.ReaderFrom)
+	if !writesTo || !readsFrom {
+		return utils.TouchCopy(target, f)
+	}
+	if size, err := f.Size(); err != nil {
+		return err
+	} else if size == 0 {
+		_, err = target.Write([]byte{})
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := f.WriteTo(writer)
+		_ = writer.CloseWithError(err)
+		done <- err
+	}()
+	_, err := readerFrom.ReadFrom(reader)
+	// unblocks WriteTo if ReadFrom stopped early
+	_ = reader.CloseWithError(err)
+	if werr := <-done; err == nil && werr != io.ErrClosedPipe {
+		err = werr
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package throttle
+
+import (
+	"errors"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// Options holds throttle-specific options. A zero value means no limit.
+type Options struct {
+	// ReadBytesPerSecond limits the rate of Read and WriteTo, across every File of the FileSystem.
+	ReadBytesPerSecond int64 `json:"readBytesPerSecond,omitempty"`
+
+	// WriteBytesPerSecond limits the rate of Write and ReadFrom, across every File of the FileSystem.
+	WriteBytesPerSecond int64 `json:"writeBytesPerSecond,omitempty"`
+
+	// RequestsPerSecond limits the rate of metadata and list calls, across every File and Location of the FileSystem:
+	// Exists, Size, LastModified, List, ListByPrefix, ListByRegex, Delete, DeleteFile and each copy or move.
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty"`
+}
+
+// FileSystem implements vfs.Filesystem by limiting the bandwidth and request rate of another FileSystem. Limits are
+// token buckets holding up to a second's worth, shared by every File and Location created from the FileSystem.
+type FileSystem struct {
+	fileSystem vfs.FileSystem
+	options    Options
+
+	reads    *limiter
+	writes   *limiter
+	requests *limiter
+}
+
+// NewFileSystem initializer returns a FileSystem which throttles fileSystem. Set limits with WithOptions.
+func NewFileSystem(fileSystem vfs.FileSystem) *FileSystem {
+	return &FileSystem{fileSystem: fileSystem}
+}
+
+// WithOptions sets options for the FileSystem, replacing any limits. Any options that aren't throttle.Options are
+// ignored.
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+		fs.reads = newLimiter(float64(opts.ReadBytesPerSecond))
+		fs.writes = newLimiter(float64(opts.WriteBytesPerSecond))
+		fs.requests = newLimiter(opts.RequestsPerSecond)
+	}
+	return fs
+}
+
+// Unwrap returns the throttled FileSystem.
+func (fs *FileSystem) Unwrap() vfs.FileSystem {
+	return fs.fileSystem
+}
+
+// NewFile function returns the throttle implementation of vfs.File.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("throttle FileSystem requires a FileSystem")
+	}
+	file, err := fs.fileSystem.NewFile(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: fs, file: file}, nil
+}
+
+// NewLocation function returns the throttle implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if fs.fileSystem == nil {
+		return nil, errors.New("throttle FileSystem requires a FileSystem")
+	}
+	location, err := fs.fileSystem.NewLocation(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, location: location}, nil
+}
+
+// Name returns the name of the wrapped FileSystem prefixed with "throttled", ie: "throttled AWS S3"
+func (fs *FileSystem) Name() string {
+	if fs.fileSystem == nil {
+		return "throttled"
+	}
+	return "throttled " + fs.fileSystem.Name()
+}
+
+// Scheme returns the scheme of the wrapped FileSystem. Files are unchanged, so backends may copy to and from
+// throttled files natively.
+func (fs *FileSystem) Scheme() string {
+	if fs.fileSystem == nil {
+		return ""
+	}
+	return fs.fileSystem.Scheme()
+}
@@ -0,0 +1,158 @@
+package throttle
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	_s3 "github.com/c2fo/vfs/v3/backend/s3"
+	"github.com/c2fo/vfs/v3/mocks"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir string
+	clock  *fakeClock
+	fs     *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "throttle-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.clock = &fakeClock{t: time.Unix(1000, 0)}
+	now = s.clock.now
+	sleep = s.clock.sleep
+	s.fs = NewFileSystem(&_os.FileSystem{}).WithOptions(Options{
+		ReadBytesPerSecond:  100,
+		WriteBytesPerSecond: 200,
+		RequestsPerSecond:   2,
+	})
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	now = time.Now
+	sleep = time.Sleep
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) TestReadWrite() {
+	content := strings.Repeat("x", 1000)
+	file, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "data.txt"))
+	s.NoError(err)
+	s.Equal("throttled os", file.Location().FileSystem().Name())
+
+	n, err := file.Write([]byte(content))
+	s.NoError(err)
+	s.Equal(1000, n)
+	s.NoError(file.Close())
+	s.Equal(4*time.Second, s.clock.slept, "1000 bytes at 200 bytes per second, less the first second's burst")
+
+	s.clock.slept = 0
+	read, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal(content, string(read))
+	s.NoError(file.Close())
+	s.Equal(9*time.Second, s.clock.slept, "1000 bytes at 100 bytes per second, less the first second's burst")
+}
+
+func (s *fileTestSuite) TestRequestsShared() {
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "a.txt"), []byte("a"), 0644))
+	location, err := s.fs.NewLocation("", s.tmpDir+"/")
+	s.NoError(err)
+	a, err := location.NewFile("a.txt")
+	s.NoError(err)
+	b, err := s.fs.NewFile("", filepath.Join(s.tmpDir, "b.txt"))
+	s.NoError(err)
+
+	_, err = a.Exists()
+	s.NoError(err)
+	_, err = b.Exists()
+	s.NoError(err)
+	s.Zero(s.clock.slept)
+	_, err = location.List()
+	s.NoError(err)
+	_, err = a.Size()
+	s.NoError(err)
+	s.Equal(time.Second, s.clock.slept, "4 requests at 2 per second across files and locations")
+
+	// os copies stream through the limits, so the Size of the source is one request and its byte fits the burst
+	s.clock.slept = 0
+	s.NoError(a.CopyToFile(b))
+	s.Equal(500*time.Millisecond, s.clock.slept)
+	contents, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "b.txt"))
+	s.NoError(err)
+	s.Equal("a", string(contents))
+}
+
+func (s *fileTestSuite) TestS3() {
+	content := strings.Repeat("x", 1000)
+	client := &mocks.S3API{}
+	client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(1000)}, nil)
+	client.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(func(*s3.GetObjectInput) *s3.GetObjectOutput {
+			return &s3.GetObjectOutput{Body: ioutil.NopCloser(strings.NewReader(content))}
+		}, nil)
+	client.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).Return(&s3.CopyObjectOutput{}, nil)
+	uploaded := &bytes.Buffer{}
+	client.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).
+		Return(func(input *s3.PutObjectInput) *request.Request {
+			_, _ = io.Copy(uploaded, input.Body)
+			return request.New(aws.Config{}, metadata.ClientInfo{}, request.Handlers{}, nil,
+				&request.Operation{Name: "PutObject"}, input, &s3.PutObjectOutput{})
+		}, func(*s3.PutObjectInput) *s3.PutObjectOutput {
+			return &s3.PutObjectOutput{}
+		})
+	backend := _s3.NewFileSystem().WithClient(client)
+
+	reads := NewFileSystem(backend).WithOptions(Options{ReadBytesPerSecond: 100})
+	source, err := reads.NewFile("bucket", "/source.txt")
+	s.NoError(err)
+	downloaded := &bytes.Buffer{}
+	_, err = io.Copy(downloaded, source)
+	s.NoError(err)
+	s.Equal(content, downloaded.String())
+	s.Equal(9*time.Second, s.clock.slept, "the download streams at 100 bytes per second, less the first second's burst")
+
+	// a copy into another throttle FileSystem streams, so the target's limit applies to the upload
+	s.clock.slept = 0
+	unlimited, err := NewFileSystem(backend).NewFile("bucket", "/source.txt")
+	s.NoError(err)
+	writes := NewFileSystem(backend).WithOptions(Options{WriteBytesPerSecond: 200})
+	target, err := writes.NewFile("bucket", "/target.txt")
+	s.NoError(err)
+	s.NoError(unlimited.CopyToFile(target))
+	s.Equal(content, uploaded.String())
+	s.Equal(4*time.Second, s.clock.slept, "the upload streams at 200 bytes per second, less the first second's burst")
+	client.AssertNotCalled(s.T(), "CopyObject", mock.Anything)
+
+	// a copy within one throttle FileSystem is copied by s3
+	s.clock.slept = 0
+	copied, err := writes.NewFile("bucket", "/copied.txt")
+	s.NoError(err)
+	s.NoError(target.CopyToFile(copied))
+	s.Zero(s.clock.slept)
+	client.AssertNumberOfCalls(s.T(), "CopyObject", 1)
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -0,0 +1,106 @@
+package throttle
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// now and sleep are replaced in tests.
+var (
+	now   = time.Now
+	sleep = time.Sleep
+)
+
+// limiter is a token bucket shared by every File and Location of a FileSystem. Tokens are reserved before they're
+// available, so a request for more than the bucket holds waits for the shortfall rather than failing.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64 // most tokens the bucket holds
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter returns a limiter of rate tokens per second, holding up to a second's worth, or nil when rate isn't
+// positive, meaning no limit.
+func newLimiter(rate float64) *limiter {
+	if rate <= 0 {
+		return nil
+	}
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{rate: rate, burst: burst, tokens: burst, last: now()}
+}
+
+// wait blocks until n tokens are available, taking them. A nil limiter never blocks.
+func (l *limiter) wait(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	l.mu.Lock()
+	t := now()
+	l.tokens += t.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = t
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		sleep(delay)
+	}
+}
+
+// chunk returns the most bytes to read or write at once, so that a large buffer is spread over time rather than
+// sent in a single burst. A nil limiter doesn't chunk.
+func (l *limiter) chunk(n int) int {
+	if l == nil || float64(n) <= l.burst {
+		return n
+	}
+	return int(l.burst)
+}
+
+// limitedReader reads from reader, limited to the limiter's rate. Large reads are split so the bytes are spread over
+// time.
+type limitedReader struct {
+	reader  io.Reader
+	limiter *limiter
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p[:r.limiter.chunk(len(p))])
+	r.limiter.wait(n)
+	return n, err
+}
+
+// limitedWriter writes to writer, limited to the limiter's rate. Large writes are split so the bytes are spread over
+// time.
+type limitedWriter struct {
+	writer  io.Writer
+	limiter *limiter
+}
+
+func (w *limitedWriter) Write(data []byte) (int, error) {
+	if len(data) == 0 {
+		return w.writer.Write(data)
+	}
+	written := 0
+	for written < len(data) {
+		chunk := data[written : written+w.limiter.chunk(len(data)-written)]
+		w.limiter.wait(len(chunk))
+		n, err := w.writer.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
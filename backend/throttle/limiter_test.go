@@ -0,0 +1,88 @@
+package throttle
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+// fakeClock replaces now and sleep, advancing time only when slept.
+type fakeClock struct {
+	mu    sync.Mutex
+	t     time.Time
+	slept time.Duration
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.slept += d
+	c.t = c.t.Add(d)
+}
+
+type limiterTestSuite struct {
+	suite.Suite
+	clock *fakeClock
+}
+
+func (s *limiterTestSuite) SetupTest() {
+	s.clock = &fakeClock{t: time.Unix(1000, 0)}
+	now = s.clock.now
+	sleep = s.clock.sleep
+}
+
+func (s *limiterTestSuite) TearDownTest() {
+	now = time.Now
+	sleep = time.Sleep
+}
+
+func (s *limiterTestSuite) TestNoLimit() {
+	var l *limiter
+	s.Nil(newLimiter(0))
+	l.wait(1 << 30)
+	s.Equal(1<<20, l.chunk(1<<20))
+	s.Zero(s.clock.slept)
+}
+
+func (s *limiterTestSuite) TestWait() {
+	l := newLimiter(100)
+
+	l.wait(100)
+	s.Zero(s.clock.slept, "the first second's worth is a burst")
+
+	l.wait(50)
+	s.Equal(500*time.Millisecond, s.clock.slept)
+
+	s.clock.t = s.clock.t.Add(10 * time.Second)
+	l.wait(100)
+	s.Equal(500*time.Millisecond, s.clock.slept, "idle time refills the bucket, up to the burst")
+	l.wait(1)
+	s.Equal(510*time.Millisecond, s.clock.slept)
+
+	s.Equal(100, l.chunk(1000))
+	s.Equal(10, l.chunk(10))
+}
+
+func (s *limiterTestSuite) TestSlowRate() {
+	l := newLimiter(0.5)
+	l.wait(1)
+	s.Zero(s.clock.slept, "burst is at least one")
+	l.wait(1)
+	s.Equal(2*time.Second, s.clock.slept)
+}
+
+func TestLimiter(t *testing.T) {
+	suite.Run(t, new(limiterTestSuite))
+}
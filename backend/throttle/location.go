@@ -0,0 +1,103 @@
+package throttle
+
+import (
+	"regexp"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface for a throttled location.
+type Location struct {
+	fileSystem *FileSystem
+	location   vfs.Location
+}
+
+// Unwrap returns the throttled location.
+func (l *Location) Unwrap() vfs.Location {
+	return l.location
+}
+
+// List calls List on the wrapped location, once a request is allowed.
+func (l *Location) List() ([]string, error) {
+	l.fileSystem.requests.wait(1)
+	return l.location.List()
+}
+
+// ListByPrefix calls ListByPrefix on the wrapped location, once a request is allowed.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	l.fileSystem.requests.wait(1)
+	return l.location.ListByPrefix(prefix)
+}
+
+// ListByRegex calls ListByRegex on the wrapped location, once a request is allowed.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	l.fileSystem.requests.wait(1)
+	return l.location.ListByRegex(regex)
+}
+
+// ListDirs calls ListDirs on the wrapped location, which must implement utils.DirLister, once a request is allowed.
+func (l *Location) ListDirs() ([]string, error) {
+	l.fileSystem.requests.wait(1)
+	return utils.ListDirs(l.location)
+}
+
+// Volume returns the volume of the wrapped location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path returns the path of the wrapped location.
+func (l *Location) Path() string {
+	return l.location.Path()
+}
+
+// Exists calls Exists on the wrapped location, once a request is allowed.
+func (l *Location) Exists() (bool, error) {
+	l.fileSystem.requests.wait(1)
+	return l.location.Exists()
+}
+
+// NewLocation returns a new throttle Location relative to this one.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	location, err := l.location.NewLocation(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: l.fileSystem, location: location}, nil
+}
+
+// ChangeDir changes the directory of the wrapped location.
+func (l *Location) ChangeDir(relativePath string) error {
+	return l.location.ChangeDir(relativePath)
+}
+
+// FileSystem returns the throttle FileSystem the location was created by.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a throttle File for fileName, relative to the location.
+func (l *Location) NewFile(fileName string) (vfs.File, error) {
+	file, err := l.location.NewFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: l.fileSystem, file: file}, nil
+}
+
+// DeleteFile calls DeleteFile on the wrapped location, once a request is allowed.
+func (l *Location) DeleteFile(fileName string) error {
+	l.fileSystem.requests.wait(1)
+	return l.location.DeleteFile(fileName)
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
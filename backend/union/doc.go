@@ -0,0 +1,49 @@
+/*
+Package union overlay VFS implementation.
+
+A union FileSystem is composed of an ordered list of layers, each a vfs.Location on any backend. Reads fall through the
+layers, so a file comes from the first layer containing it, while writes and deletes only ever change the first
+layer. A typical use is a local working directory over a shared, read-mostly prefix:
+
+  import(
+      "github.com/c2fo/vfs/v3/backend/union"
+      "github.com/c2fo/vfs/v3/vfssimple"
+  )
+
+  func DevFileSystem() (*union.FileSystem, error) {
+      local, err := vfssimple.NewLocation("file:///tmp/dev-data/")
+      if err != nil {
+          return nil, err
+      }
+      shared, err := vfssimple.NewLocation("s3://mybucket/shared-data/")
+      if err != nil {
+          return nil, err
+      }
+      return union.NewFileSystem(local, shared), nil
+  }
+
+Paths
+
+Paths are relative to the root of each layer, so with the layers above union path /in/data.csv is read from
+/tmp/dev-data/in/data.csv if it exists there and from s3://mybucket/shared-data/in/data.csv otherwise. Unions have no
+volume, and URIs use the "union" scheme, ie: union:///in/data.csv.
+
+Listing
+
+Location.List merges the names at the location in every layer, without duplicates, and leaves out files hidden by a
+whiteout.
+
+Writes and Copy-up
+
+Writes always go to the first layer, so the lower layers are never changed. As on other backends, writing to a file
+that hasn't been read or seeked since it was last closed replaces its content, so nothing is copied from a lower layer.
+Otherwise a file that only exists in a lower layer is first copied up to the first layer, keeping the current offset,
+so a Seek followed by a Write changes the copy the same way it would have changed the original.
+
+Deletes and Whiteouts
+
+Deleting a file removes it from the first layer. If a lower layer still has the file, an empty whiteout file named
+".wh." followed by the file name is written next to it in the first layer, hiding the lower copies. Writing the file
+again removes the whiteout. File names starting with ".wh." are reserved, and NewFile rejects them.
+*/
+package union
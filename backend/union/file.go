@@ -0,0 +1,283 @@
+package union
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for a file in the union. Reads are from the highest layer containing the file,
+// and writes are to the first layer.
+type File struct {
+	fileSystem *FileSystem
+	path       string
+
+	// active is the layer file being read or written, and activeLayer its index, or -1 when not yet resolved.
+	active      vfs.File
+	activeLayer int
+}
+
+// Info Functions
+
+// LastModified returns the timestamp of the file in the highest layer containing it.
+func (f *File) LastModified() (*time.Time, error) {
+	file, err := f.resolved()
+	if err != nil {
+		return nil, err
+	}
+	return file.LastModified()
+}
+
+// Name returns the base name of the file.
+func (f *File) Name() string {
+	return path.Base(f.path)
+}
+
+// Path returns the full path of the file, relative to the root of each layer. IE: "/some/path/to/file.txt"
+func (f *File) Path() string {
+	return f.path
+}
+
+// Exists returns whether the file exists in any layer without being hidden by a whiteout.
+func (f *File) Exists() (bool, error) {
+	layer, _, err := f.fileSystem.resolve(f.path)
+	if err != nil {
+		return false, err
+	}
+	return layer >= 0, nil
+}
+
+// Size returns the size of the file in the highest layer containing it.
+func (f *File) Size() (uint64, error) {
+	file, err := f.resolved()
+	if err != nil {
+		return 0, err
+	}
+	return file.Size()
+}
+
+// Location returns a union Location for the directory containing the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		path:       utils.EnsureTrailingSlash(path.Dir(f.path)),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile copies the file's content to the target file.
+func (f *File) CopyToFile(target vfs.File) error {
+	if err := utils.TouchCopy(target, f); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := target.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	return f.Close()
+}
+
+// CopyToLocation copies the file to a file of the same name at location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.CopyToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// MoveToFile copies the file to the target file then deletes the file.
+func (f *File) MoveToFile(target vfs.File) error {
+	if err := f.CopyToFile(target); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation copies the file to location then deletes the file, returning the new file.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := f.CopyToLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	return newFile, f.Delete()
+}
+
+// CRUD Operations
+
+// Delete deletes the file from the first layer. If the file still exists in a lower layer, a whiteout is written to
+// the first layer to hide it.
+func (f *File) Delete() error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	upper, err := f.fileSystem.layerFile(0, f.path)
+	if err != nil {
+		return err
+	}
+	existed, err := upper.Exists()
+	if err != nil {
+		return err
+	}
+	if existed {
+		if err := upper.Delete(); err != nil {
+			return err
+		}
+	}
+
+	layer, _, err := f.fileSystem.resolve(f.path)
+	if err != nil {
+		return err
+	}
+	if layer < 0 {
+		if !existed {
+			return fmt.Errorf("file does not exist at %s", f)
+		}
+		return nil
+	}
+
+	whiteout, err := f.fileSystem.layerFile(0, whiteoutPath(f.path))
+	if err != nil {
+		return err
+	}
+	if _, err := whiteout.Write([]byte{}); err != nil {
+		return err
+	}
+	return whiteout.Close()
+}
+
+// Close closes the layer file being read or written.
+func (f *File) Close() error {
+	if f.active == nil {
+		return nil
+	}
+	err := f.active.Close()
+	f.active = nil
+	f.activeLayer = -1
+	return err
+}
+
+// Read implements the io.Reader interface, reading from the highest layer containing the file.
+func (f *File) Read(p []byte) (int, error) {
+	file, err := f.resolved()
+	if err != nil {
+		return 0, err
+	}
+	return file.Read(p)
+}
+
+// Seek implements the io.Seeker interface.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	file, err := f.resolved()
+	if err != nil {
+		return 0, err
+	}
+	return file.Seek(offset, whence)
+}
+
+// Write implements the io.Writer interface, writing to the first layer. A file that's only in a lower layer is first
+// copied up to the first layer if it has been read or seeked, and any whiteout for it is removed.
+func (f *File) Write(data []byte) (int, error) {
+	if f.activeLayer != 0 {
+		if err := f.copyUp(); err != nil {
+			return 0, err
+		}
+	}
+	return f.active.Write(data)
+}
+
+/*
+	Private helpers
+*/
+
+// resolved returns the active layer file, resolving the highest layer containing the file if needed.
+func (f *File) resolved() (vfs.File, error) {
+	if f.active != nil {
+		return f.active, nil
+	}
+	layer, file, err := f.fileSystem.resolve(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if layer < 0 {
+		return nil, fmt.Errorf("file does not exist at %s", f)
+	}
+	f.active = file
+	f.activeLayer = layer
+	return file, nil
+}
+
+// copyUp makes the file in the first layer the active file. Writing replaces the content of a file that hasn't been
+// read or seeked since it was last closed, so a file in a lower layer is only copied up, at the same offset, when it
+// has been.
+func (f *File) copyUp() error {
+	seeked := f.active != nil
+	var offset int64
+	if seeked {
+		current, err := f.active.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		offset = current
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	upper, err := f.fileSystem.layerFile(0, f.path)
+	if err != nil {
+		return err
+	}
+	layer, lower, err := f.fileSystem.resolve(f.path)
+	if err != nil {
+		return err
+	}
+	if layer > 0 && seeked {
+		if err := lower.CopyToFile(upper); err != nil {
+			return err
+		}
+		if offset > 0 {
+			if _, err := upper.Seek(offset, io.SeekStart); err != nil {
+				return err
+			}
+		}
+	} else if layer < 0 {
+		whiteout, err := f.fileSystem.layerFile(0, whiteoutPath(f.path))
+		if err != nil {
+			return err
+		}
+		exists, err := whiteout.Exists()
+		if err != nil {
+			return err
+		}
+		if exists {
+			if err := whiteout.Delete(); err != nil {
+				return err
+			}
+		}
+	}
+
+	f.active = upper
+	f.activeLayer = 0
+	return nil
+}
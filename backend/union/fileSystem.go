@@ -0,0 +1,131 @@
+package union
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Scheme defines the filesystem type.
+const Scheme = "union"
+const name = "union"
+
+// WhiteoutPrefix is prepended to the name of a file in the top layer to mark that the file is deleted, hiding it in
+// the layers below.
+const WhiteoutPrefix = ".wh."
+
+// FileSystem implements vfs.Filesystem as an overlay of an ordered list of layers, each a vfs.Location on any backend.
+// Reads fall through the layers in order, while writes and deletes only ever change the first layer.
+type FileSystem struct {
+	layers []vfs.Location
+}
+
+// NewFileSystem initializer returns a FileSystem of layers, highest precedence first. The first layer is the only one
+// that's written to.
+func NewFileSystem(layers ...vfs.Location) *FileSystem {
+	return &FileSystem{layers: layers}
+}
+
+// NewFile function returns the union implementation of vfs.File for name, which is relative to the root of each
+// layer. Unions have no volume so volume must be empty.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if err := fs.validate(volume); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, errors.New("non-empty string for name is required")
+	}
+	p := cleanPath(name)
+	if p == "/" {
+		return nil, fmt.Errorf("%s is not a file", name)
+	}
+	if strings.HasPrefix(path.Base(p), WhiteoutPrefix) {
+		return nil, fmt.Errorf("file names starting with %s are reserved for whiteouts", WhiteoutPrefix)
+	}
+	return &File{fileSystem: fs, path: p, activeLayer: -1}, nil
+}
+
+// NewLocation function returns the union implementation of vfs.Location for name, which is relative to the root of
+// each layer. Unions have no volume so volume must be empty.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if err := fs.validate(volume); err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, path: utils.EnsureTrailingSlash(cleanPath(name))}, nil
+}
+
+// Name returns "union"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme returns "union" as the initial part of a file URI ie: union://
+func (fs *FileSystem) Scheme() string {
+	return Scheme
+}
+
+// layerFile returns the file at p in the layer with the given index.
+func (fs *FileSystem) layerFile(layer int, p string) (vfs.File, error) {
+	l := fs.layers[layer]
+	return l.FileSystem().NewFile(l.Volume(), path.Join(l.Path(), p))
+}
+
+// layerLocation returns the location at p in the layer with the given index.
+func (fs *FileSystem) layerLocation(layer int, p string) (vfs.Location, error) {
+	l := fs.layers[layer]
+	return l.FileSystem().NewLocation(l.Volume(), utils.EnsureTrailingSlash(path.Join(l.Path(), p)))
+}
+
+// resolve returns the index of the highest layer containing the file at p and the file in that layer. The index is -1
+// when the file doesn't exist in any layer or is hidden by a whiteout.
+func (fs *FileSystem) resolve(p string) (int, vfs.File, error) {
+	for i := range fs.layers {
+		file, err := fs.layerFile(i, p)
+		if err != nil {
+			return -1, nil, err
+		}
+		exists, err := file.Exists()
+		if err != nil {
+			return -1, nil, err
+		}
+		if exists {
+			return i, file, nil
+		}
+
+		whiteout, err := fs.layerFile(i, whiteoutPath(p))
+		if err != nil {
+			return -1, nil, err
+		}
+		whitedOut, err := whiteout.Exists()
+		if err != nil {
+			return -1, nil, err
+		}
+		if whitedOut {
+			return -1, nil, nil
+		}
+	}
+	return -1, nil, nil
+}
+
+func (fs *FileSystem) validate(volume string) error {
+	if len(fs.layers) == 0 {
+		return errors.New("union FileSystem requires at least one layer")
+	}
+	if volume != "" {
+		return errors.New("union FileSystem has no volumes")
+	}
+	return nil
+}
+
+// cleanPath returns p as an absolute path, with ".." elements stopping at the root.
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+func whiteoutPath(p string) string {
+	return path.Join(path.Dir(p), WhiteoutPrefix+path.Base(p))
+}
@@ -0,0 +1,219 @@
+package union
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/mocks"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	tmpDir string
+	fs     *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "union-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.NoError(os.MkdirAll(filepath.Join(dir, "local", "data"), 0755))
+	s.NoError(os.MkdirAll(filepath.Join(dir, "shared", "data"), 0755))
+	s.writeFile("local/data/both.txt", "local copy")
+	s.writeFile("local/data/local.txt", "local only")
+	s.writeFile("shared/data/both.txt", "shared copy")
+	s.writeFile("shared/data/shared.txt", "shared only")
+
+	osfs := &_os.FileSystem{}
+	local, err := osfs.NewLocation("", filepath.Join(dir, "local")+"/")
+	s.NoError(err)
+	shared, err := osfs.NewLocation("", filepath.Join(dir, "shared")+"/")
+	s.NoError(err)
+	s.fs = NewFileSystem(local, shared)
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *fileTestSuite) writeFile(name, contents string) {
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, name), []byte(contents), 0644))
+}
+
+func (s *fileTestSuite) readFile(name string) string {
+	contents, err := ioutil.ReadFile(filepath.Join(s.tmpDir, name))
+	s.NoError(err)
+	return string(contents)
+}
+
+func (s *fileTestSuite) readUnion(name string) string {
+	file, err := s.fs.NewFile("", name)
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.NoError(file.Close())
+	return string(contents)
+}
+
+func (s *fileTestSuite) TestRead() {
+	s.Equal("local copy", s.readUnion("/data/both.txt"), "first layer wins")
+	s.Equal("local only", s.readUnion("/data/local.txt"))
+	s.Equal("shared only", s.readUnion("/data/shared.txt"), "falls through to lower layer")
+
+	file, err := s.fs.NewFile("", "/data/shared.txt")
+	s.NoError(err)
+	s.Equal("union:///data/shared.txt", file.URI())
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(11), size)
+
+	missing, err := s.fs.NewFile("", "/data/missing.txt")
+	s.NoError(err)
+	exists, err := missing.Exists()
+	s.NoError(err)
+	s.False(exists)
+	_, err = missing.Read(make([]byte, 1))
+	s.Error(err)
+
+	_, err = s.fs.NewFile("", "/data/.wh.shared.txt")
+	s.Error(err, "whiteout names are reserved")
+	_, err = s.fs.NewFile("vol", "/data/shared.txt")
+	s.Error(err, "unions have no volume")
+}
+
+func (s *fileTestSuite) TestList() {
+	location, err := s.fs.NewLocation("", "/data/")
+	s.NoError(err)
+	names, err := location.List()
+	s.NoError(err)
+	s.Equal([]string{"both.txt", "local.txt", "shared.txt"}, names)
+
+	names, err = location.ListByPrefix("sh")
+	s.NoError(err)
+	s.Equal([]string{"shared.txt"}, names)
+
+	exists, err := location.Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	s.NoError(os.MkdirAll(filepath.Join(s.tmpDir, "local", "data", "a"), 0755))
+	s.NoError(os.MkdirAll(filepath.Join(s.tmpDir, "shared", "data", "a"), 0755))
+	s.NoError(os.MkdirAll(filepath.Join(s.tmpDir, "shared", "data", "b"), 0755))
+	dirs, err := location.(*Location).ListDirs()
+	s.NoError(err)
+	s.Equal([]string{"a", "b"}, dirs, "directories of every layer")
+}
+
+func (s *fileTestSuite) TestWriteGoesToFirstLayer() {
+	file, err := s.fs.NewFile("", "/data/new.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("brand new"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	s.Equal("brand new", s.readFile("local/data/new.txt"))
+	_, err = os.Stat(filepath.Join(s.tmpDir, "shared", "data", "new.txt"))
+	s.True(os.IsNotExist(err), "lower layer unchanged")
+}
+
+func (s *fileTestSuite) TestCopyUp() {
+	file, err := s.fs.NewFile("", "/data/shared.txt")
+	s.NoError(err)
+	_, err = file.Seek(7, io.SeekStart)
+	s.NoError(err)
+	_, err = file.Write([]byte("ONLY"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	s.Equal("shared ONLY", s.readFile("local/data/shared.txt"), "copied up then written at the same offset")
+	s.Equal("shared only", s.readFile("shared/data/shared.txt"), "lower layer unchanged")
+	s.Equal("shared ONLY", s.readUnion("/data/shared.txt"))
+}
+
+func (s *fileTestSuite) TestWriteReplacesWithoutCopyUp() {
+	osfs := &_os.FileSystem{}
+	local, err := osfs.NewLocation("", filepath.Join(s.tmpDir, "local")+"/")
+	s.NoError(err)
+	// copying or reading the lower layer fails, so a copy-up would fail the write
+	faulty := mocks.NewFaultyFileSystem(osfs, 1, mocks.Fault{
+		Ops: []mocks.FaultOp{mocks.FaultOpCopy, mocks.FaultOpRead},
+		Err: errors.New("copied up"),
+	})
+	shared, err := faulty.NewLocation("", filepath.Join(s.tmpDir, "shared")+"/")
+	s.NoError(err)
+	fs := NewFileSystem(local, shared)
+
+	file, err := fs.NewFile("", "/data/shared.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("new"))
+	s.NoError(err)
+	s.NoError(file.Close())
+	s.Equal("new", s.readFile("local/data/shared.txt"), "written without copying up")
+	s.Equal("shared only", s.readFile("shared/data/shared.txt"), "lower layer unchanged")
+}
+
+func (s *fileTestSuite) TestDeleteWhiteout() {
+	location, err := s.fs.NewLocation("", "/data/")
+	s.NoError(err)
+
+	s.NoError(location.DeleteFile("both.txt"))
+	s.NoError(location.DeleteFile("shared.txt"))
+	s.NoError(location.DeleteFile("local.txt"))
+
+	_, err = os.Stat(filepath.Join(s.tmpDir, "local", "data", ".wh.both.txt"))
+	s.NoError(err, "whiteout hides the lower copy")
+	_, err = os.Stat(filepath.Join(s.tmpDir, "local", "data", ".wh.local.txt"))
+	s.True(os.IsNotExist(err), "no whiteout needed when no lower layer has the file")
+	s.Equal("shared copy", s.readFile("shared/data/both.txt"), "lower layer unchanged")
+
+	names, err := location.List()
+	s.NoError(err)
+	s.Empty(names)
+	file, err := location.NewFile("shared.txt")
+	s.NoError(err)
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists)
+	s.Error(file.Delete(), "already deleted")
+
+	// writing again removes the whiteout, without copying up the hidden content
+	_, err = file.Write([]byte("again"))
+	s.NoError(err)
+	s.NoError(file.Close())
+	s.Equal("again", s.readUnion("/data/shared.txt"))
+	_, err = os.Stat(filepath.Join(s.tmpDir, "local", "data", ".wh.shared.txt"))
+	s.True(os.IsNotExist(err))
+	names, err = location.List()
+	s.NoError(err)
+	s.Equal([]string{"shared.txt"}, names)
+}
+
+func (s *fileTestSuite) TestMove() {
+	file, err := s.fs.NewFile("", "/data/shared.txt")
+	s.NoError(err)
+	archive, err := s.fs.NewLocation("", "/archive/")
+	s.NoError(err)
+	moved, err := file.MoveToLocation(archive)
+	s.NoError(err)
+	s.Equal("/archive/shared.txt", moved.Path())
+
+	s.Equal("shared only", s.readFile("local/archive/shared.txt"))
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.False(exists, "whited out after the move")
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
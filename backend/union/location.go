@@ -0,0 +1,181 @@
+package union
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface for a path in every layer of the union.
+type Location struct {
+	fileSystem *FileSystem
+	path       string
+}
+
+// List returns the names of the files at the location in any layer, leaving out files hidden by a whiteout in a
+// higher layer.
+func (l *Location) List() ([]string, error) {
+	seen := make(map[string]bool)
+	hidden := make(map[string]bool)
+	names := make([]string, 0)
+
+	for i := range l.fileSystem.layers {
+		location, err := l.fileSystem.layerLocation(i, l.path)
+		if err != nil {
+			return nil, err
+		}
+		layerNames, err := location.List()
+		if err != nil {
+			return nil, err
+		}
+
+		var whiteouts []string
+		for _, name := range layerNames {
+			if strings.HasPrefix(name, WhiteoutPrefix) {
+				whiteouts = append(whiteouts, strings.TrimPrefix(name, WhiteoutPrefix))
+				continue
+			}
+			if !seen[name] && !hidden[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		// whiteouts only hide files in lower layers
+		for _, name := range whiteouts {
+			hidden[name] = true
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListByPrefix returns the names of the files at the location starting with prefix, as List does.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	if err := utils.ValidateFilePrefix(prefix); err != nil {
+		return nil, err
+	}
+	all, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]string, 0)
+	for _, name := range all {
+		if strings.HasPrefix(name, prefix) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
+
+// ListByRegex returns the names of the files at the location matching regex, as List does.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	all, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]string, 0)
+	for _, name := range all {
+		if regex.MatchString(name) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
+
+// ListDirs returns the names of the directories at the location in any layer. Every layer's location must implement
+// utils.DirLister.
+func (l *Location) ListDirs() ([]string, error) {
+	seen := make(map[string]bool)
+	dirs := make([]string, 0)
+	for i := range l.fileSystem.layers {
+		location, err := l.fileSystem.layerLocation(i, l.path)
+		if err != nil {
+			return nil, err
+		}
+		layerDirs, err := utils.ListDirs(location)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range layerDirs {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// Volume returns "" since unions have no volume.
+func (l *Location) Volume() string {
+	return ""
+}
+
+// Path returns the location's path, relative to the root of each layer, with leading and trailing slashes.
+func (l *Location) Path() string {
+	return l.path
+}
+
+// Exists returns whether the location exists in any layer.
+func (l *Location) Exists() (bool, error) {
+	for i := range l.fileSystem.layers {
+		location, err := l.fileSystem.layerLocation(i, l.path)
+		if err != nil {
+			return false, err
+		}
+		exists, err := location.Exists()
+		if err != nil || exists {
+			return exists, err
+		}
+	}
+	return false, nil
+}
+
+// NewLocation returns a new Location relative to this one.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	return &Location{
+		fileSystem: l.fileSystem,
+		path:       utils.EnsureTrailingSlash(cleanPath(path.Join(l.path, relativePath))),
+	}, nil
+}
+
+// ChangeDir changes the location to a path relative to it.
+func (l *Location) ChangeDir(relativePath string) error {
+	l.path = utils.EnsureTrailingSlash(cleanPath(path.Join(l.path, relativePath)))
+	return nil
+}
+
+// FileSystem returns the union FileSystem the location was created by.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a union File for fileName, relative to the location.
+func (l *Location) NewFile(fileName string) (vfs.File, error) {
+	return l.fileSystem.NewFile("", path.Join(l.path, fileName))
+}
+
+// DeleteFile deletes the file of the given name at the location, as File.Delete does.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
@@ -0,0 +1,208 @@
+package webhdfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	apiPrefix = "/webhdfs/v1"
+
+	typeFile      = "FILE"
+	typeDirectory = "DIRECTORY"
+)
+
+// fileStatus mirrors the WebHDFS FileStatus JSON object.
+type fileStatus struct {
+	PathSuffix       string `json:"pathSuffix"`
+	Type             string `json:"type"`
+	Length           int64  `json:"length"`
+	ModificationTime int64  `json:"modificationTime"`
+}
+
+// RemoteException is the error returned by the WebHDFS API for a failed operation.
+type RemoteException struct {
+	Exception     string `json:"exception"`
+	JavaClassName string `json:"javaClassName"`
+	Message       string `json:"message"`
+	StatusCode    int    `json:"-"`
+}
+
+// Error implements the error interface.
+func (e *RemoteException) Error() string {
+	if e.Exception == "" {
+		return fmt.Sprintf("webhdfs: request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("webhdfs: %s: %s", e.Exception, e.Message)
+}
+
+// isNotFound reports whether err is WebHDFS's way of saying a path doesn't exist.
+func isNotFound(err error) bool {
+	if re, ok := err.(*RemoteException); ok {
+		return re.StatusCode == http.StatusNotFound || re.Exception == "FileNotFoundException"
+	}
+	return false
+}
+
+// operationURL builds the REST URL for an operation on path p at the namenode host.
+func (fs *FileSystem) operationURL(host, p, op string, params url.Values) string {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("op", op)
+	if fs.options.DelegationToken != "" {
+		params.Set("delegation", fs.options.DelegationToken)
+	} else if user := fs.options.user(); user != "" {
+		params.Set("user.name", user)
+	}
+
+	scheme := "http"
+	if fs.options.UseTLS {
+		scheme = "https"
+	}
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     host,
+		Path:     apiPrefix + p,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+// do sends the request, converting any error response into a *RemoteException.
+func (fs *FileSystem) do(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		var body struct {
+			RemoteException RemoteException `json:"RemoteException"`
+		}
+		// error bodies aren't guaranteed to be JSON (ie: from a proxy), so only the status is reliable
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		body.RemoteException.StatusCode = resp.StatusCode
+		return nil, &body.RemoteException
+	}
+	return resp, nil
+}
+
+// decode sends a request for op and decodes the JSON response into v.
+func (fs *FileSystem) decode(method, host, p, op string, params url.Values, v interface{}) error {
+	req, err := http.NewRequest(method, fs.operationURL(host, p, op, params), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := fs.do(fs.Client(), req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// getFileStatus returns the status of path p, or nil if it doesn't exist.
+func (fs *FileSystem) getFileStatus(host, p string) (*fileStatus, error) {
+	var result struct {
+		FileStatus fileStatus `json:"FileStatus"`
+	}
+	if err := fs.decode(http.MethodGet, host, p, "GETFILESTATUS", nil, &result); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result.FileStatus, nil
+}
+
+// listStatus returns the status of every entry in directory p. A non-existent directory returns an empty slice.
+func (fs *FileSystem) listStatus(host, p string) ([]fileStatus, error) {
+	var result struct {
+		FileStatuses struct {
+			FileStatus []fileStatus `json:"FileStatus"`
+		} `json:"FileStatuses"`
+	}
+	if err := fs.decode(http.MethodGet, host, p, "LISTSTATUS", nil, &result); err != nil {
+		if isNotFound(err) {
+			return []fileStatus{}, nil
+		}
+		return nil, err
+	}
+	return result.FileStatuses.FileStatus, nil
+}
+
+// booleanOp sends an operation that responds with {"boolean": ...}, returning an error if the result is false.
+func (fs *FileSystem) booleanOp(method, host, p, op string, params url.Values) error {
+	var result struct {
+		Boolean bool `json:"boolean"`
+	}
+	if err := fs.decode(method, host, p, op, params, &result); err != nil {
+		return err
+	}
+	if !result.Boolean {
+		return fmt.Errorf("webhdfs: %s of %s was not successful", op, p)
+	}
+	return nil
+}
+
+// open returns a reader of path p's content starting at offset. The namenode redirects to a datanode, which the
+// client follows.
+func (fs *FileSystem) open(host, p string, offset int64) (io.ReadCloser, error) {
+	params := url.Values{}
+	if offset > 0 {
+		params.Set("offset", strconv.FormatInt(offset, 10))
+	}
+	req, err := http.NewRequest(http.MethodGet, fs.operationURL(host, p, "OPEN", params), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := fs.do(fs.Client(), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// upload performs the two-step CREATE or APPEND: the namenode is asked for a datanode location without any data,
+// then the data is sent to the datanode.
+func (fs *FileSystem) upload(method, host, p, op string, params url.Values, data []byte) error {
+	req, err := http.NewRequest(method, fs.operationURL(host, p, op, params), nil)
+	if err != nil {
+		return err
+	}
+
+	noRedirect := *fs.Client()
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := fs.do(&noRedirect, req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return fmt.Errorf("webhdfs: %s of %s did not redirect to a datanode", op, p)
+	}
+
+	req, err = http.NewRequest(method, location, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err = fs.do(fs.Client(), req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return resp.Body.Close()
+}
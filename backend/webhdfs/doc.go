@@ -0,0 +1,72 @@
+/*
+Package webhdfs HDFS VFS implementation using the WebHDFS REST API.
+
+Usage
+
+Rely on github.com/c2fo/vfs/backend
+
+  import(
+      "github.com/c2fo/vfs/v3/backend"
+      "github.com/c2fo/vfs/v3/backend/webhdfs"
+  )
+
+  func UseFs() error {
+      fs := backend.Backend(webhdfs.Scheme)
+      ...
+  }
+
+Or call directly:
+
+  import "github.com/c2fo/vfs/v3/backend/webhdfs"
+
+  func DoSomething() {
+      fs := webhdfs.NewFileSystem()
+      ...
+  }
+
+The volume of a webhdfs URI is the namenode's HTTP address, so the file /data/raw/events.json on the namenode
+nn1.example.com:9870 has a URI of webhdfs://nn1.example.com:9870/data/raw/events.json and is created with:
+
+  file, err := fs.NewFile("nn1.example.com:9870", "/data/raw/events.json")
+
+webhdfs can be augmented with the following implementation-specific methods. Backend returns vfs.Filesystem interface
+so it would have to be cast as webhdfs.FileSystem to use the following:
+
+  func DoSomething() {
+
+      ...
+
+      // cast if fs was created using backend.Backend().  Not necessary if created directly from webhdfs.NewFileSystem().
+      fs = fs.(*webhdfs.FileSystem)
+
+      // to pass in client options
+      fs = fs.WithOptions(
+          webhdfs.Options{
+              User:   "etl",
+              UseTLS: true,
+          },
+      )
+
+      // to pass a specific http client, for instance one with timeouts or a custom transport
+      fs = fs.WithClient(&http.Client{Timeout: 5 * time.Minute})
+  }
+
+Operations
+
+  * Location.List and friends use LISTSTATUS, returning only files.
+  * File.Exists, Size and LastModified use GETFILESTATUS.
+  * File.Read streams with OPEN, and File.Seek reopens the stream at the new offset.
+  * File.Write buffers Options.WriteChunkSize bytes at a time, sending the first chunk with CREATE (overwriting any
+    existing file) and each subsequent chunk with APPEND. Close sends whatever remains.
+  * File.MoveToFile and MoveToLocation use RENAME when the target is on the same namenode.
+
+Authentication
+
+Pseudo authentication is done by sending Options.User (or the HADOOP_USER_NAME environment variable) as the user.name
+parameter. A delegation token may be sent instead with Options.DelegationToken.
+
+See Also
+
+See: https://hadoop.apache.org/docs/stable/hadoop-project-dist/hadoop-hdfs/WebHDFS.html
+*/
+package webhdfs
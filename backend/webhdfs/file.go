@@ -0,0 +1,309 @@
+package webhdfs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// File implements vfs.File interface for WebHDFS.
+type File struct {
+	fileSystem  *FileSystem
+	host        string
+	name        string
+	reader      io.ReadCloser
+	offset      int64
+	writeBuffer *bytes.Buffer
+	created     bool
+}
+
+// newFile initializer returns a pointer to File.
+func newFile(fs *FileSystem, host, name string) (*File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil webhdfs.FileSystem pointer is required")
+	}
+	name = utils.CleanPrefix(name)
+	if host == "" || name == "" {
+		return nil, errors.New("non-empty strings for host and name are required")
+	}
+	return &File{
+		fileSystem: fs,
+		host:       host,
+		name:       "/" + name,
+	}, nil
+}
+
+// Info Functions
+
+// LastModified returns the modificationTime from GETFILESTATUS.
+func (f *File) LastModified() (*time.Time, error) {
+	status, err := f.fileStatus()
+	if err != nil {
+		return nil, err
+	}
+	modified := time.Unix(0, status.ModificationTime*int64(time.Millisecond))
+	return &modified, nil
+}
+
+// Name returns the base name of the file. IE: "file.txt" of "webhdfs://namenode:9870/some/path/to/file.txt
+func (f *File) Name() string {
+	return path.Base(f.name)
+}
+
+// Path returns the absolute path of the file. IE: "/some/path/to/file.txt" of
+// "webhdfs://namenode:9870/some/path/to/file.txt
+func (f *File) Path() string {
+	return f.name
+}
+
+// Exists returns whether a file (not a directory) exists at the file's path, based on GETFILESTATUS.
+func (f *File) Exists() (bool, error) {
+	status, err := f.fileSystem.getFileStatus(f.host, f.name)
+	if err != nil {
+		return false, err
+	}
+	return status != nil && status.Type == typeFile, nil
+}
+
+// Size returns the length from GETFILESTATUS.
+func (f *File) Size() (uint64, error) {
+	status, err := f.fileStatus()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(status.Length), nil
+}
+
+// Location returns a vfs.Location for the directory containing the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		host:       f.host,
+		path:       utils.EnsureTrailingSlash(path.Dir(f.name)),
+	}
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Move/Copy Operations
+
+// CopyToFile puts the contents of File into the targetFile passed using io.Copy.
+func (f *File) CopyToFile(targetFile vfs.File) error {
+	if err := utils.TouchCopy(targetFile, f); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := targetFile.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	return f.Close()
+}
+
+// CopyToLocation creates a copy of *File, using the file's current name as the new file's name at the given location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.CopyToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// MoveToFile moves the file to targetFile. If the target is on the same namenode, RENAME is used, otherwise the file
+// is copied then deleted.
+func (f *File) MoveToFile(targetFile vfs.File) error {
+	if tf, ok := targetFile.(*File); ok && tf.fileSystem == f.fileSystem && tf.host == f.host {
+		return f.rename(tf)
+	}
+
+	if err := f.CopyToFile(targetFile); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation moves the file to a file of the same name at location, returning the new file. See MoveToFile.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.MoveToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// CRUD Operations
+
+// Delete discards anything buffered for write, then calls DELETE on the file.
+func (f *File) Delete() error {
+	f.writeBuffer = nil
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return f.fileSystem.booleanOp(http.MethodDelete, f.host, f.name, "DELETE", nil)
+}
+
+// Close closes any open read stream and sends anything still buffered by Write to HDFS.
+func (f *File) Close() error {
+	f.offset = 0
+	if f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.writeBuffer != nil {
+		err := f.flush()
+		f.writeBuffer = nil
+		f.created = false
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read implements the io.Reader interface, streaming the file's content with OPEN from the current offset.
+func (f *File) Read(p []byte) (int, error) {
+	if f.reader == nil {
+		reader, err := f.fileSystem.open(f.host, f.name, f.offset)
+		if err != nil {
+			return 0, err
+		}
+		f.reader = reader
+	}
+
+	n, err := f.reader.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Seek implements the io.Seeker interface. The next Read after a Seek issues a new OPEN at the new offset.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.offset + offset
+	case io.SeekEnd:
+		size, err := f.Size()
+		if err != nil {
+			return 0, err
+		}
+		newOffset = int64(size) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence value %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, errors.New("seek to a negative offset")
+	}
+
+	if newOffset != f.offset && f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		if err != nil {
+			return 0, err
+		}
+	}
+	f.offset = newOffset
+	return newOffset, nil
+}
+
+// Write implements the io.Writer interface. Data is buffered and sent with CREATE once Options.WriteChunkSize bytes
+// have accumulated, with APPEND for each chunk thereafter. Anything remaining is sent on Close().
+func (f *File) Write(data []byte) (int, error) {
+	if f.writeBuffer == nil {
+		f.writeBuffer = bytes.NewBuffer([]byte{})
+	}
+	n, err := f.writeBuffer.Write(data)
+	if err != nil {
+		return n, err
+	}
+	if int64(f.writeBuffer.Len()) >= f.fileSystem.options.writeChunkSize() {
+		if err := f.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+/*
+	Private helpers
+*/
+
+// flush sends the write buffer to HDFS, overwriting the file on the first flush and appending thereafter.
+func (f *File) flush() error {
+	if f.created {
+		if f.writeBuffer.Len() == 0 {
+			return nil
+		}
+		err := f.fileSystem.upload(http.MethodPost, f.host, f.name, "APPEND", nil, f.writeBuffer.Bytes())
+		if err != nil {
+			return err
+		}
+	} else {
+		params := url.Values{"overwrite": {"true"}}
+		err := f.fileSystem.upload(http.MethodPut, f.host, f.name, "CREATE", params, f.writeBuffer.Bytes())
+		if err != nil {
+			return err
+		}
+		f.created = true
+	}
+	f.writeBuffer.Reset()
+	return nil
+}
+
+func (f *File) fileStatus() (*fileStatus, error) {
+	status, err := f.fileSystem.getFileStatus(f.host, f.name)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil || status.Type != typeFile {
+		return nil, fmt.Errorf("file does not exist at %s", f)
+	}
+	return status, nil
+}
+
+// rename moves the file with RENAME. RENAME neither creates missing parent directories nor replaces an existing
+// destination, so both are taken care of first.
+func (f *File) rename(target *File) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if exists, err := target.Exists(); err != nil {
+		return err
+	} else if exists {
+		if err := target.Delete(); err != nil {
+			return err
+		}
+	}
+	if err := f.fileSystem.booleanOp(http.MethodPut, f.host, path.Dir(target.name), "MKDIRS", nil); err != nil {
+		return err
+	}
+	params := url.Values{"destination": {target.name}}
+	return f.fileSystem.booleanOp(http.MethodPut, f.host, f.name, "RENAME", params)
+}
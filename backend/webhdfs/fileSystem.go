@@ -0,0 +1,81 @@
+package webhdfs
+
+import (
+	"net/http"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/backend"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Scheme defines the filesystem type.
+const Scheme = "webhdfs"
+const name = "WebHDFS"
+
+// FileSystem implements vfs.Filesystem for HDFS through the WebHDFS REST API. The volume is the namenode's
+// host:port, ie: namenode.example.com:9870
+type FileSystem struct {
+	client  *http.Client
+	options Options
+}
+
+// NewFile function returns the webhdfs implementation of vfs.File.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	return newFile(fs, volume, name)
+}
+
+// NewLocation function returns the webhdfs implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	return &Location{
+		fileSystem: fs,
+		host:       volume,
+		path:       cleanLocationPath(name),
+	}, nil
+}
+
+// Name returns "WebHDFS"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme return "webhdfs" as the initial part of a file URI ie: webhdfs://
+func (fs *FileSystem) Scheme() string {
+	return Scheme
+}
+
+// Client returns the underlying http client, creating it, if necessary.
+func (fs *FileSystem) Client() *http.Client {
+	if fs.client == nil {
+		fs.client = &http.Client{}
+	}
+	return fs.client
+}
+
+// WithOptions sets options for client and returns the filesystem (chainable)
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	// only set options if vfs.Options is webhdfs.Options
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+	}
+	return fs
+}
+
+// WithClient passes in an http client and returns the filesystem (chainable)
+func (fs *FileSystem) WithClient(client *http.Client) *FileSystem {
+	fs.client = client
+	return fs
+}
+
+// NewFileSystem initializer for FileSystem struct returns a FileSystem using http.DefaultClient settings.
+func NewFileSystem() *FileSystem {
+	return &FileSystem{}
+}
+
+func cleanLocationPath(name string) string {
+	return "/" + utils.EnsureTrailingSlash(utils.CleanPrefix(name))
+}
+
+func init() {
+	//registers a default Filesystem
+	backend.Register(Scheme, NewFileSystem())
+}
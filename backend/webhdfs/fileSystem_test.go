@@ -0,0 +1,50 @@
+package webhdfs
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+}
+
+func (s *fileSystemTestSuite) TestWithOptions() {
+	fs := NewFileSystem().WithOptions("just a string")
+	s.Equal(Options{}, fs.options, "no change for non-webhdfs.Options")
+
+	fs = fs.WithOptions(Options{DelegationToken: "token", UseTLS: true})
+	s.Equal("https://nn:9870/webhdfs/v1/a?delegation=token&op=OPEN", fs.operationURL("nn:9870", "/a", "OPEN", nil))
+	s.Equal(int64(defaultWriteChunkSize), fs.options.writeChunkSize())
+}
+
+func (s *fileSystemTestSuite) TestUserFromEnv() {
+	defer os.Unsetenv("HADOOP_USER_NAME")
+	s.NoError(os.Setenv("HADOOP_USER_NAME", "hdfs"))
+	fs := NewFileSystem()
+	s.Equal("http://nn:9870/webhdfs/v1/a?op=OPEN&user.name=hdfs", fs.operationURL("nn:9870", "/a", "OPEN", nil))
+}
+
+func (s *fileSystemTestSuite) TestClient() {
+	fs := NewFileSystem()
+	s.NotNil(fs.Client(), "default client created")
+
+	client := &http.Client{}
+	s.Equal(client, fs.WithClient(client).Client())
+	s.Equal(Scheme, fs.Scheme())
+	s.Equal(name, fs.Name())
+}
+
+func (s *fileSystemTestSuite) TestNewFile_Error() {
+	_, err := NewFileSystem().NewFile("", "/a.txt")
+	s.Error(err, "host is required")
+	_, err = NewFileSystem().NewFile("nn:9870", "")
+	s.Error(err, "name is required")
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
@@ -0,0 +1,164 @@
+package webhdfs
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3/mocks"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type fileTestSuite struct {
+	suite.Suite
+	namenode *fakeNamenode
+	fs       *FileSystem
+}
+
+func (s *fileTestSuite) SetupTest() {
+	s.namenode = newFakeNamenode()
+	s.fs = NewFileSystem().WithOptions(Options{User: "etl"})
+}
+
+func (s *fileTestSuite) TearDownTest() {
+	s.namenode.Close()
+}
+
+func (s *fileTestSuite) TestInfo() {
+	s.namenode.put("/data/raw/events.json", "{}\n{}\n")
+	file, err := s.fs.NewFile(s.namenode.host(), "/data/raw/events.json")
+	s.NoError(err)
+
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(6), size)
+
+	modified, err := file.LastModified()
+	s.NoError(err)
+	s.True(time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC).Equal(*modified))
+
+	s.Equal("webhdfs://"+s.namenode.host()+"/data/raw/events.json", file.URI())
+	s.Equal("/data/raw/", file.Location().Path())
+	s.Equal("etl", s.namenode.users[0], "user.name sent")
+
+	missing, err := s.fs.NewFile(s.namenode.host(), "/data/raw/missing.json")
+	s.NoError(err)
+	exists, err = missing.Exists()
+	s.NoError(err)
+	s.False(exists)
+	_, err = missing.Size()
+	s.Error(err)
+
+	dir, err := s.fs.NewFile(s.namenode.host(), "/data/raw")
+	s.NoError(err)
+	exists, err = dir.Exists()
+	s.NoError(err)
+	s.False(exists, "directories are not files")
+}
+
+func (s *fileTestSuite) TestReadAndSeek() {
+	s.namenode.put("/a.txt", "hello world")
+	file, err := s.fs.NewFile(s.namenode.host(), "/a.txt")
+	s.NoError(err)
+
+	data := make([]byte, 5)
+	_, err = io.ReadFull(file, data)
+	s.NoError(err)
+	s.Equal("hello", string(data))
+
+	_, err = file.Seek(-5, io.SeekEnd)
+	s.NoError(err)
+	rest, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("world", string(rest))
+	s.NoError(file.Close())
+	s.Equal([]string{"OPEN", "GETFILESTATUS", "OPEN"}, s.namenode.ops, "seek reopens with offset")
+}
+
+func (s *fileTestSuite) TestWrite() {
+	file, err := s.fs.NewFile(s.namenode.host(), "/out/new.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("some content"))
+	s.NoError(err)
+	_, ok := s.namenode.get("/out/new.txt")
+	s.False(ok, "nothing sent before close")
+
+	s.NoError(file.Close())
+	content, ok := s.namenode.get("/out/new.txt")
+	s.True(ok)
+	s.Equal("some content", content)
+	s.Equal([]string{"CREATE"}, s.namenode.ops)
+}
+
+func (s *fileTestSuite) TestWriteChunked() {
+	s.namenode.put("/out/big.txt", "to be overwritten")
+	s.fs.WithOptions(Options{WriteChunkSize: 4})
+	file, err := s.fs.NewFile(s.namenode.host(), "/out/big.txt")
+	s.NoError(err)
+	for _, chunk := range []string{"abcd", "ef", "gh", "ij"} {
+		_, err = file.Write([]byte(chunk))
+		s.NoError(err)
+	}
+	s.NoError(file.Close())
+
+	content, _ := s.namenode.get("/out/big.txt")
+	s.Equal("abcdefghij", content)
+	s.Equal([]string{"CREATE", "APPEND", "APPEND"}, s.namenode.ops)
+}
+
+func (s *fileTestSuite) TestDelete() {
+	s.namenode.put("/del.txt", "x")
+	file, err := s.fs.NewFile(s.namenode.host(), "/del.txt")
+	s.NoError(err)
+	s.NoError(file.Delete())
+	_, ok := s.namenode.get("/del.txt")
+	s.False(ok)
+	s.Error(file.Delete(), "deleting a missing file fails")
+}
+
+func (s *fileTestSuite) TestMoveToFileUsesRename() {
+	s.namenode.put("/src/a.txt", "move me")
+	s.namenode.put("/dst/existing/a.txt", "old")
+	file, err := s.fs.NewFile(s.namenode.host(), "/src/a.txt")
+	s.NoError(err)
+	target, err := s.fs.NewFile(s.namenode.host(), "/dst/existing/a.txt")
+	s.NoError(err)
+
+	s.NoError(file.MoveToFile(target))
+	content, _ := s.namenode.get("/dst/existing/a.txt")
+	s.Equal("move me", content)
+	_, ok := s.namenode.get("/src/a.txt")
+	s.False(ok)
+	s.Contains(s.namenode.ops, "RENAME")
+
+	location, err := s.fs.NewLocation(s.namenode.host(), "/new/dir/")
+	s.NoError(err)
+	moved, err := target.MoveToLocation(location)
+	s.NoError(err)
+	s.Equal("/new/dir/a.txt", moved.Path())
+	content, _ = s.namenode.get("/new/dir/a.txt")
+	s.Equal("move me", content)
+}
+
+func (s *fileTestSuite) TestCopyToFile() {
+	s.namenode.put("/src/a.txt", "copy me")
+	file, err := s.fs.NewFile(s.namenode.host(), "/src/a.txt")
+	s.NoError(err)
+	target := mocks.NewStringFile("", "a.txt")
+	s.NoError(file.CopyToFile(target))
+	s.Equal("copy me", target.Content())
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -0,0 +1,134 @@
+package webhdfs
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Location implements the vfs.Location interface specific to WebHDFS.
+type Location struct {
+	fileSystem *FileSystem
+	host       string
+	path       string
+}
+
+// List calls LISTSTATUS on the location's directory, returning the names of all files (not directories) in it.
+func (l *Location) List() ([]string, error) {
+	return l.fileList(func(string) bool { return true })
+}
+
+// ListByPrefix returns the names of all files in the location's directory that start with prefix.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	if err := utils.ValidateFilePrefix(prefix); err != nil {
+		return nil, err
+	}
+	return l.fileList(func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// ListByRegex returns the names of all files in the location's directory that match the regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.fileList(regex.MatchString)
+}
+
+// ListDirs calls LISTSTATUS on the location's directory, returning the names of the directories in it.
+func (l *Location) ListDirs() ([]string, error) {
+	dirs := make([]string, 0)
+	statuses, err := l.fileSystem.listStatus(l.host, l.path)
+	if err != nil {
+		return dirs, err
+	}
+	for _, status := range statuses {
+		if status.Type == typeDirectory {
+			dirs = append(dirs, status.PathSuffix)
+		}
+	}
+	return dirs, nil
+}
+
+func (l *Location) fileList(test func(name string) bool) ([]string, error) {
+	files := make([]string, 0)
+	statuses, err := l.fileSystem.listStatus(l.host, l.path)
+	if err != nil {
+		return files, err
+	}
+	for _, status := range statuses {
+		if status.Type == typeFile && test(status.PathSuffix) {
+			files = append(files, status.PathSuffix)
+		}
+	}
+	return files, nil
+}
+
+// Volume returns the namenode host:port of the location.
+func (l *Location) Volume() string {
+	return l.host
+}
+
+// Path returns the location's directory path with leading and trailing slashes.
+func (l *Location) Path() string {
+	return l.path
+}
+
+// Exists returns true if the location's path exists on HDFS and is a directory.
+func (l *Location) Exists() (bool, error) {
+	status, err := l.fileSystem.getFileStatus(l.host, l.path)
+	if err != nil {
+		return false, err
+	}
+	return status != nil && status.Type == typeDirectory, nil
+}
+
+// NewLocation makes a copy of the underlying Location, then modifies its path by calling ChangeDir with the
+// relativePath argument, returning the resulting location.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	newLocation := &Location{}
+	*newLocation = *l
+	if err := newLocation.ChangeDir(relativePath); err != nil {
+		return nil, err
+	}
+	return newLocation, nil
+}
+
+// ChangeDir takes a relative path, and modifies the underlying Location's path. For this implementation there are no
+// errors.
+func (l *Location) ChangeDir(relativePath string) error {
+	l.path = cleanLocationPath(path.Join(l.path, relativePath))
+	return nil
+}
+
+// FileSystem returns a vfs.FileSystem interface of the location's underlying fileSystem.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile uses the properties of the calling location to generate a vfs.File. The filePath argument is expected to
+// be a relative path to the location's current path.
+func (l *Location) NewFile(filePath string) (vfs.File, error) {
+	return newFile(l.fileSystem, l.host, path.Join(l.path, filePath))
+}
+
+// DeleteFile removes the file at fileName path.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
@@ -0,0 +1,85 @@
+package webhdfs
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type locationTestSuite struct {
+	suite.Suite
+	namenode *fakeNamenode
+	fs       *FileSystem
+}
+
+func (s *locationTestSuite) SetupTest() {
+	s.namenode = newFakeNamenode()
+	s.fs = NewFileSystem()
+	for _, p := range []string{"/data/a.csv", "/data/b.csv", "/data/notes.txt", "/data/sub/c.csv"} {
+		s.namenode.put(p, p)
+	}
+}
+
+func (s *locationTestSuite) TearDownTest() {
+	s.namenode.Close()
+}
+
+func (s *locationTestSuite) TestList() {
+	location, err := s.fs.NewLocation(s.namenode.host(), "data")
+	s.NoError(err)
+	s.Equal("/data/", location.Path())
+
+	files, err := location.List()
+	s.NoError(err)
+	s.Equal([]string{"a.csv", "b.csv", "notes.txt"}, files, "directories are excluded")
+
+	files, err = location.ListByPrefix("a")
+	s.NoError(err)
+	s.Equal([]string{"a.csv"}, files)
+
+	files, err = location.ListByRegex(regexp.MustCompile(`\.csv$`))
+	s.NoError(err)
+	s.Equal([]string{"a.csv", "b.csv"}, files)
+
+	missing, err := location.NewLocation("../missing/")
+	s.NoError(err)
+	files, err = missing.List()
+	s.NoError(err)
+	s.Equal([]string{}, files, "empty slice for missing directory")
+}
+
+func (s *locationTestSuite) TestExists() {
+	location, err := s.fs.NewLocation(s.namenode.host(), "/data/sub/")
+	s.NoError(err)
+	exists, err := location.Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	s.NoError(location.ChangeDir("../../nope"))
+	s.Equal("/nope/", location.Path())
+	exists, err = location.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *locationTestSuite) TestNewFileAndDelete() {
+	location, err := s.fs.NewLocation(s.namenode.host(), "/data/")
+	s.NoError(err)
+	file, err := location.NewFile("sub/c.csv")
+	s.NoError(err)
+	s.Equal("/data/sub/c.csv", file.Path())
+	s.Equal("webhdfs://"+s.namenode.host()+"/data/", location.URI())
+
+	s.NoError(location.DeleteFile("sub/c.csv"))
+	_, ok := s.namenode.get("/data/sub/c.csv")
+	s.False(ok)
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
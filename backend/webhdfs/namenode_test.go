@@ -0,0 +1,187 @@
+package webhdfs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeNamenode is an in-memory stand-in for a WebHDFS namenode and its datanodes.
+type fakeNamenode struct {
+	*httptest.Server
+	mu    sync.Mutex
+	files map[string][]byte
+	dirs  map[string]bool
+	ops   []string
+	users []string
+}
+
+func newFakeNamenode() *fakeNamenode {
+	nn := &fakeNamenode{
+		files: make(map[string][]byte),
+		dirs:  map[string]bool{"/": true},
+	}
+	nn.Server = httptest.NewServer(http.HandlerFunc(nn.serveHTTP))
+	return nn
+}
+
+// host returns the host:port to use as a webhdfs volume.
+func (nn *fakeNamenode) host() string {
+	u, _ := url.Parse(nn.URL)
+	return u.Host
+}
+
+func (nn *fakeNamenode) put(p, content string) {
+	nn.mu.Lock()
+	defer nn.mu.Unlock()
+	nn.files[p] = []byte(content)
+	nn.mkdirs(path.Dir(p))
+}
+
+func (nn *fakeNamenode) get(p string) (string, bool) {
+	nn.mu.Lock()
+	defer nn.mu.Unlock()
+	content, ok := nn.files[p]
+	return string(content), ok
+}
+
+func (nn *fakeNamenode) mkdirs(dir string) {
+	for dir != "/" && dir != "." {
+		nn.dirs[dir] = true
+		dir = path.Dir(dir)
+	}
+}
+
+func (nn *fakeNamenode) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	nn.mu.Lock()
+	defer nn.mu.Unlock()
+
+	p := path.Clean(strings.TrimPrefix(r.URL.Path, apiPrefix))
+	query := r.URL.Query()
+	op := query.Get("op")
+	datanode := query.Get("datanode") == "true"
+	if !datanode {
+		nn.ops = append(nn.ops, op)
+		nn.users = append(nn.users, query.Get("user.name"))
+	}
+
+	redirect := func() {
+		query.Set("datanode", "true")
+		u := *r.URL
+		u.RawQuery = query.Encode()
+		w.Header().Set("Location", nn.URL+u.RequestURI())
+		w.WriteHeader(http.StatusTemporaryRedirect)
+	}
+
+	switch op {
+	case "GETFILESTATUS":
+		if content, ok := nn.files[p]; ok {
+			writeJSON(w, map[string]interface{}{"FileStatus": nn.status("", content)})
+		} else if nn.dirs[p] {
+			writeJSON(w, map[string]interface{}{"FileStatus": fileStatus{Type: typeDirectory}})
+		} else {
+			notFound(w, p)
+		}
+	case "LISTSTATUS":
+		if !nn.dirs[p] {
+			notFound(w, p)
+			return
+		}
+		statuses := make([]fileStatus, 0)
+		for name, content := range nn.files {
+			if path.Dir(name) == p {
+				statuses = append(statuses, nn.status(path.Base(name), content))
+			}
+		}
+		for dir := range nn.dirs {
+			if dir != "/" && path.Dir(dir) == p {
+				statuses = append(statuses, fileStatus{PathSuffix: path.Base(dir), Type: typeDirectory})
+			}
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].PathSuffix < statuses[j].PathSuffix })
+		writeJSON(w, map[string]interface{}{"FileStatuses": map[string]interface{}{"FileStatus": statuses}})
+	case "OPEN":
+		content, ok := nn.files[p]
+		if !ok {
+			notFound(w, p)
+		} else if !datanode {
+			redirect()
+		} else {
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			_, _ = w.Write(content[offset:])
+		}
+	case "CREATE", "APPEND":
+		if !datanode {
+			if op == "APPEND" && nn.files[p] == nil {
+				notFound(w, p)
+				return
+			}
+			redirect()
+			return
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		if op == "CREATE" {
+			nn.files[p] = data
+			nn.mkdirs(path.Dir(p))
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			nn.files[p] = append(nn.files[p], data...)
+		}
+	case "DELETE":
+		_, ok := nn.files[p]
+		delete(nn.files, p)
+		writeJSON(w, map[string]bool{"boolean": ok})
+	case "MKDIRS":
+		nn.mkdirs(p)
+		writeJSON(w, map[string]bool{"boolean": true})
+	case "RENAME":
+		dest := query.Get("destination")
+		content, ok := nn.files[p]
+		_, destExists := nn.files[dest]
+		if !ok || destExists || !nn.dirs[path.Dir(dest)] {
+			writeJSON(w, map[string]bool{"boolean": false})
+			return
+		}
+		delete(nn.files, p)
+		nn.files[dest] = content
+		writeJSON(w, map[string]bool{"boolean": true})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]interface{}{"RemoteException": RemoteException{
+			Exception: "IllegalArgumentException",
+			Message:   "Invalid value for webhdfs parameter \"op\"",
+		}})
+	}
+}
+
+func (nn *fakeNamenode) status(suffix string, content []byte) fileStatus {
+	return fileStatus{
+		PathSuffix:       suffix,
+		Type:             typeFile,
+		Length:           int64(len(content)),
+		ModificationTime: time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond),
+	}
+}
+
+func notFound(w http.ResponseWriter, p string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"RemoteException": RemoteException{
+		Exception:     "FileNotFoundException",
+		JavaClassName: "java.io.FileNotFoundException",
+		Message:       "File does not exist: " + p,
+	}})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
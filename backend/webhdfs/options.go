@@ -0,0 +1,52 @@
+package webhdfs
+
+import (
+	"os"
+
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// EnvPrefix is the prefix of the environment variables read by OptionsFromEnv for the default webhdfs FileSystem.
+const EnvPrefix = "VFS_WEBHDFS_"
+
+// defaultWriteChunkSize is the amount of written data buffered before it is sent to HDFS.
+const defaultWriteChunkSize = 64 * 1024 * 1024
+
+// Options holds WebHDFS-specific options.
+type Options struct {
+	// User is sent as the user.name parameter for pseudo authentication. Defaults to HADOOP_USER_NAME.
+	User string `json:"user,omitempty"`
+	// DelegationToken is sent as the delegation parameter, taking the place of User when set.
+	DelegationToken string `json:"delegationToken,omitempty"`
+	// UseTLS addresses the namenode with https rather than http.
+	UseTLS bool `json:"useTLS,omitempty"`
+	// WriteChunkSize is the number of bytes buffered by File.Write before being sent with CREATE (first chunk) or
+	// APPEND (subsequent chunks). Defaults to 64MB.
+	WriteChunkSize int64 `json:"writeChunkSize,omitempty"`
+}
+
+// OptionsFromEnv returns opts with each option that's set in the environment replaced. Variables are named prefix
+// followed by USER, DELEGATION_TOKEN, USE_TLS or WRITE_CHUNK_SIZE, ie: VFS_WEBHDFS_USER with EnvPrefix.
+func OptionsFromEnv(prefix string, opts Options) (Options, error) {
+	env := &utils.EnvReader{Prefix: prefix}
+	env.String("USER", &opts.User)
+	env.String("DELEGATION_TOKEN", &opts.DelegationToken)
+	env.Bool("USE_TLS", &opts.UseTLS)
+	env.Int64("WRITE_CHUNK_SIZE", &opts.WriteChunkSize)
+	return opts, env.Err()
+}
+
+// user returns the user.name to authenticate as, falling back to the environment.
+func (o Options) user() string {
+	if o.User != "" {
+		return o.User
+	}
+	return os.Getenv("HADOOP_USER_NAME")
+}
+
+func (o Options) writeChunkSize() int64 {
+	if o.WriteChunkSize > 0 {
+		return o.WriteChunkSize
+	}
+	return defaultWriteChunkSize
+}
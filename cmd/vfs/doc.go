@@ -0,0 +1,42 @@
+/*
+vfs works with files and locations on any supported system through their URIs, resolved with vfssimple so that every
+command works the same way on file://, s3://, gs:// and any other registered backend.
+Complete URI (scheme://authority/path) required except for local filesystem.
+See github.com/c2fo/vfs docs for authentication.
+Remotes named in the vfssimple config file ($VFS_CONFIG or ~/.vfs/config.json) can be used as <name>:<path>.
+
+Usage
+
+  vfs <command> [flags] <uri>...
+
+Commands
+
+  cat <uri>...             writes the content of files to stdout
+  du [-h] <uri>            shows the total size of the files below a location
+  ls [-l] [-R] <uri>       lists the files and directories at a location
+  mv <uri> <uri>           moves a file to another file, or into a location when the target ends with /
+  rm [-r] [-f] <uri>...    deletes files, or every file below a location with -r
+  serve [-write] <uri>     serves the files below a location over HTTP
+  stat <uri>...            shows the size, modification time and checksum of files
+  touch <uri>...           creates empty files, without creating any directories first
+
+A URI ending with a slash is always a location. Without one, ls decides with vfssimple.Resolve: the URI is a location
+if it's listed as a directory, or has files below it, and a file otherwise. rm -r asks for confirmation before deleting
+anything, unless -f is given. touch leaves existing files as they are, since most backends can't change a modification
+time without rewriting the content.
+
+serve serves GET and HEAD requests for files, with Range support, and listings of locations, as HTML or, with
+?format=json, as JSON (see vfshttp). It listens on -addr, localhost:8080 by default so that only local clients can
+connect, and with -write also accepts PUT and DELETE requests to write and delete files.
+
+Listing directories, for ls, rm -r and du, needs backends that can list directories: os, s3, gs and webhdfs.
+
+Examples
+
+  vfs ls -l s3://mybucket/path/to/
+  vfs cat gs://googlebucket/some/path/data.csv | head
+  vfs rm -r s3://mybucket/tmp/
+  vfs mv /some/local/file.txt s3://mybucket/path/to/
+  vfs serve -addr :8080 s3://mybucket/public/
+*/
+package main
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// du shows the total size of the files in each directory directly below a location, then of the whole location.
+func du(args []string) error {
+	flags := newFlagSet("du")
+	human := flags.Bool("h", false, "shows sizes in human readable units, ie: 1.5 MB")
+	uris, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	location, err := newLocation(uris[0])
+	if err != nil {
+		return err
+	}
+
+	var total uint64
+	var dirs []string
+	dirTotals := make(map[string]uint64)
+	err = utils.Walk(location, func(relativePath string, file vfs.File) error {
+		size, err := file.Size()
+		if err != nil {
+			return err
+		}
+		total += size
+		if i := strings.Index(relativePath, "/"); i >= 0 {
+			dir := relativePath[:i+1]
+			if _, ok := dirTotals[dir]; !ok {
+				dirs = append(dirs, dir)
+			}
+			dirTotals[dir] += size
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	format := func(n uint64) string {
+		if *human {
+			return formatBytes(n)
+		}
+		return fmt.Sprint(n)
+	}
+	out := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, dir := range dirs {
+		fmt.Fprintf(out, "%s\t%s%s\n", format(dirTotals[dir]), location.URI(), dir)
+	}
+	fmt.Fprintf(out, "%s\t%s\n", format(total), location.URI())
+	return out.Flush()
+}
+
+// formatBytes returns n in human readable units, ie: 1.5 MB.
+func formatBytes(n uint64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
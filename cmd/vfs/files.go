@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/vfssimple"
+)
+
+// md5er is implemented by Files that can report the MD5 of their content, such as os, s3 and gs files.
+type md5er interface {
+	MD5() (string, error)
+}
+
+// cat writes the content of each file to stdout in turn.
+func cat(args []string) error {
+	uris, err := parseArgs(newFlagSet("cat"), args, 1, -1)
+	if err != nil {
+		return err
+	}
+	for _, uri := range uris {
+		file, err := vfssimple.NewFile(uri)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(os.Stdout, file); err != nil {
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mv moves a file to another file, or into a location when the target ends with a slash.
+func mv(args []string) error {
+	uris, err := parseArgs(newFlagSet("mv"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	src, err := vfssimple.NewFile(uris[0])
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(uris[1], "/") {
+		location, err := vfssimple.NewLocation(uris[1])
+		if err != nil {
+			return err
+		}
+		_, err = src.MoveToLocation(location)
+		return err
+	}
+	target, err := vfssimple.NewFile(uris[1])
+	if err != nil {
+		return err
+	}
+	return src.MoveToFile(target)
+}
+
+// stat shows the URI, size, modification time and, where the backend reports one, the MD5 of each file.
+func stat(args []string) error {
+	uris, err := parseArgs(newFlagSet("stat"), args, 1, -1)
+	if err != nil {
+		return err
+	}
+	for i, uri := range uris {
+		file, err := vfssimple.NewFile(uri)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := printStat(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printStat(file vfs.File) error {
+	exists, err := file.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("file does not exist at %s", file)
+	}
+	size, err := file.Size()
+	if err != nil {
+		return err
+	}
+	modified, err := file.LastModified()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("URI:      %s\n", file.URI())
+	fmt.Printf("Size:     %d\n", size)
+	fmt.Printf("Modified: %s\n", modified.Local().Format(time.RFC3339))
+	if m, ok := file.(md5er); ok {
+		sum, err := m.MD5()
+		if err != nil {
+			return err
+		}
+		if sum != "" {
+			fmt.Printf("MD5:      %s\n", sum)
+		}
+	}
+	return nil
+}
+
+// touch creates each file that doesn't exist as an empty file. Existing files are left as they are, since most
+// backends can't change a modification time without rewriting the content.
+func touch(args []string) error {
+	uris, err := parseArgs(newFlagSet("touch"), args, 1, -1)
+	if err != nil {
+		return err
+	}
+	for _, uri := range uris {
+		file, err := vfssimple.NewFile(uri)
+		if err != nil {
+			return err
+		}
+		exists, err := file.Exists()
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := file.Write([]byte{}); err != nil {
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
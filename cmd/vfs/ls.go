@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+	"github.com/c2fo/vfs/v3/vfssimple"
+)
+
+// longFormat is the format of a line of ls -l: size, modification time and name.
+const longFormat = "%12s  %-25s  %s\n"
+
+// ls lists the files, and directories, at a location, or a single file.
+func ls(args []string) error {
+	flags := newFlagSet("ls")
+	long := flags.Bool("l", false, "shows the size and modification time of each file")
+	recursive := flags.Bool("R", false, "lists every file below the location, by relative path")
+	uris, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	file, loc, err := vfssimple.Resolve(uris[0])
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		exists, err := file.Exists()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%s does not exist", uris[0])
+		}
+		return printEntry(out, file.Name(), file, *long)
+	}
+	if *recursive {
+		return utils.Walk(loc, func(relativePath string, file vfs.File) error {
+			return printEntry(out, relativePath, file, *long)
+		})
+	}
+
+	names, err := loc.List()
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file, err := loc.NewFile(name)
+		if err != nil {
+			return err
+		}
+		if err := printEntry(out, name, file, *long); err != nil {
+			return err
+		}
+	}
+
+	if lister, ok := loc.(utils.DirLister); ok {
+		dirs, err := lister.ListDirs()
+		if err != nil {
+			return err
+		}
+		sort.Strings(dirs)
+		for _, dir := range dirs {
+			if *long {
+				fmt.Fprintf(out, longFormat, "-", "-", dir+"/")
+			} else {
+				fmt.Fprintf(out, "%s/\n", dir)
+			}
+		}
+	}
+	return nil
+}
+
+// printEntry prints name, with the file's size and modification time when long is set.
+func printEntry(out io.Writer, name string, file vfs.File, long bool) error {
+	if !long {
+		_, err := fmt.Fprintln(out, name)
+		return err
+	}
+	size, err := file.Size()
+	if err != nil {
+		return err
+	}
+	modified, err := file.LastModified()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, longFormat, fmt.Sprint(size), modified.Local().Format(time.RFC3339), name)
+	return err
+}
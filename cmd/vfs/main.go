@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+	"github.com/c2fo/vfs/v3/vfssimple"
+)
+
+const usageTemplate = `
+%[1]s works with files and locations on any supported system through their URIs.
+Complete URI (scheme://authority/path) required except for local filesystem.
+See github.com/c2fo/vfs docs for authentication.
+Remotes named in the vfssimple config file ($VFS_CONFIG or ~/.vfs/config.json) can be used as <name>:<path>.
+
+Usage:  %[1]s <command> [flags] <uri>...
+
+Commands:
+%[2]s
+Run '%[1]s <command> -help' for the flags of a command.
+
+`
+
+// command is a subcommand of vfs.
+type command struct {
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+var commands map[string]command
+
+// commands are set by init, since each command refers to commands for its usage
+func init() {
+	commands = map[string]command{
+		"ls":    {"ls [-l] [-R] <uri>", "lists the files and directories at a location", ls},
+		"cat":   {"cat <uri>...", "writes the content of files to stdout", cat},
+		"rm":    {"rm [-r] [-f] <uri>...", "deletes files, or every file below a location with -r", rm},
+		"mv":    {"mv <uri> <uri>", "moves a file to another file, or into a location when the target ends with /", mv},
+		"stat":  {"stat <uri>...", "shows the size, modification time and checksum of files", stat},
+		"touch": {"touch <uri>...", "creates empty files, without creating any directories first", touch},
+		"du":    {"du [-h] <uri>", "shows the total size of the files below a location", du},
+		"serve": {"serve [-write] <uri>", "serves the files below a location over HTTP", serve},
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-help" || os.Args[1] == "help" {
+		usage()
+		os.Exit(0)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(1)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		failMessage(err)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var list strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&list, "    %-24s %s\n", commands[name].usage, commands[name].description)
+	}
+	fmt.Fprintf(os.Stdout, usageTemplate, os.Args[0], list.String())
+}
+
+// newFlagSet returns a FlagSet for the named command, which prints the command's usage line and flags on -help.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "\nUsage:  %s %s\n\n%s\n\n", os.Args[0], commands[name].usage,
+			commands[name].description)
+		flags.PrintDefaults()
+		fmt.Fprintln(flags.Output())
+	}
+	return flags
+}
+
+// parseArgs parses the flags of a command, returning its arguments as URIs. It exits with the command's usage when
+// there are fewer than min or, if max isn't -1, more than max arguments.
+func parseArgs(flags *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	// errors exit, with ExitOnError
+	_ = flags.Parse(args)
+	if flags.NArg() < min || (max >= 0 && flags.NArg() > max) {
+		flags.Usage()
+		os.Exit(1)
+	}
+	uris := make([]string, flags.NArg())
+	for i, arg := range flags.Args() {
+		uri, err := vfssimple.NormalizeURI(arg)
+		if err != nil {
+			return nil, err
+		}
+		uris[i] = uri
+	}
+	return uris, nil
+}
+
+// newLocation returns the location at uri, which is treated as a location whether or not it ends with a slash.
+func newLocation(uri string) (vfs.Location, error) {
+	return vfssimple.NewLocation(utils.EnsureTrailingSlash(uri))
+}
+
+func failMessage(err error) {
+	red := color.New(color.FgHiRed).Add(color.Bold)
+	fmt.Fprintf(os.Stderr, red.Sprint("failed")+": %s\n", err.Error())
+	os.Exit(1)
+}
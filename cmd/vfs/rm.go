@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+	"github.com/c2fo/vfs/v3/vfssimple"
+)
+
+// rm deletes files, or with -r every file below a location after asking for confirmation.
+func rm(args []string) error {
+	flags := newFlagSet("rm")
+	recursive := flags.Bool("r", false, "deletes every file below each location")
+	force := flags.Bool("f", false, "doesn't ask for confirmation before deleting recursively")
+	uris, err := parseArgs(flags, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	for _, uri := range uris {
+		if !*recursive {
+			file, err := vfssimple.NewFile(uri)
+			if err != nil {
+				return err
+			}
+			if err := file.Delete(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		location, err := newLocation(uri)
+		if err != nil {
+			return err
+		}
+		var files []vfs.File
+		err = utils.Walk(location, func(relativePath string, file vfs.File) error {
+			files = append(files, file)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		if !*force && !confirm(fmt.Sprintf("delete %d files below %s?", len(files), location)) {
+			continue
+		}
+		for _, file := range files {
+			if err := file.Delete(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// answers is where confirm reads answers from. It's shared between questions, so that buffered answers aren't lost.
+var answers = bufio.NewReader(os.Stdin)
+
+// prompts is where confirm asks questions.
+var prompts io.Writer = os.Stderr
+
+// confirm asks question on stderr, returning whether the answer read from stdin is yes.
+func confirm(question string) bool {
+	fmt.Fprintf(prompts, "%s [y/N] ", question)
+	answer, err := answers.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/c2fo/vfs/v3/vfshttp"
+)
+
+// serve serves the files below a location over HTTP until it's interrupted.
+func serve(args []string) error {
+	flags := newFlagSet("serve")
+	addr := flags.String("addr", "localhost:8080", "the address to listen on, ie: :8080 for every interface")
+	write := flags.Bool("write", false, "allows PUT and DELETE requests to write and delete files")
+	uris, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	location, err := newLocation(uris[0])
+	if err != nil {
+		return err
+	}
+
+	handler := vfshttp.NewHandler(location).WithOptions(vfshttp.Options{AllowWrites: *write})
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	logger.Printf("serving %s on %s", location.URI(), *addr)
+	return http.ListenAndServe(*addr, logRequests(logger, handler))
+}
+
+// logRequests logs the method, path, status and duration of each request to handler.
+func logRequests(logger *log.Logger, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(recorder, r)
+		logger.Printf("%s %s %d %s", r.Method, r.URL.Path, recorder.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// statusRecorder records the status written to a ResponseWriter, for logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type vfsTestSuite struct {
+	suite.Suite
+	tmpDir string
+}
+
+func (s *vfsTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfs-cmd-test")
+	s.NoError(err)
+	s.tmpDir = dir
+}
+
+func (s *vfsTestSuite) TearDownTest() {
+	answers = bufio.NewReader(os.Stdin)
+	prompts = os.Stderr
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *vfsTestSuite) TestParseArgs() {
+	wd, err := os.Getwd()
+	s.NoError(err)
+	flags := newFlagSet("rm")
+	recursive := flags.Bool("r", false, "")
+	uris, err := parseArgs(flags, []string{"-r", "s3://bucket/logs/", "data/", "x.txt"}, 1, -1)
+	s.NoError(err)
+	s.True(*recursive)
+	s.Equal([]string{
+		"s3://bucket/logs/",
+		"file://" + filepath.Join(wd, "data") + "/",
+		"file://" + filepath.Join(wd, "x.txt"),
+	}, uris, "local paths become URIs, keeping trailing slashes")
+}
+
+func (s *vfsTestSuite) TestRmRecursiveConfirm() {
+	a := s.writeFile("a/1.txt")
+	s.writeFile("a/sub/2.txt")
+	b := s.writeFile("b/3.txt")
+
+	prompt := &bytes.Buffer{}
+	prompts = prompt
+	answers = bufio.NewReader(strings.NewReader("n\ny\n"))
+	s.NoError(rm([]string{"-r", filepath.Join(s.tmpDir, "a"), filepath.Join(s.tmpDir, "b")}))
+
+	s.Contains(prompt.String(), "delete 2 files below file://"+filepath.Join(s.tmpDir, "a")+"/?")
+	s.Contains(prompt.String(), "delete 1 files below file://"+filepath.Join(s.tmpDir, "b")+"/?")
+	s.FileExists(a, "files are kept when deleting isn't confirmed")
+	s.FileExists(filepath.Join(s.tmpDir, "a", "sub", "2.txt"))
+	_, err := os.Stat(b)
+	s.True(os.IsNotExist(err), "files are deleted once confirmed")
+}
+
+func (s *vfsTestSuite) TestRmRecursiveForce() {
+	a := s.writeFile("a/1.txt")
+	prompt := &bytes.Buffer{}
+	prompts = prompt
+	s.NoError(rm([]string{"-r", "-f", filepath.Join(s.tmpDir, "a")}))
+	s.Empty(prompt.String(), "-f doesn't ask")
+	_, err := os.Stat(a)
+	s.True(os.IsNotExist(err))
+}
+
+func (s *vfsTestSuite) TestMvIntoLocation() {
+	src := s.writeFile("src/1.txt")
+	s.NoError(os.Mkdir(filepath.Join(s.tmpDir, "target"), 0755))
+	s.NoError(mv([]string{src, filepath.Join(s.tmpDir, "target") + "/"}))
+
+	_, err := os.Stat(src)
+	s.True(os.IsNotExist(err))
+	content, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "target", "1.txt"))
+	s.NoError(err)
+	s.Equal("src/1.txt", string(content), "the file keeps its name inside the location")
+}
+
+func (s *vfsTestSuite) TestMvToFile() {
+	src := s.writeFile("src/1.txt")
+	s.NoError(mv([]string{src, filepath.Join(s.tmpDir, "renamed.txt")}))
+
+	content, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "renamed.txt"))
+	s.NoError(err)
+	s.Equal("src/1.txt", string(content))
+}
+
+// writeFile writes a file below the tmp dir, with its relative path as its content, returning its path.
+func (s *vfsTestSuite) writeFile(name string) string {
+	p := filepath.Join(s.tmpDir, name)
+	s.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+	s.NoError(ioutil.WriteFile(p, []byte(name), 0644))
+	return p
+}
+
+func TestVFS(t *testing.T) {
+	suite.Run(t, new(vfsTestSuite))
+}
@@ -9,12 +9,13 @@ require (
 	github.com/fatih/color v1.7.0
 	github.com/golang/protobuf v0.0.0-20170427213220-18c9bb326172 // indirect
 	github.com/googleapis/gax-go v0.0.0-20170321005343-9af46dd5a171 // indirect
+	github.com/klauspost/compress v1.9.8
 	github.com/mattn/go-colorable v0.0.9 // indirect
-	github.com/mattn/go-isatty v0.0.4 // indirect
+	github.com/mattn/go-isatty v0.0.4
 	github.com/stretchr/objx v0.1.1 // indirect
 	github.com/stretchr/testify v1.3.0
 	golang.org/x/net v0.0.0-20180826012351-8a410e7b638d
-	golang.org/x/oauth2 v0.0.0-20180821212333-d2e6202438be // indirect
+	golang.org/x/oauth2 v0.0.0-20180821212333-d2e6202438be
 	golang.org/x/sync v0.0.0-20190227155943-e225da77a7e6 // indirect
 	golang.org/x/sys v0.0.0-20180525142821-c11f84a56e43 // indirect
 	golang.org/x/text v0.0.0-20170401064109-f4b4367115ec // indirect
@@ -0,0 +1,491 @@
+package mocks
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// FaultOp names an operation a Fault can be injected into.
+type FaultOp string
+
+// Operations faults can be injected into. Location.List, ListByPrefix and ListByRegex are all FaultOpList, and
+// Location.Exists and DeleteFile are FaultOpExists and FaultOpDelete.
+const (
+	FaultOpRead         FaultOp = "read"
+	FaultOpWrite        FaultOp = "write"
+	FaultOpSeek         FaultOp = "seek"
+	FaultOpClose        FaultOp = "close"
+	FaultOpExists       FaultOp = "exists"
+	FaultOpSize         FaultOp = "size"
+	FaultOpLastModified FaultOp = "last_modified"
+	FaultOpDelete       FaultOp = "delete"
+	FaultOpCopy         FaultOp = "copy"
+	FaultOpMove         FaultOp = "move"
+	FaultOpList         FaultOp = "list"
+)
+
+// ErrFaultInjected is returned by a faulted operation whose Fault has no Err, other than a short read.
+var ErrFaultInjected = errors.New("injected fault")
+
+// Fault describes a failure for FaultyFileSystem to inject into matching operations.
+type Fault struct {
+	// Ops the fault applies to. Empty means every operation.
+	Ops []FaultOp
+
+	// Path, if set, limits the fault to files and locations whose Path() matches.
+	Path *regexp.Regexp
+
+	// Probability that a matching operation is faulted, between 0 and 1. Zero means always.
+	Probability float64
+
+	// Times is the most times the fault is injected. Zero means no limit.
+	Times int
+
+	// Latency is added before a faulted operation.
+	Latency time.Duration
+
+	// Err is returned by a faulted operation instead of performing it. A fault with only Latency set delays the
+	// operation without failing it.
+	Err error
+
+	// ShortIO makes a faulted Read return at most half of the requested bytes, without an error, and a faulted Write
+	// write only half of the bytes then return Err, or io.ErrShortWrite.
+	ShortIO bool
+
+	// PartialUpload makes a faulted Close of a written file write only the first half of the content before closing,
+	// then return Err, or ErrFaultInjected. Files of a FaultyFileSystem with any PartialUpload fault buffer their
+	// writes until Close.
+	PartialUpload bool
+}
+
+// FaultyFileSystem wraps any vfs.FileSystem, injecting errors, latency, short reads and writes and partial uploads
+// into the operations of its files and locations, to test that code survives flaky storage. Faults are chosen with
+// a seeded random source so a failing test can be replayed.
+//
+//	fs := mocks.NewFaultyFileSystem(&os.FileSystem{}, 42,
+//	    mocks.Fault{Ops: []mocks.FaultOp{mocks.FaultOpRead}, Probability: 0.1, Err: io.ErrUnexpectedEOF},
+//	    mocks.Fault{Ops: []mocks.FaultOp{mocks.FaultOpClose}, Path: regexp.MustCompile(`\.csv$`), Times: 1,
+//	        PartialUpload: true},
+//	)
+type FaultyFileSystem struct {
+	fileSystem vfs.FileSystem
+	faults     []Fault
+	buffer     bool
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	fired    []int
+	injected int
+}
+
+// NewFaultyFileSystem returns a FaultyFileSystem wrapping fileSystem, injecting faults chosen with a random source
+// seeded with seed. The first matching fault, in order, is injected into each operation.
+func NewFaultyFileSystem(fileSystem vfs.FileSystem, seed int64, faults ...Fault) *FaultyFileSystem {
+	fs := &FaultyFileSystem{
+		fileSystem: fileSystem,
+		faults:     faults,
+		rand:       rand.New(rand.NewSource(seed)),
+		fired:      make([]int, len(faults)),
+	}
+	for _, fault := range faults {
+		if fault.PartialUpload {
+			fs.buffer = true
+		}
+	}
+	return fs
+}
+
+// Injected returns the number of faults injected so far.
+func (fs *FaultyFileSystem) Injected() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.injected
+}
+
+// NewFile returns a FaultyFile for the wrapped FileSystem's file.
+func (fs *FaultyFileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	file, err := fs.fileSystem.NewFile(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &FaultyFile{fileSystem: fs, file: file}, nil
+}
+
+// NewLocation returns a FaultyLocation for the wrapped FileSystem's location.
+func (fs *FaultyFileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	location, err := fs.fileSystem.NewLocation(volume, name)
+	if err != nil {
+		return nil, err
+	}
+	return &FaultyLocation{fileSystem: fs, location: location}, nil
+}
+
+// Name returns the name of the wrapped FileSystem.
+func (fs *FaultyFileSystem) Name() string {
+	return fs.fileSystem.Name()
+}
+
+// Scheme returns the scheme of the wrapped FileSystem, so code under test sees the scheme it expects.
+func (fs *FaultyFileSystem) Scheme() string {
+	return fs.fileSystem.Scheme()
+}
+
+// fault returns the first fault matching op on p, after sleeping for its latency, or nil if none is injected.
+func (fs *FaultyFileSystem) fault(op FaultOp, p string) *Fault {
+	fs.mu.Lock()
+	var injected *Fault
+	for i := range fs.faults {
+		fault := &fs.faults[i]
+		if !fault.matches(op, p) || (fault.Times > 0 && fs.fired[i] >= fault.Times) {
+			continue
+		}
+		if fault.Probability > 0 && fs.rand.Float64() >= fault.Probability {
+			continue
+		}
+		fs.fired[i]++
+		fs.injected++
+		injected = fault
+		break
+	}
+	fs.mu.Unlock()
+
+	if injected != nil && injected.Latency > 0 {
+		time.Sleep(injected.Latency)
+	}
+	return injected
+}
+
+func (f *Fault) matches(op FaultOp, p string) bool {
+	if f.Path != nil && !f.Path.MatchString(p) {
+		return false
+	}
+	if len(f.Ops) == 0 {
+		return true
+	}
+	for _, o := range f.Ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// err returns the error a fault injects, if any. A fault with nothing but its Ops, Path, Probability or Times set
+// injects ErrFaultInjected.
+func (f *Fault) err() error {
+	if f == nil {
+		return nil
+	}
+	if f.Err == nil && f.Latency == 0 && !f.ShortIO && !f.PartialUpload {
+		return ErrFaultInjected
+	}
+	return f.Err
+}
+
+// FaultyLocation is the vfs.Location of a FaultyFileSystem.
+type FaultyLocation struct {
+	fileSystem *FaultyFileSystem
+	location   vfs.Location
+}
+
+// List calls List on the wrapped location, unless faulted.
+func (l *FaultyLocation) List() ([]string, error) {
+	if err := l.fileSystem.fault(FaultOpList, l.location.Path()).err(); err != nil {
+		return nil, err
+	}
+	return l.location.List()
+}
+
+// ListByPrefix calls ListByPrefix on the wrapped location, unless faulted.
+func (l *FaultyLocation) ListByPrefix(prefix string) ([]string, error) {
+	if err := l.fileSystem.fault(FaultOpList, l.location.Path()).err(); err != nil {
+		return nil, err
+	}
+	return l.location.ListByPrefix(prefix)
+}
+
+// ListByRegex calls ListByRegex on the wrapped location, unless faulted.
+func (l *FaultyLocation) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	if err := l.fileSystem.fault(FaultOpList, l.location.Path()).err(); err != nil {
+		return nil, err
+	}
+	return l.location.ListByRegex(regex)
+}
+
+// Volume returns the volume of the wrapped location.
+func (l *FaultyLocation) Volume() string {
+	return l.location.Volume()
+}
+
+// Path returns the path of the wrapped location.
+func (l *FaultyLocation) Path() string {
+	return l.location.Path()
+}
+
+// Exists calls Exists on the wrapped location, unless faulted.
+func (l *FaultyLocation) Exists() (bool, error) {
+	if err := l.fileSystem.fault(FaultOpExists, l.location.Path()).err(); err != nil {
+		return false, err
+	}
+	return l.location.Exists()
+}
+
+// NewLocation returns a FaultyLocation relative to this one.
+func (l *FaultyLocation) NewLocation(relativePath string) (vfs.Location, error) {
+	location, err := l.location.NewLocation(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return &FaultyLocation{fileSystem: l.fileSystem, location: location}, nil
+}
+
+// ChangeDir changes the directory of the wrapped location.
+func (l *FaultyLocation) ChangeDir(relativePath string) error {
+	return l.location.ChangeDir(relativePath)
+}
+
+// FileSystem returns the FaultyFileSystem the location was created by.
+func (l *FaultyLocation) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a FaultyFile for fileName, relative to the location.
+func (l *FaultyLocation) NewFile(fileName string) (vfs.File, error) {
+	file, err := l.location.NewFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return &FaultyFile{fileSystem: l.fileSystem, file: file}, nil
+}
+
+// DeleteFile deletes the file of the given name at the location, as FaultyFile.Delete does.
+func (l *FaultyLocation) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *FaultyLocation) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *FaultyLocation) String() string {
+	return l.URI()
+}
+
+// FaultyFile is the vfs.File of a FaultyFileSystem.
+type FaultyFile struct {
+	fileSystem *FaultyFileSystem
+	file       vfs.File
+	written    *bytes.Buffer
+}
+
+// LastModified calls LastModified on the wrapped file, unless faulted.
+func (f *FaultyFile) LastModified() (*time.Time, error) {
+	if err := f.fault(FaultOpLastModified).err(); err != nil {
+		return nil, err
+	}
+	return f.file.LastModified()
+}
+
+// Name returns the name of the wrapped file.
+func (f *FaultyFile) Name() string {
+	return f.file.Name()
+}
+
+// Path returns the path of the wrapped file.
+func (f *FaultyFile) Path() string {
+	return f.file.Path()
+}
+
+// Exists calls Exists on the wrapped file, unless faulted.
+func (f *FaultyFile) Exists() (bool, error) {
+	if err := f.fault(FaultOpExists).err(); err != nil {
+		return false, err
+	}
+	return f.file.Exists()
+}
+
+// Size calls Size on the wrapped file, unless faulted.
+func (f *FaultyFile) Size() (uint64, error) {
+	if err := f.fault(FaultOpSize).err(); err != nil {
+		return 0, err
+	}
+	return f.file.Size()
+}
+
+// Location returns a FaultyLocation for the wrapped file's location.
+func (f *FaultyFile) Location() vfs.Location {
+	return &FaultyLocation{fileSystem: f.fileSystem, location: f.file.Location()}
+}
+
+// URI returns the File's URI as a string.
+func (f *FaultyFile) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *FaultyFile) String() string {
+	return f.URI()
+}
+
+// CopyToFile calls CopyToFile on the wrapped file, unless faulted. A FaultyFile target isn't unwrapped, so its write
+// faults apply.
+func (f *FaultyFile) CopyToFile(target vfs.File) error {
+	if err := f.fault(FaultOpCopy).err(); err != nil {
+		return err
+	}
+	if err := f.flush(); err != nil {
+		return err
+	}
+	return f.file.CopyToFile(target)
+}
+
+// CopyToLocation calls CopyToLocation on the wrapped file, unless faulted.
+func (f *FaultyFile) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	if err := f.fault(FaultOpCopy).err(); err != nil {
+		return nil, err
+	}
+	if err := f.flush(); err != nil {
+		return nil, err
+	}
+	return f.file.CopyToLocation(location)
+}
+
+// MoveToFile calls MoveToFile on the wrapped file, unless faulted.
+func (f *FaultyFile) MoveToFile(target vfs.File) error {
+	if err := f.fault(FaultOpMove).err(); err != nil {
+		return err
+	}
+	if err := f.flush(); err != nil {
+		return err
+	}
+	return f.file.MoveToFile(target)
+}
+
+// MoveToLocation calls MoveToLocation on the wrapped file, unless faulted.
+func (f *FaultyFile) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	if err := f.fault(FaultOpMove).err(); err != nil {
+		return nil, err
+	}
+	if err := f.flush(); err != nil {
+		return nil, err
+	}
+	return f.file.MoveToLocation(location)
+}
+
+// Delete calls Delete on the wrapped file, unless faulted.
+func (f *FaultyFile) Delete() error {
+	if err := f.fault(FaultOpDelete).err(); err != nil {
+		return err
+	}
+	f.written = nil
+	return f.file.Delete()
+}
+
+// Close closes the wrapped file, unless faulted. A PartialUpload fault writes only half of the buffered content
+// before closing.
+func (f *FaultyFile) Close() error {
+	fault := f.fault(FaultOpClose)
+	if fault != nil && fault.PartialUpload && f.written != nil {
+		written := f.written.Bytes()
+		f.written = nil
+		if _, err := f.file.Write(written[:len(written)/2]); err != nil {
+			return err
+		}
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		if fault.Err != nil {
+			return fault.Err
+		}
+		return ErrFaultInjected
+	}
+	if err := fault.err(); err != nil {
+		return err
+	}
+	if err := f.flush(); err != nil {
+		return err
+	}
+	return f.file.Close()
+}
+
+// Read calls Read on the wrapped file, unless faulted. A ShortIO fault reads at most half of len(p) bytes.
+func (f *FaultyFile) Read(p []byte) (int, error) {
+	fault := f.fault(FaultOpRead)
+	if err := fault.err(); err != nil {
+		return 0, err
+	}
+	if fault != nil && fault.ShortIO && len(p) > 1 {
+		p = p[:len(p)/2]
+	}
+	return f.file.Read(p)
+}
+
+// Seek calls Seek on the wrapped file, unless faulted.
+func (f *FaultyFile) Seek(offset int64, whence int) (int64, error) {
+	if err := f.fault(FaultOpSeek).err(); err != nil {
+		return 0, err
+	}
+	if err := f.flush(); err != nil {
+		return 0, err
+	}
+	return f.file.Seek(offset, whence)
+}
+
+// Write calls Write on the wrapped file, unless faulted. A ShortIO fault writes half of data then returns an error.
+func (f *FaultyFile) Write(data []byte) (int, error) {
+	fault := f.fault(FaultOpWrite)
+	if fault != nil && fault.ShortIO {
+		n, err := f.write(data[:len(data)/2])
+		if err != nil {
+			return n, err
+		}
+		if fault.Err != nil {
+			return n, fault.Err
+		}
+		return n, io.ErrShortWrite
+	}
+	if err := fault.err(); err != nil {
+		return 0, err
+	}
+	return f.write(data)
+}
+
+func (f *FaultyFile) fault(op FaultOp) *Fault {
+	return f.fileSystem.fault(op, f.file.Path())
+}
+
+// write writes data to the wrapped file, or the buffer when PartialUpload faults are possible.
+func (f *FaultyFile) write(data []byte) (int, error) {
+	if !f.fileSystem.buffer {
+		return f.file.Write(data)
+	}
+	if f.written == nil {
+		f.written = &bytes.Buffer{}
+	}
+	return f.written.Write(data)
+}
+
+// flush writes any buffered content to the wrapped file.
+func (f *FaultyFile) flush() error {
+	if f.written == nil {
+		return nil
+	}
+	written := f.written.Bytes()
+	f.written = nil
+	_, err := f.file.Write(written)
+	return err
+}
@@ -0,0 +1,139 @@
+package mocks_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/mocks"
+)
+
+type faultyFileSystemTestSuite struct {
+	suite.Suite
+	tmpDir string
+}
+
+func (s *faultyFileSystemTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "faulty-test")
+	s.NoError(err)
+	s.tmpDir = dir
+}
+
+func (s *faultyFileSystemTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *faultyFileSystemTestSuite) newFile(fs vfs.FileSystem, name string) vfs.File {
+	file, err := fs.NewFile("", filepath.Join(s.tmpDir, name))
+	s.NoError(err)
+	return file
+}
+
+func (s *faultyFileSystemTestSuite) TestErrByOpAndPath() {
+	injected := errors.New("boom")
+	fs := mocks.NewFaultyFileSystem(&_os.FileSystem{}, 1,
+		mocks.Fault{Ops: []mocks.FaultOp{mocks.FaultOpExists}, Path: regexp.MustCompile(`\.csv$`), Err: injected})
+
+	_, err := s.newFile(fs, "a.csv").Exists()
+	s.Equal(injected, err)
+	_, err = s.newFile(fs, "a.txt").Exists()
+	s.NoError(err, "path doesn't match")
+	_, err = s.newFile(fs, "a.csv").Size()
+	s.Error(err, "size isn't faulted but the file doesn't exist")
+	s.NotEqual(injected, err)
+	s.Equal(1, fs.Injected())
+}
+
+func (s *faultyFileSystemTestSuite) TestTimes() {
+	fs := mocks.NewFaultyFileSystem(&_os.FileSystem{}, 1,
+		mocks.Fault{Ops: []mocks.FaultOp{mocks.FaultOpWrite}, Times: 2, Err: mocks.ErrFaultInjected})
+	file := s.newFile(fs, "times.txt")
+
+	for i := 0; i < 2; i++ {
+		_, err := file.Write([]byte("x"))
+		s.Equal(mocks.ErrFaultInjected, err)
+	}
+	_, err := file.Write([]byte("ok"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	content, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "times.txt"))
+	s.NoError(err)
+	s.Equal("ok", string(content))
+}
+
+func (s *faultyFileSystemTestSuite) TestProbabilityIsDeterministic() {
+	run := func() []bool {
+		fs := mocks.NewFaultyFileSystem(&_os.FileSystem{}, 42,
+			mocks.Fault{Ops: []mocks.FaultOp{mocks.FaultOpExists}, Probability: 0.5, Err: mocks.ErrFaultInjected})
+		file := s.newFile(fs, "p.txt")
+		var faulted []bool
+		for i := 0; i < 20; i++ {
+			_, err := file.Exists()
+			faulted = append(faulted, err != nil)
+		}
+		return faulted
+	}
+	first := run()
+	s.Equal(first, run(), "same seed injects the same faults")
+	s.Contains(first, true)
+	s.Contains(first, false)
+}
+
+func (s *faultyFileSystemTestSuite) TestShortIO() {
+	fs := mocks.NewFaultyFileSystem(&_os.FileSystem{}, 1,
+		mocks.Fault{Ops: []mocks.FaultOp{mocks.FaultOpRead, mocks.FaultOpWrite}, Times: 2, ShortIO: true})
+	file := s.newFile(fs, "short.txt")
+
+	n, err := file.Write([]byte("abcdefgh"))
+	s.Equal(io.ErrShortWrite, err)
+	s.Equal(4, n)
+	s.NoError(file.Close())
+
+	p := make([]byte, 4)
+	n, err = file.Read(p)
+	s.NoError(err)
+	s.Equal(2, n)
+	s.Equal("ab", string(p[:n]))
+}
+
+func (s *faultyFileSystemTestSuite) TestPartialUpload() {
+	fs := mocks.NewFaultyFileSystem(&_os.FileSystem{}, 1,
+		mocks.Fault{Ops: []mocks.FaultOp{mocks.FaultOpClose}, PartialUpload: true})
+	file := s.newFile(fs, "partial.txt")
+
+	_, err := file.Write([]byte("abcd"))
+	s.NoError(err)
+	_, err = file.Write([]byte("efgh"))
+	s.NoError(err)
+	s.Equal(mocks.ErrFaultInjected, file.Close())
+
+	content, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "partial.txt"))
+	s.NoError(err)
+	s.Equal("abcd", string(content))
+}
+
+func (s *faultyFileSystemTestSuite) TestLocationList() {
+	fs := mocks.NewFaultyFileSystem(&_os.FileSystem{}, 1,
+		mocks.Fault{Ops: []mocks.FaultOp{mocks.FaultOpList}, Times: 1})
+	location, err := fs.NewLocation("", s.tmpDir+"/")
+	s.NoError(err)
+
+	_, err = location.List()
+	s.Equal(mocks.ErrFaultInjected, err)
+	_, err = location.List()
+	s.NoError(err)
+	s.Equal(fs, location.FileSystem())
+}
+
+func TestFaultyFileSystem(t *testing.T) {
+	suite.Run(t, new(faultyFileSystemTestSuite))
+}
@@ -4,10 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path"
 	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/c2fo/vfs/v3"
 )
@@ -94,3 +98,162 @@ func TouchCopy(writer, reader vfs.File) error {
 	}
 	return nil
 }
+
+// TouchCopyReader is TouchCopy for a reader that isn't a vfs.File, such as a request body, whose size isn't known up
+// front. It returns the number of bytes copied, and guarantees a Write() call on the target file.
+func TouchCopyReader(writer vfs.File, reader io.Reader) (int64, error) {
+	written, err := io.Copy(writer, reader)
+	if err == nil && written == 0 {
+		_, err = writer.Write([]byte{})
+	}
+	return written, err
+}
+
+// DirLister is implemented by Locations that can list the directories directly inside them, which Walk requires.
+type DirLister interface {
+	// ListDirs returns the base names, without trailing slashes, of the directories directly inside the location.
+	ListDirs() ([]string, error)
+}
+
+// ListDirs returns the directories directly inside location when it implements DirLister, or an error saying that
+// its locations can't list directories.
+func ListDirs(location vfs.Location) ([]string, error) {
+	lister, ok := location.(DirLister)
+	if !ok {
+		return nil, fmt.Errorf("%s locations can't list directories", location.FileSystem().Scheme())
+	}
+	return lister.ListDirs()
+}
+
+// WalkFunc is called by Walk for each file, with the file's path relative to the location being walked.
+type WalkFunc func(relativePath string, file vfs.File) error
+
+// Walk calls fn for every file at location and in every directory below it, in lexical order. The location, and every
+// location below it, must implement DirLister. Walk stops at the first error from fn.
+func Walk(location vfs.Location, fn WalkFunc) error {
+	return walk(location, "", fn)
+}
+
+func walk(location vfs.Location, relativeDir string, fn WalkFunc) error {
+	lister, ok := location.(DirLister)
+	if !ok {
+		return fmt.Errorf("%s locations can't list directories", location.FileSystem().Scheme())
+	}
+
+	names, err := location.List()
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file, err := location.NewFile(name)
+		if err != nil {
+			return err
+		}
+		if err := fn(path.Join(relativeDir, name), file); err != nil {
+			return err
+		}
+	}
+
+	dirs, err := lister.ListDirs()
+	if err != nil {
+		return err
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		sub, err := location.NewLocation(EnsureTrailingSlash(dir))
+		if err != nil {
+			return err
+		}
+		if err := walk(sub, path.Join(relativeDir, dir), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnvReader sets the fields of a backend's Options from environment variables named Prefix followed by a field's
+// name, ie: VFS_S3_ENDPOINT. Unset or empty variables leave their field as it is. The first variable that can't be
+// parsed is reported by Err, and no fields are set after it.
+type EnvReader struct {
+	Prefix string
+	err    error
+}
+
+// String sets field from the variable Prefix+name.
+func (r *EnvReader) String(name string, field *string) {
+	if value, ok := r.lookup(name); ok {
+		*field = value
+	}
+}
+
+// Strings sets field from the comma separated list in the variable Prefix+name.
+func (r *EnvReader) Strings(name string, field *[]string) {
+	if value, ok := r.lookup(name); ok {
+		*field = SplitList(value)
+	}
+}
+
+// Bool sets field from the variable Prefix+name, which may be any value accepted by strconv.ParseBool.
+func (r *EnvReader) Bool(name string, field *bool) {
+	if value, ok := r.lookup(name); ok {
+		b, err := strconv.ParseBool(value)
+		r.set(name, err, func() { *field = b })
+	}
+}
+
+// Int sets field from the variable Prefix+name.
+func (r *EnvReader) Int(name string, field *int) {
+	if value, ok := r.lookup(name); ok {
+		i, err := strconv.Atoi(value)
+		r.set(name, err, func() { *field = i })
+	}
+}
+
+// Int64 sets field from the variable Prefix+name.
+func (r *EnvReader) Int64(name string, field *int64) {
+	if value, ok := r.lookup(name); ok {
+		i, err := strconv.ParseInt(value, 10, 64)
+		r.set(name, err, func() { *field = i })
+	}
+}
+
+// Duration sets field from the variable Prefix+name, which may be any value accepted by time.ParseDuration, ie: 30s.
+func (r *EnvReader) Duration(name string, field *time.Duration) {
+	if value, ok := r.lookup(name); ok {
+		d, err := time.ParseDuration(value)
+		r.set(name, err, func() { *field = d })
+	}
+}
+
+// Err returns the error parsing the first variable that couldn't be parsed, if any.
+func (r *EnvReader) Err() error {
+	return r.err
+}
+
+func (r *EnvReader) lookup(name string) (string, bool) {
+	if r.err != nil {
+		return "", false
+	}
+	value := os.Getenv(r.Prefix + name)
+	return value, value != ""
+}
+
+func (r *EnvReader) set(name string, err error, set func()) {
+	if err != nil {
+		r.err = fmt.Errorf("invalid %s%s: %s", r.Prefix, name, err)
+		return
+	}
+	set()
+}
+
+// SplitList returns the non-empty, trimmed elements of a comma separated list.
+func SplitList(list string) []string {
+	var elements []string
+	for _, element := range strings.Split(list, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
@@ -1,16 +1,21 @@
 package utils_test
 
 import (
+	"errors"
 	"fmt"
 	_os "github.com/c2fo/vfs/v3/backend/os"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/c2fo/vfs/v3"
 	"github.com/c2fo/vfs/v3/mocks"
 	"github.com/c2fo/vfs/v3/utils"
 )
@@ -207,6 +212,108 @@ func (s *utilsTest) TestTouchCopy() {
 
 }
 
+func (s *utilsTest) TestTouchCopyReader() {
+	dir, err := ioutil.TempDir("", "utils_touch")
+	s.NoError(err)
+	defer os.RemoveAll(dir)
+
+	osfs := _os.FileSystem{}
+	writer, err := osfs.NewFile("", filepath.Join(dir, "empty.txt"))
+	s.NoError(err)
+	written, err := utils.TouchCopyReader(writer, strings.NewReader(""))
+	s.NoError(err)
+	s.Zero(written)
+	s.NoError(writer.Close())
+	s.FileExists(writer.Path(), "an empty reader still writes the file")
+
+	writer, err = osfs.NewFile("", filepath.Join(dir, "content.txt"))
+	s.NoError(err)
+	written, err = utils.TouchCopyReader(writer, strings.NewReader("blah"))
+	s.NoError(err)
+	s.Equal(int64(4), written)
+	s.NoError(writer.Close())
+	content, err := ioutil.ReadFile(writer.Path())
+	s.NoError(err)
+	s.Equal("blah", string(content))
+}
+
+func (s *utilsTest) TestWalk() {
+	dir, err := ioutil.TempDir("", "utils_walk")
+	s.NoError(err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"b.txt", "a/x.txt", "a/b/y.txt", "c/z.txt"} {
+		s.NoError(os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		s.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+	s.NoError(os.MkdirAll(filepath.Join(dir, "empty"), 0755))
+
+	osfs := _os.FileSystem{}
+	location, err := osfs.NewLocation("", dir+"/")
+	s.NoError(err)
+
+	var walked []string
+	err = utils.Walk(location, func(relativePath string, file vfs.File) error {
+		walked = append(walked, relativePath)
+		s.Equal(filepath.Join(dir, relativePath), file.Path())
+		return nil
+	})
+	s.NoError(err)
+	s.Equal([]string{"b.txt", "a/x.txt", "a/b/y.txt", "c/z.txt"}, walked)
+
+	stop := errors.New("stop")
+	err = utils.Walk(location, func(string, vfs.File) error { return stop })
+	s.Equal(stop, err, "should stop at the first error")
+
+	// embedding the interface hides ListDirs
+	notLister := struct{ vfs.Location }{location}
+	err = utils.Walk(notLister, func(string, vfs.File) error { return nil })
+	s.Error(err, "locations that can't list directories can't be walked")
+}
+
+func (s *utilsTest) TestEnvReader() {
+	defer func() {
+		for _, name := range []string{"STRING", "STRINGS", "BOOL", "INT", "DURATION"} {
+			_ = os.Unsetenv("VFS_TEST_" + name)
+		}
+	}()
+	s.NoError(os.Setenv("VFS_TEST_STRING", "value"))
+	s.NoError(os.Setenv("VFS_TEST_STRINGS", " a,,b "))
+	s.NoError(os.Setenv("VFS_TEST_BOOL", "1"))
+	s.NoError(os.Setenv("VFS_TEST_DURATION", "1m"))
+
+	opts := struct {
+		String   string
+		Strings  []string
+		Bool     bool
+		Int      int
+		Duration time.Duration
+		Unset    string
+	}{Int: 5, Unset: "default"}
+	env := &utils.EnvReader{Prefix: "VFS_TEST_"}
+	env.String("STRING", &opts.String)
+	env.Strings("STRINGS", &opts.Strings)
+	env.Bool("BOOL", &opts.Bool)
+	env.Int("INT", &opts.Int)
+	env.Duration("DURATION", &opts.Duration)
+	env.String("UNSET", &opts.Unset)
+	s.NoError(env.Err())
+	s.Equal("value", opts.String)
+	s.Equal([]string{"a", "b"}, opts.Strings)
+	s.True(opts.Bool)
+	s.Equal(5, opts.Int, "unset variables leave fields as they are")
+	s.Equal(time.Minute, opts.Duration)
+	s.Equal("default", opts.Unset)
+
+	s.NoError(os.Setenv("VFS_TEST_INT", "five"))
+	s.NoError(os.Setenv("VFS_TEST_STRING", "other"))
+	env = &utils.EnvReader{Prefix: "VFS_TEST_"}
+	env.Int("INT", &opts.Int)
+	env.String("STRING", &opts.String)
+	s.Error(env.Err())
+	s.Equal(5, opts.Int)
+	s.Equal("value", opts.String, "no fields are set after an error")
+}
+
 func TestUtils(t *testing.T) {
 	suite.Run(t, new(utilsTest))
 }
@@ -2,14 +2,42 @@
 vfscp copies a file from one place to another, even between supported remote systems.
 Complete URI (scheme:// authority/path) required except for local filesystem.
 See github.com/c2fo/vfs docs for authentication.
+Remotes named in the vfssimple config file ($VFS_CONFIG or ~/.vfs/config.json) can be used as <name>:<path>.
 
 
 Usage
 
 vfscp's usage is extremely simple:
 
-  vfscp <uri> <uri>
-  -help   prints help message
+  vfscp [-r [-parallel <n>] [-include <glob>]... [-exclude <glob>]...] <uri|-> <uri|->
+  -                 as the source reads stdin, as the target writes stdout (not with -r)
+  -r                copies a location, and every location below it, recursively
+  -parallel <n>     with -r, copies n files at once (default 1)
+  -include <glob>   with -r, only copies files matching the glob (repeatable)
+  -exclude <glob>   with -r, doesn't copy files matching the glob (repeatable)
+  -help             prints help message
+
+Globs without a "/" match file names, ie: '*.csv'. Globs with a "/" match the path relative to the source location,
+ie: 'logs/*.gz'.
+
+A "-" source or target is streamed, without buffering the whole file, where the backend allows it: os, s3 and gs.
+When writing to stdout, messages go to stderr.
+
+Recursive copies show live progress (files done, bytes/sec and ETA) when stdout is a terminal, and log a line per file
+otherwise. If any file fails to copy, every failure is listed and vfscp exits with status 1.
+
+Sync
+
+vfscp sync copies only the files that are missing or changed from one location to another, comparing size and
+modification time, or MD5 with -checksum:
+
+  vfscp sync [-delete] [-dry-run] [-checksum] [-parallel <n>] [-include <glob>]... [-exclude <glob>]... <uri> <uri>
+  -delete           deletes files in the target location that aren't in the source location
+  -dry-run          reports the planned actions without taking them
+  -checksum         compares files of the same size by MD5, when both backends report one
+  -parallel <n>     takes n actions at once (default 1)
+  -include <glob>   only syncs files matching the glob (repeatable)
+  -exclude <glob>   doesn't sync files matching the glob (repeatable)
 
 Examples
 
@@ -19,5 +47,16 @@ But may also be use the full scheme uri:
   vfscp file:///some/local/file.txt s3://mybucket/path/to/myfile.txt
 Copy a file from Google Cloud Storage to Amazon S3
   vfscp gs://googlebucket/some/path/photo.jpg s3://awsS3bucket/path/to/photo.jpg
+Upload a tarball from stdin, and stream an object to stdout
+  tar cz some/dir | vfscp - s3://mybucket/path/to/dir.tar.gz
+  vfscp s3://mybucket/path/to/big.csv - | head
+Copy a file from a remote named in the config file
+  vfscp prod-archive:/2024/x.csv ./x.csv
+Copy a local directory tree to an S3 prefix, leaving out temporary files
+  vfscp -r -parallel 8 -exclude '*.tmp' /some/local/dir/ s3://mybucket/path/to/dir/
+Mirror a local directory tree to an S3 prefix, deleting files that were removed locally
+  vfscp sync -delete /some/local/dir/ s3://mybucket/path/to/dir/
+
+Recursive copies and syncs need backends that can list directories: os, s3, gs and webhdfs.
 */
 package main
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// progressInterval is how often a live progress display is redrawn.
+const progressInterval = 250 * time.Millisecond
+
+// progress reports how far a set of transfers has got. When out is a terminal a single status line is redrawn in place,
+// otherwise a plain line is logged as each transfer finishes.
+type progress struct {
+	out  io.Writer
+	live bool
+
+	mu         sync.Mutex
+	start      time.Time
+	totalFiles int
+	totalBytes uint64
+	files      int
+	bytes      uint64
+	failed     int
+	width      int
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// newProgress returns a progress for totalFiles transfers of totalBytes, displayed live when stdout is a terminal.
+func newProgress(totalFiles int, totalBytes uint64) *progress {
+	fd := os.Stdout.Fd()
+	return &progress{
+		out:        os.Stdout,
+		live:       isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd),
+		totalFiles: totalFiles,
+		totalBytes: totalBytes,
+	}
+}
+
+// begin starts the clock and, for a live display, redrawing the status line.
+func (p *progress) begin() {
+	p.start = time.Now()
+	if !p.live {
+		return
+	}
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+	go func() {
+		defer close(p.stopped)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.mu.Lock()
+				p.redraw()
+				p.mu.Unlock()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// copied records n more bytes copied by a transfer that hasn't finished.
+func (p *progress) copied(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bytes += uint64(n)
+}
+
+// finished records the end of a transfer of size bytes from src to target, which failed if err isn't nil. copied is
+// the bytes already recorded for it: the rest of size is added when it succeeded, and they're taken back when it
+// failed.
+func (p *progress) finished(src, target string, size, copied uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.files++
+	if err != nil {
+		p.failed++
+		p.bytes -= copied
+	} else if size > copied {
+		p.bytes += size - copied
+	}
+	if p.live {
+		return
+	}
+
+	counter := fmt.Sprintf("[%d/%d]", p.files, p.totalFiles)
+	if err != nil {
+		red := color.New(color.FgHiRed).Add(color.Bold)
+		fmt.Fprintf(p.out, "%s %s %s to %s: %s\n", counter, red.Sprint("failed"), src, target, err)
+		return
+	}
+	fmt.Fprintf(p.out, "%s copied %s to %s (%s)\n", counter, src, target, formatBytes(size))
+}
+
+// end stops a live display, leaving its final state on screen.
+func (p *progress) end() {
+	if !p.live {
+		return
+	}
+	close(p.stop)
+	<-p.stopped
+	p.mu.Lock()
+	p.redraw()
+	p.mu.Unlock()
+	fmt.Fprintln(p.out)
+}
+
+// redraw rewrites the status line in place. mu must be held.
+func (p *progress) redraw() {
+	elapsed := time.Since(p.start)
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(p.bytes) / elapsed.Seconds()
+	}
+	eta := "--"
+	if rate > 0 && p.files < p.totalFiles {
+		// files may have grown since their sizes were totalled
+		var remainingBytes uint64
+		if p.bytes < p.totalBytes {
+			remainingBytes = p.totalBytes - p.bytes
+		}
+		remaining := time.Duration(float64(remainingBytes) / rate * float64(time.Second))
+		eta = remaining.Round(time.Second).String()
+	}
+
+	line := fmt.Sprintf("%d/%d files, %s/%s, %s/s, ETA %s",
+		p.files, p.totalFiles, formatBytes(p.bytes), formatBytes(p.totalBytes), formatBytes(uint64(rate)), eta)
+	if p.failed > 0 {
+		line += fmt.Sprintf(", %d failed", p.failed)
+	}
+	// pad with spaces to clear what's left of a longer previous line
+	padding := ""
+	if len(line) < p.width {
+		padding = strings.Repeat(" ", p.width-len(line))
+	}
+	p.width = len(line)
+	fmt.Fprint(p.out, "\r"+line+padding)
+}
+
+// progressReader reads from a file being copied, recording the bytes read in progress as they're read.
+type progressReader struct {
+	reader   io.Reader
+	progress *progress
+	count    uint64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += uint64(n)
+	r.progress.copied(n)
+	return n, err
+}
+
+// formatBytes returns n in human readable units, ie: 1.5 MB.
+func formatBytes(n uint64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
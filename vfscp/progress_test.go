@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/backend/readonly"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type progressTestSuite struct {
+	suite.Suite
+	tmpDir  string
+	noColor bool
+}
+
+func (s *progressTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfscp-progress-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.noColor = color.NoColor
+	color.NoColor = true
+}
+
+func (s *progressTestSuite) TearDownTest() {
+	color.NoColor = s.noColor
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *progressTestSuite) TestLines() {
+	out := &bytes.Buffer{}
+	p := &progress{out: out, totalFiles: 2, totalBytes: 10}
+	p.begin()
+	p.copied(2)
+	p.finished("file:///src/a.txt", "file:///target/a.txt", 4, 2, nil)
+	p.copied(3)
+	p.finished("file:///src/b.txt", "file:///target/b.txt", 6, 3, errors.New("denied"))
+	p.end()
+
+	s.Equal("[1/2] copied file:///src/a.txt to file:///target/a.txt (4 B)\n"+
+		"[2/2] failed file:///src/b.txt to file:///target/b.txt: denied\n", out.String())
+	s.Equal(uint64(4), p.bytes, "bytes of a failed copy are taken back")
+	s.Equal(1, p.failed)
+}
+
+func (s *progressTestSuite) TestRedrawRemainingClamped() {
+	out := &bytes.Buffer{}
+	p := &progress{out: out, live: true, totalFiles: 2, totalBytes: 5, bytes: 10}
+	p.start = time.Now().Add(-time.Second)
+	p.redraw()
+	s.Contains(out.String(), "ETA 0s", "files that grew since they were totalled leave nothing remaining")
+}
+
+func (s *progressTestSuite) TestCopyRecordsBytesAsRead() {
+	content := strings.Repeat("x", 100)
+	s.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "src.txt"), []byte(content), 0644))
+	osfs := &_os.FileSystem{}
+	// a readonly file's scheme differs from the target's, so it's streamed
+	src, err := readonly.NewFileSystem(osfs).NewFile("", filepath.Join(s.tmpDir, "src.txt"))
+	s.NoError(err)
+	target, err := osfs.NewFile("", filepath.Join(s.tmpDir, "target.txt"))
+	s.NoError(err)
+
+	p := &progress{out: ioutil.Discard, totalFiles: 1, totalBytes: 100}
+	t := &transfer{src: src, target: target, size: 100}
+	copied, err := t.copy(p)
+	s.NoError(err)
+	s.Equal(uint64(100), copied)
+	s.Equal(uint64(100), p.bytes, "bytes are recorded before the copy finishes")
+	p.finished(src.URI(), target.URI(), t.size, copied, nil)
+	s.Equal(uint64(100), p.bytes, "recorded bytes aren't added twice")
+
+	written, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "target.txt"))
+	s.NoError(err)
+	s.Equal(content, string(written))
+}
+
+func (s *progressTestSuite) TestSummarize() {
+	osfs := &_os.FileSystem{}
+	a, err := osfs.NewFile("", "/src/a.txt")
+	s.NoError(err)
+	b, err := osfs.NewFile("", "/src/b.txt")
+	s.NoError(err)
+	targetA, err := osfs.NewFile("", "/target/a.txt")
+	s.NoError(err)
+	targetB, err := osfs.NewFile("", "/target/b.txt")
+	s.NoError(err)
+
+	out := &bytes.Buffer{}
+	s.True(summarize(out, []*transfer{{src: a, target: targetA, size: 4}}))
+	s.Equal("\nCopied 1 files (4 bytes)\n\n", out.String())
+
+	out.Reset()
+	s.False(summarize(out, []*transfer{
+		{src: a, target: targetA, size: 4},
+		{src: b, target: targetB, size: 6, err: errors.New("denied")},
+	}))
+	s.Equal("\nCopied 1 files (4 bytes)\n\n"+
+		"Failed to copy 1 files:\n"+
+		"  file:///src/b.txt to file:///target/b.txt: denied\n\n", out.String())
+}
+
+func TestProgress(t *testing.T) {
+	suite.Run(t, new(progressTestSuite))
+}
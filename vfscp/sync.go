@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+	"github.com/c2fo/vfs/v3/vfssimple"
+	"github.com/c2fo/vfs/v3/vfssync"
+)
+
+const syncUsageTemplate = `
+%[1]s sync copies only the files that are missing or changed from one location to another, even between supported
+remote systems, optionally deleting files from the target that aren't in the source.
+
+Usage:  %[1]s sync [-delete] [-dry-run] [-checksum] [-parallel <n>] [-include <glob>]... [-exclude <glob>]... <uri> <uri>
+
+    ie,        %[1]s sync -delete /some/local/dir/ s3://mybucket/path/to/dir/
+
+    -delete
+        deletes files in the target location that aren't in the source location
+    -dry-run
+        reports the planned actions without taking them
+    -checksum
+        compares files of the same size by MD5, when both backends report one, instead of modification time
+    -parallel <n>
+        takes n actions at once. Defaults to 1.
+    -include <glob>
+        only syncs files matching the glob. May be repeated.
+    -exclude <glob>
+        doesn't sync files matching the glob. May be repeated.
+    -help
+        prints this message
+
+`
+
+// syncCommand runs the sync subcommand with args, the arguments following "sync".
+func syncCommand(args []string) {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stdout, syncUsageTemplate, os.Args[0])
+	}
+	var help bool
+	var includes, excludes globList
+	opts := vfssync.Options{}
+	flags.BoolVar(&help, "help", false, "prints this message")
+	flags.BoolVar(&opts.Delete, "delete", false, "deletes files in the target location that aren't in the source")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "reports the planned actions without taking them")
+	flags.BoolVar(&opts.Checksum, "checksum", false, "compares files of the same size by MD5")
+	flags.IntVar(&opts.Parallel, "parallel", 1, "the number of actions to take at once")
+	flags.Var(&includes, "include", "only syncs files matching the glob")
+	flags.Var(&excludes, "exclude", "doesn't sync files matching the glob")
+	// errors exit, with ExitOnError
+	_ = flags.Parse(args)
+
+	if help {
+		flags.Usage()
+		os.Exit(0)
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	srcURI, err := vfssimple.NormalizeURI(flags.Arg(0))
+	if err != nil {
+		failMessage(err)
+	}
+	targetURI, err := vfssimple.NormalizeURI(flags.Arg(1))
+	if err != nil {
+		failMessage(err)
+	}
+	src, err := vfssimple.NewLocation(utils.EnsureTrailingSlash(srcURI))
+	if err != nil {
+		failMessage(err)
+	}
+	target, err := vfssimple.NewLocation(utils.EnsureTrailingSlash(targetURI))
+	if err != nil {
+		failMessage(err)
+	}
+
+	f := &filter{includes: includes, excludes: excludes}
+	opts.Filter = f.match
+	syncLocations(src, target, opts)
+}
+
+// syncLocations syncs target with src, reporting each action and a summary. If any action fails, the failures are
+// listed and vfscp exits with a non-zero status.
+func syncLocations(src, target vfs.Location, opts vfssync.Options) {
+	green := color.New(color.FgHiGreen).Add(color.Bold)
+	white := color.New(color.FgHiWhite).Add(color.Bold)
+	red := color.New(color.FgHiRed).Add(color.Bold)
+
+	verb := "Syncing "
+	if opts.DryRun {
+		verb = "Planning sync of "
+	}
+	blue := color.New(color.FgHiBlue).Add(color.Bold)
+	fmt.Print(white.Sprint(verb) + blue.Sprint(src) + white.Sprint(" to ") + blue.Sprint(target) + white.Sprint(" ...\n"))
+
+	opts.OnAction = func(action vfssync.Action) {
+		if action.Err != nil {
+			fmt.Printf("%s %s: %s\n", red.Sprint("failed"), action, action.Err)
+			return
+		}
+		fmt.Println(action)
+	}
+	actions, err := vfssync.Sync(src, target, opts)
+	if err != nil {
+		failMessage(err)
+	}
+
+	counts := make(map[vfssync.ActionType]int)
+	var bytes uint64
+	var failed []vfssync.Action
+	for _, action := range actions {
+		if action.Err != nil {
+			failed = append(failed, action)
+			continue
+		}
+		counts[action.Type]++
+		if action.Type != vfssync.Delete {
+			bytes += action.Size
+		}
+	}
+
+	summary := fmt.Sprintf("\n%d copied, %d updated, %d deleted (%s)", counts[vfssync.Copy], counts[vfssync.Update],
+		counts[vfssync.Delete], formatBytes(bytes))
+	if opts.DryRun {
+		summary += " planned"
+	}
+	fmt.Print(white.Sprint(summary + "\n\n"))
+	if len(failed) > 0 {
+		fmt.Print(red.Sprintf("%d actions failed:\n", len(failed)))
+		for _, action := range failed {
+			fmt.Printf("  %s: %s\n", action, action.Err)
+		}
+		fmt.Println()
+		os.Exit(1)
+	}
+	fmt.Print(green.Sprint("done\n\n"))
+}
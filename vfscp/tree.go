@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+	"github.com/c2fo/vfs/v3/vfssimple"
+)
+
+// globList is a flag.Value collecting every use of a repeatable glob flag.
+type globList []string
+
+func (g *globList) String() string {
+	return strings.Join(*g, ",")
+}
+
+func (g *globList) Set(glob string) error {
+	if _, err := path.Match(glob, ""); err != nil {
+		return fmt.Errorf("bad glob %q: %s", glob, err)
+	}
+	*g = append(*g, glob)
+	return nil
+}
+
+// filter decides which files of a recursive copy are copied.
+type filter struct {
+	includes globList
+	excludes globList
+}
+
+// match returns whether the file at relativePath should be copied: it must match an include glob, if there are any,
+// and no exclude glob.
+func (f *filter) match(relativePath string) bool {
+	if len(f.includes) > 0 && !matchAny(f.includes, relativePath) {
+		return false
+	}
+	return !matchAny(f.excludes, relativePath)
+}
+
+// matchAny returns whether any glob matches relativePath. Globs without a "/" are matched against the file name only.
+func matchAny(globs globList, relativePath string) bool {
+	for _, glob := range globs {
+		name := relativePath
+		if !strings.Contains(glob, "/") {
+			name = path.Base(relativePath)
+		}
+		// globs are validated by Set
+		if matched, _ := path.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// transfer is a copy of one file of a recursive copy.
+type transfer struct {
+	src    vfs.File
+	target vfs.File
+	size   uint64
+	err    error
+}
+
+// copyTree copies every file below the source location to the same relative path below the target location, with
+// parallel copies at once. Each failed copy is reported, then vfscp exits with a non-zero status.
+func copyTree(srcLocationURI, targetLocationURI string, f *filter, parallel int) {
+	green := color.New(color.FgHiGreen).Add(color.Bold)
+
+	srcLocation, err := vfssimple.NewLocation(utils.EnsureTrailingSlash(srcLocationURI))
+	if err != nil {
+		failMessage(err)
+	}
+	targetLocation, err := vfssimple.NewLocation(utils.EnsureTrailingSlash(targetLocationURI))
+	if err != nil {
+		failMessage(err)
+	}
+
+	copyMessage(srcLocation.URI(), targetLocation.URI())
+	transfers, totalBytes, err := planTransfers(srcLocation, targetLocation, f)
+	if err != nil {
+		failMessage(err)
+	}
+	fmt.Printf("%d files (%s)\n", len(transfers), formatBytes(totalBytes))
+
+	p := newProgress(len(transfers), totalBytes)
+	p.begin()
+	runTransfers(transfers, parallel, p)
+	p.end()
+
+	if !summarize(os.Stdout, transfers) {
+		os.Exit(1)
+	}
+	fmt.Print(green.Sprint("done\n\n"))
+}
+
+// summarize writes the files and bytes copied by transfers to out, then lists any that failed, returning whether
+// they all succeeded.
+func summarize(out io.Writer, transfers []*transfer) bool {
+	white := color.New(color.FgHiWhite).Add(color.Bold)
+	red := color.New(color.FgHiRed).Add(color.Bold)
+
+	var files int
+	var bytes uint64
+	var failed []*transfer
+	for _, t := range transfers {
+		if t.err != nil {
+			failed = append(failed, t)
+			continue
+		}
+		files++
+		bytes += t.size
+	}
+
+	fmt.Fprint(out, white.Sprintf("\nCopied %d files (%d bytes)\n\n", files, bytes))
+	if len(failed) == 0 {
+		return true
+	}
+	fmt.Fprint(out, red.Sprintf("Failed to copy %d files:\n", len(failed)))
+	for _, t := range failed {
+		fmt.Fprintf(out, "  %s to %s: %s\n", t.src, t.target, t.err)
+	}
+	fmt.Fprintln(out)
+	return false
+}
+
+// planTransfers returns a transfer for every file below srcLocation that f matches, to the same relative path below
+// targetLocation, and their total size.
+func planTransfers(srcLocation, targetLocation vfs.Location, f *filter) ([]*transfer, uint64, error) {
+	var transfers []*transfer
+	var totalBytes uint64
+	err := utils.Walk(srcLocation, func(relativePath string, srcFile vfs.File) error {
+		if !f.match(relativePath) {
+			return nil
+		}
+		targetFile, err := targetLocation.NewFile(relativePath)
+		if err != nil {
+			return err
+		}
+		size, err := srcFile.Size()
+		if err != nil {
+			return err
+		}
+		transfers = append(transfers, &transfer{src: srcFile, target: targetFile, size: size})
+		totalBytes += size
+		return nil
+	})
+	return transfers, totalBytes, err
+}
+
+// copy copies the transfer's file, returning the bytes recorded in p as they were copied. A file is copied by its
+// backend when the target has the same scheme, so it may copy natively, and its bytes are left for p.finished to
+// record. Otherwise it's streamed, recording the bytes as they're read.
+func (t *transfer) copy(p *progress) (uint64, error) {
+	if t.src.Location().FileSystem().Scheme() == t.target.Location().FileSystem().Scheme() {
+		return 0, t.src.CopyToFile(t.target)
+	}
+
+	reader := &progressReader{reader: t.src, progress: p}
+	_, err := utils.TouchCopyReader(t.target, reader)
+	if cerr := t.target.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := t.src.Close(); err == nil {
+		err = cerr
+	}
+	return reader.count, err
+}
+
+// runTransfers copies every transfer using parallel workers, recording each copy's error in its transfer.
+func runTransfers(transfers []*transfer, parallel int, p *progress) {
+	if parallel < 1 {
+		parallel = 1
+	}
+	jobs := make(chan *transfer)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				var copied uint64
+				copied, t.err = t.copy(p)
+				p.finished(t.src.URI(), t.target.URI(), t.size, copied, t.err)
+			}
+		}()
+	}
+	for _, t := range transfers {
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+}
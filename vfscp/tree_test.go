@@ -0,0 +1,152 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/backend/readonly"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type treeTestSuite struct {
+	suite.Suite
+	tmpDir string
+	src    vfs.Location
+	target vfs.Location
+}
+
+func (s *treeTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfscp-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	for _, name := range []string{"a.csv", "b.tmp", "logs/c.gz", "logs/d.csv", "logs/old/e.gz"} {
+		p := filepath.Join(dir, "src", name)
+		s.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		s.NoError(ioutil.WriteFile(p, []byte(name), 0644))
+	}
+
+	osfs := &_os.FileSystem{}
+	s.src, err = osfs.NewLocation("", filepath.Join(dir, "src")+"/")
+	s.NoError(err)
+	s.target, err = osfs.NewLocation("", filepath.Join(dir, "target")+"/")
+	s.NoError(err)
+}
+
+func (s *treeTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *treeTestSuite) TestFilterMatch() {
+	tests := []struct {
+		includes     globList
+		excludes     globList
+		relativePath string
+		match        bool
+	}{
+		{relativePath: "a.csv", match: true},
+		{relativePath: "logs/old/e.gz", match: true},
+		{includes: globList{"*.csv"}, relativePath: "a.csv", match: true},
+		{includes: globList{"*.csv"}, relativePath: "logs/d.csv", match: true},
+		{includes: globList{"*.csv"}, relativePath: "logs/c.gz", match: false},
+		{includes: globList{"*.csv", "*.gz"}, relativePath: "logs/c.gz", match: true},
+		{excludes: globList{"*.tmp"}, relativePath: "b.tmp", match: false},
+		{excludes: globList{"*.tmp"}, relativePath: "a.csv", match: true},
+		{includes: globList{"logs/*.gz"}, relativePath: "logs/c.gz", match: true},
+		{includes: globList{"logs/*.gz"}, relativePath: "logs/old/e.gz", match: false},
+		{includes: globList{"logs/*.gz"}, relativePath: "c.gz", match: false},
+		{includes: globList{"*.gz"}, excludes: globList{"old/*"}, relativePath: "logs/old/e.gz", match: true},
+		{includes: globList{"*.gz"}, excludes: globList{"logs/old/*"}, relativePath: "logs/old/e.gz", match: false},
+	}
+	for _, test := range tests {
+		f := &filter{includes: test.includes, excludes: test.excludes}
+		s.Equal(test.match, f.match(test.relativePath), "includes %v, excludes %v, path %s",
+			test.includes, test.excludes, test.relativePath)
+	}
+}
+
+func (s *treeTestSuite) TestGlobList() {
+	var globs globList
+	s.NoError(globs.Set("*.csv"))
+	s.NoError(globs.Set("logs/*"))
+	s.Equal("*.csv,logs/*", globs.String())
+	s.Error(globs.Set("[a-"), "bad globs are refused")
+}
+
+func (s *treeTestSuite) TestCopyTree() {
+	tests := []struct {
+		filter *filter
+		copied []string
+	}{
+		{
+			filter: &filter{},
+			copied: []string{"a.csv", "b.tmp", "logs/c.gz", "logs/d.csv", "logs/old/e.gz"},
+		},
+		{
+			filter: &filter{excludes: globList{"*.tmp"}},
+			copied: []string{"a.csv", "logs/c.gz", "logs/d.csv", "logs/old/e.gz"},
+		},
+		{
+			filter: &filter{includes: globList{"logs/*"}},
+			copied: []string{"logs/c.gz", "logs/d.csv"},
+		},
+	}
+	for _, test := range tests {
+		s.NoError(os.RemoveAll(filepath.Join(s.tmpDir, "target")))
+		transfers, totalBytes, err := planTransfers(s.src, s.target, test.filter)
+		s.NoError(err)
+		s.Len(transfers, len(test.copied))
+
+		var expectedBytes uint64
+		for _, name := range test.copied {
+			expectedBytes += uint64(len(name))
+		}
+		s.Equal(expectedBytes, totalBytes)
+
+		runTransfers(transfers, 2, &progress{out: ioutil.Discard, totalFiles: len(transfers)})
+		for _, t := range transfers {
+			s.NoError(t.err)
+		}
+		s.Equal(test.copied, s.targetFiles(), "relative paths are kept")
+		for _, name := range test.copied {
+			content, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "target", name))
+			s.NoError(err)
+			s.Equal(name, string(content))
+		}
+	}
+}
+
+func (s *treeTestSuite) TestCopyTreeFromWrapper() {
+	src, err := readonly.NewFileSystem(s.src.FileSystem()).NewLocation("", s.src.Path())
+	s.NoError(err)
+	transfers, _, err := planTransfers(src, s.target, &filter{})
+	s.NoError(err, "wrapper locations list directories")
+	s.Len(transfers, 5)
+}
+
+// targetFiles returns the paths of the files below the target directory, relative to it.
+func (s *treeTestSuite) targetFiles() []string {
+	root := filepath.Join(s.tmpDir, "target")
+	var files []string
+	s.NoError(filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relative, err := filepath.Rel(root, p)
+		files = append(files, filepath.ToSlash(relative))
+		return err
+	}))
+	return files
+}
+
+func TestTree(t *testing.T) {
+	suite.Run(t, new(treeTestSuite))
+}
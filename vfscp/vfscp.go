@@ -3,12 +3,13 @@ package main
 import (
 	"flag"
 	"fmt"
-	"net/url"
+	"io"
 	"os"
-	"path/filepath"
 
 	"github.com/fatih/color"
 
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
 	"github.com/c2fo/vfs/v3/vfssimple"
 )
 
@@ -16,24 +17,59 @@ const usageTemplate = `
 %[1]s copies a file from one place to another, even between supported remote systems.
 Complete URI (scheme://authority/path) required except for local filesystem.
 See github.com/c2fo/vfs docs for authentication.
+Remotes named in the vfssimple config file ($VFS_CONFIG or ~/.vfs/config.json) can be used as <name>:<path>.
 
-Usage:  %[1]s <uri> <uri>
+Usage:  %[1]s sync -help
+        %[1]s <uri|-> <uri|->
+        %[1]s [-r [-parallel <n>] [-include <glob>]... [-exclude <glob>]...] <uri> <uri>
 
     ie,        %[1]s /some/local/file.txt s3://mybucket/path/to/myfile.txt
     same as    %[1]s file:///some/local/file.txt s3://mybucket/path/to/myfile.txt
+    remote     %[1]s prod-archive:/2024/x.csv ./x.csv
     gcs to s3  %[1]s gs://googlebucket/some/path/photo.jpg s3://awsS3bucket/path/to/photo.jpg
-
+    directory  %[1]s -r -parallel 8 -exclude '*.tmp' /some/local/dir/ s3://mybucket/path/to/dir/
+    stdin      tar cz some/dir | %[1]s - s3://mybucket/path/to/dir.tar.gz
+    stdout     %[1]s s3://mybucket/path/to/big.csv - | head
+
+    -
+        as the source, reads from stdin; as the target, writes to stdout. Content is streamed rather than buffered
+        in full where the backend allows it (os, s3 and gs). Messages are written to stderr. Can't be used with -r.
+
+    -r
+        copies every file in the source location, and the locations below it, to the same relative path in the
+        target location. Progress is shown live on a terminal, otherwise as a line per file. If any file fails to
+        copy, the failures are listed and the exit status is 1.
+    -parallel <n>
+        with -r, copies n files at once. Defaults to 1.
+    -include <glob>
+        with -r, only copies files matching the glob. May be repeated.
+    -exclude <glob>
+        with -r, doesn't copy files matching the glob. May be repeated.
     -help
         prints this message
 
+    Globs without a "/" match file names, ie: '*.csv'. Globs with a "/" match the path relative to the source
+    location, ie: 'logs/*.gz'.
+
 `
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		syncCommand(os.Args[2:])
+		return
+	}
+
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stdout, usageTemplate, os.Args[0])
 	}
-	var help bool
+	var help, recursive bool
+	var parallel int
+	var includes, excludes globList
 	flag.BoolVar(&help, "help", false, "prints this message")
+	flag.BoolVar(&recursive, "r", false, "copies a location recursively")
+	flag.IntVar(&parallel, "parallel", 1, "with -r, the number of files to copy at once")
+	flag.Var(&includes, "include", "with -r, only copies files matching the glob")
+	flag.Var(&excludes, "exclude", "with -r, doesn't copy files matching the glob")
 	flag.Parse()
 
 	if help {
@@ -46,17 +82,30 @@ func main() {
 		os.Exit(1)
 	}
 
+	if flag.Arg(0) == "-" || flag.Arg(1) == "-" {
+		messages = os.Stderr
+		if recursive {
+			failMessage(fmt.Errorf("- can't be used with -r"))
+		}
+		streamFiles(flag.Arg(0), flag.Arg(1))
+		return
+	}
+
 	fmt.Println("")
 
-	srcFileURI, err := normalizeArgs(flag.Arg(0))
+	srcFileURI, err := vfssimple.NormalizeURI(flag.Arg(0))
 	if err != nil {
 		panic(err)
 	}
-	targetFileURI, err := normalizeArgs(flag.Arg(1))
+	targetFileURI, err := vfssimple.NormalizeURI(flag.Arg(1))
 	if err != nil {
 		panic(err)
 	}
 
+	if recursive {
+		copyTree(srcFileURI, targetFileURI, &filter{includes: includes, excludes: excludes}, parallel)
+		return
+	}
 	copyFiles(srcFileURI, targetFileURI)
 }
 
@@ -82,27 +131,57 @@ func copyFiles(srcFileURI, targetFileURI string) {
 
 }
 
-func normalizeArgs(str string) (string, error) {
-	var normalizedArg string
-	u, err := url.Parse(str)
-	if err != nil {
-		return "", err
+// streamFiles copies src to target, either of which may be "-" for stdin or stdout.
+func streamFiles(src, target string) {
+	var reader io.Reader = os.Stdin
+	var srcFile vfs.File
+	if src != "-" {
+		srcFileURI, err := vfssimple.NormalizeURI(src)
+		if err != nil {
+			failMessage(err)
+		}
+		srcFile, err = vfssimple.NewFile(srcFileURI)
+		if err != nil {
+			failMessage(err)
+		}
+		reader = srcFile
 	}
-	if u.IsAbs() {
-		normalizedArg = str
+
+	if target == "-" {
+		// io.Copy uses the file's WriteTo, where the backend has one, to stream rather than read in small chunks
+		if _, err := io.Copy(os.Stdout, reader); err != nil {
+			failMessage(err)
+		}
 	} else {
-		absPath, err := filepath.Abs(str)
+		targetFileURI, err := vfssimple.NormalizeURI(target)
 		if err != nil {
-			return "", err
+			failMessage(err)
+		}
+		targetFile, err := vfssimple.NewFile(targetFileURI)
+		if err != nil {
+			failMessage(err)
+		}
+		if _, err := utils.TouchCopyReader(targetFile, reader); err != nil {
+			failMessage(err)
+		}
+		if err := targetFile.Close(); err != nil {
+			failMessage(err)
+		}
+	}
+
+	if srcFile != nil {
+		if err := srcFile.Close(); err != nil {
+			failMessage(err)
 		}
-		normalizedArg = "file://" + absPath
 	}
-	return normalizedArg, err
 }
 
+// messages is where status and failure messages are written. It's stderr when streaming to stdout.
+var messages io.Writer = os.Stdout
+
 func failMessage(err error) {
 	red := color.New(color.FgHiRed).Add(color.Bold)
-	fmt.Printf(red.Sprint("failed\n\n")+"\n%s\n\n", err.Error())
+	fmt.Fprintf(messages, red.Sprint("failed\n\n")+"\n%s\n\n", err.Error())
 	os.Exit(1)
 }
 
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type vfscpTestSuite struct {
+	suite.Suite
+	tmpDir string
+}
+
+func (s *vfscpTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfscp-stream-test")
+	s.NoError(err)
+	s.tmpDir = dir
+}
+
+func (s *vfscpTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *vfscpTestSuite) TestStreamFilesTruncatesTarget() {
+	src := filepath.Join(s.tmpDir, "src.txt")
+	target := filepath.Join(s.tmpDir, "target.txt")
+	s.NoError(ioutil.WriteFile(src, []byte("short"), 0644))
+	s.NoError(ioutil.WriteFile(target, []byte("a much longer existing file"), 0644))
+
+	streamFiles(src, target)
+
+	content, err := ioutil.ReadFile(target)
+	s.NoError(err)
+	s.Equal("short", string(content), "nothing of the existing target is left")
+}
+
+func TestVFSCP(t *testing.T) {
+	suite.Run(t, new(vfscpTestSuite))
+}
@@ -0,0 +1,175 @@
+package vfshttp
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+)
+
+// RangeReader is implemented by Files that can read part of their content without reading what comes before it, such
+// as s3 and gs files. ReadRange returns a reader of length bytes from offset, or of the rest of the content when
+// length is negative.
+type RangeReader interface {
+	ReadRange(offset, length int64) (io.ReadCloser, error)
+}
+
+// content is an io.ReadSeeker over a file of known size for http.ServeContent. Seeking only moves an offset; the file
+// is read from the offset when Read is next called, with ReadRange where the file supports it so that a range request
+// doesn't fetch the whole file, nor more than the requested range.
+type content struct {
+	file   vfs.File
+	size   int64
+	offset int64
+
+	// ranges are the byte ranges of the request, which bound what's read with ReadRange
+	ranges []byteRange
+
+	// reader reads from offset, or is nil when the offset has moved since it was opened
+	reader io.ReadCloser
+
+	// end is where the content read by reader ends
+	end int64
+}
+
+// byteRange is a range of content from start up to, but not including, end.
+type byteRange struct {
+	start int64
+	end   int64
+}
+
+func (c *content) Read(p []byte) (int, error) {
+	if c.offset >= c.size {
+		return 0, io.EOF
+	}
+	if c.reader == nil {
+		if err := c.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := c.reader.Read(p)
+	c.offset += int64(n)
+	if err == io.EOF && c.offset == c.end && c.end < c.size {
+		// the requested range has been read, but more is wanted, ie: when http.ServeContent ignored the ranges
+		err = c.Close()
+		if n == 0 && err == nil {
+			return c.Read(p)
+		}
+	}
+	return n, err
+}
+
+func (c *content) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += c.offset
+	case io.SeekEnd:
+		offset += c.size
+	}
+	if offset < 0 {
+		return 0, errors.New("vfshttp: negative position")
+	}
+	if offset != c.offset {
+		if err := c.Close(); err != nil {
+			return 0, err
+		}
+		c.offset = offset
+	}
+	return offset, nil
+}
+
+// Close closes the reader from the current offset, if any.
+func (c *content) Close() error {
+	if c.reader == nil {
+		return nil
+	}
+	err := c.reader.Close()
+	c.reader = nil
+	return err
+}
+
+func (c *content) open() error {
+	c.end = c.size
+	if rangeReader, ok := c.file.(RangeReader); ok {
+		length := int64(-1)
+		for _, r := range c.ranges {
+			if c.offset >= r.start && c.offset < r.end {
+				c.end = r.end
+				length = r.end - c.offset
+				break
+			}
+		}
+		reader, err := rangeReader.ReadRange(c.offset, length)
+		if err != nil {
+			return err
+		}
+		c.reader = reader
+		return nil
+	}
+	if _, err := c.file.Seek(c.offset, io.SeekStart); err != nil {
+		return err
+	}
+	c.reader = fileReader{c.file}
+	return nil
+}
+
+// parseRanges returns the byte ranges of a Range header for content of the given size. It returns nil for a header it
+// can't parse, which http.ServeContent then responds to.
+func parseRanges(header string, size int64) []byteRange {
+	if !strings.HasPrefix(header, "bytes=") {
+		return nil
+	}
+	var ranges []byteRange
+	for _, spec := range strings.Split(header[len("bytes="):], ",") {
+		spec = strings.TrimSpace(spec)
+		i := strings.Index(spec, "-")
+		if i < 0 {
+			return nil
+		}
+		first, last := strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+		if first == "" {
+			// the last bytes of the content, ie: -500
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil
+			}
+			if n > size {
+				n = size
+			}
+			ranges = append(ranges, byteRange{start: size - n, end: size})
+			continue
+		}
+		r := byteRange{end: size}
+		var err error
+		if r.start, err = strconv.ParseInt(first, 10, 64); err != nil || r.start < 0 {
+			return nil
+		}
+		if last != "" {
+			end, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || end < r.start {
+				return nil
+			}
+			if end+1 < size {
+				r.end = end + 1
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// fileReader reads a file that's been seeked to the content's offset. Closing it leaves the file open, since it may be
+// seeked again.
+type fileReader struct {
+	file vfs.File
+}
+
+func (r fileReader) Read(p []byte) (int, error) {
+	return r.file.Read(p)
+}
+
+func (r fileReader) Close() error {
+	return nil
+}
@@ -0,0 +1,131 @@
+package vfshttp
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3/mocks"
+)
+
+// rangeFile is a mock File that reads its content with ReadRange.
+type rangeFile struct {
+	*mocks.File
+	content string
+	offsets []int64
+	lengths []int64
+}
+
+func (f *rangeFile) ReadRange(offset, length int64) (io.ReadCloser, error) {
+	f.offsets = append(f.offsets, offset)
+	f.lengths = append(f.lengths, length)
+	end := int64(len(f.content))
+	if length >= 0 {
+		end = offset + length
+	}
+	return ioutil.NopCloser(strings.NewReader(f.content[offset:end])), nil
+}
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type contentTestSuite struct {
+	suite.Suite
+}
+
+func (s *contentTestSuite) TestReadRange() {
+	file := &rangeFile{File: new(mocks.File), content: "0123456789"}
+	c := &content{file: file, size: 10}
+
+	offset, err := c.Seek(-4, io.SeekEnd)
+	s.NoError(err)
+	s.Equal(int64(6), offset)
+	s.Empty(file.offsets, "seeking doesn't read the file")
+
+	read, err := ioutil.ReadAll(c)
+	s.NoError(err)
+	s.Equal("6789", string(read))
+
+	_, err = c.Seek(2, io.SeekStart)
+	s.NoError(err)
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(c, buf)
+	s.NoError(err)
+	s.Equal("234", string(buf))
+	s.Equal([]int64{6, 2}, file.offsets, "the file is read from each offset")
+	s.NoError(c.Close())
+
+	_, err = c.Seek(-1, io.SeekStart)
+	s.Error(err)
+}
+
+func (s *contentTestSuite) TestReadBoundedRange() {
+	file := &rangeFile{File: new(mocks.File), content: "0123456789"}
+	c := &content{file: file, size: 10, ranges: parseRanges("bytes=2-4,7-", 10)}
+
+	_, err := c.Seek(2, io.SeekStart)
+	s.NoError(err)
+	read, err := ioutil.ReadAll(io.LimitReader(c, 3))
+	s.NoError(err)
+	s.Equal("234", string(read))
+	_, err = c.Seek(7, io.SeekStart)
+	s.NoError(err)
+	read, err = ioutil.ReadAll(c)
+	s.NoError(err)
+	s.Equal("789", string(read))
+	s.Equal([]int64{2, 7}, file.offsets)
+	s.Equal([]int64{3, 3}, file.lengths, "only the requested range is read")
+
+	// content past the requested range, when the ranges were ignored, is read too
+	_, err = c.Seek(2, io.SeekStart)
+	s.NoError(err)
+	read, err = ioutil.ReadAll(c)
+	s.NoError(err)
+	s.Equal("23456789", string(read))
+	s.Equal([]int64{2, 7, 2, 5}, file.offsets)
+	s.Equal([]int64{3, 3, 3, -1}, file.lengths)
+	s.NoError(c.Close())
+}
+
+func (s *contentTestSuite) TestParseRanges() {
+	tests := []struct {
+		header   string
+		expected []byteRange
+	}{
+		{header: "", expected: nil},
+		{header: "bytes=2-5", expected: []byteRange{{start: 2, end: 6}}},
+		{header: "bytes=2-", expected: []byteRange{{start: 2, end: 10}}},
+		{header: "bytes=-3", expected: []byteRange{{start: 7, end: 10}}},
+		{header: "bytes=-30", expected: []byteRange{{start: 0, end: 10}}},
+		{header: "bytes=5-30", expected: []byteRange{{start: 5, end: 10}}},
+		{header: "bytes=0-0, 8-", expected: []byteRange{{start: 0, end: 1}, {start: 8, end: 10}}},
+		{header: "bytes=5-2", expected: nil},
+		{header: "bytes=a-b", expected: nil},
+		{header: "items=1-2", expected: nil},
+	}
+	for _, test := range tests {
+		s.Equal(test.expected, parseRanges(test.header, 10), test.header)
+	}
+}
+
+func (s *contentTestSuite) TestSeekFile() {
+	file := new(mocks.File)
+	file.On("Seek", int64(3), io.SeekStart).Return(int64(3), nil).Once()
+	file.On("Read", make([]byte, 512)).Return(2, io.EOF).Once()
+	c := &content{file: file, size: 5}
+
+	_, err := c.Seek(3, io.SeekStart)
+	s.NoError(err)
+	n, err := c.Read(make([]byte, 512))
+	s.Equal(2, n)
+	s.Equal(io.EOF, err)
+	file.AssertExpectations(s.T())
+}
+
+func TestContent(t *testing.T) {
+	suite.Run(t, new(contentTestSuite))
+}
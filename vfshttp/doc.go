@@ -0,0 +1,38 @@
+/*
+Package vfshttp serves the files below a vfs.Location over HTTP, on any backend, as an http.Handler.
+
+Usage
+
+  import(
+      "net/http"
+
+      "github.com/c2fo/vfs/v3/vfshttp"
+      "github.com/c2fo/vfs/v3/vfssimple"
+  )
+
+  func Serve() error {
+      location, err := vfssimple.NewLocation("s3://mybucket/public/")
+      if err != nil {
+          return err
+      }
+      return http.ListenAndServe(":8080", vfshttp.NewHandler(location))
+  }
+
+A request path is relative to the location, so GET /dir/data.csv serves s3://mybucket/public/dir/data.csv. Paths
+can't reach above the location: ".." elements stop at its root.
+
+Requests
+
+GET and HEAD requests for a file serve its content, with its Last-Modified header, and support Range and conditional
+(If-Modified-Since, If-Range, ...) requests through http.ServeContent. Files that implement RangeReader, as s3 and gs
+files do, read only the requested range rather than the whole file.
+
+GET and HEAD requests for a path ending with a slash return a listing of the location: HTML by default, or a Listing
+as JSON when the request accepts application/json or has a format=json query. Directories are only listed for
+locations that implement utils.DirLister, which os, s3, gs and webhdfs locations do. A request for a location without
+its trailing slash is redirected to it.
+
+PUT and DELETE requests write and delete files when Options.AllowWrites is set, and are refused with 405 Method Not
+Allowed otherwise. PUT responds 201 Created for a new file and 204 No Content when it replaces a file.
+*/
+package vfshttp
@@ -0,0 +1,224 @@
+package vfshttp
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// Options holds the options of a Handler.
+type Options struct {
+	// AllowWrites lets PUT requests write files and DELETE requests delete them. Without it, both are refused with 405
+	// Method Not Allowed.
+	AllowWrites bool
+}
+
+// Handler implements http.Handler, serving the files below a vfs.Location at their paths relative to it.
+type Handler struct {
+	location vfs.Location
+	options  Options
+}
+
+// NewHandler initializer returns a Handler serving the files below location.
+func NewHandler(location vfs.Location) *Handler {
+	return &Handler{location: location}
+}
+
+// WithOptions sets options for the Handler.
+func (h *Handler) WithOptions(opts Options) *Handler {
+	h.options = opts
+	return h
+}
+
+// ServeHTTP implements http.Handler. GET and HEAD serve a file, with support for Range requests and conditional
+// requests, or a listing of a location when the path ends with a slash. PUT writes the request body to a file and
+// DELETE deletes a file, when Options.AllowWrites is set.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := cleanPath(r.URL.Path)
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if strings.HasSuffix(p, "/") {
+			h.serveListing(w, r, p)
+			return
+		}
+		h.serveFile(w, r, p)
+	case http.MethodPut:
+		if h.allowWrites(w) {
+			h.putFile(w, r, p)
+		}
+	case http.MethodDelete:
+		if h.allowWrites(w) {
+			h.deleteFile(w, p)
+		}
+	default:
+		h.methodNotAllowed(w)
+	}
+}
+
+func (h *Handler) serveFile(w http.ResponseWriter, r *http.Request, p string) {
+	file, err := h.location.NewFile(relativePath(p))
+	if err != nil {
+		httpError(w, err, http.StatusBadRequest)
+		return
+	}
+	exists, err := fileExists(file)
+	if err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		isLocation, err := h.hasEntries(p + "/")
+		if err != nil {
+			httpError(w, err, http.StatusInternalServerError)
+			return
+		}
+		if isLocation {
+			redirect(w, r, escapeName(path.Base(p))+"/")
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	size, err := file.Size()
+	if err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+	modified, err := file.LastModified()
+	if err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+	c := &content{file: file, size: int64(size), ranges: parseRanges(r.Header.Get("Range"), int64(size))}
+	defer func() {
+		_ = c.Close()
+		_ = file.Close()
+	}()
+	http.ServeContent(w, r, file.Name(), *modified, c)
+}
+
+func (h *Handler) putFile(w http.ResponseWriter, r *http.Request, p string) {
+	if strings.HasSuffix(p, "/") {
+		httpError(w, fmt.Errorf("%s is a location, only files can be written", p), http.StatusMethodNotAllowed)
+		return
+	}
+	file, err := h.location.NewFile(relativePath(p))
+	if err != nil {
+		httpError(w, err, http.StatusBadRequest)
+		return
+	}
+	exists, err := fileExists(file)
+	if err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := utils.TouchCopyReader(file, r.Body); err != nil {
+		_ = file.Close()
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if err := file.Close(); err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	if exists {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) deleteFile(w http.ResponseWriter, p string) {
+	if strings.HasSuffix(p, "/") {
+		httpError(w, fmt.Errorf("%s is a location, only files can be deleted", p), http.StatusMethodNotAllowed)
+		return
+	}
+	file, err := h.location.NewFile(relativePath(p))
+	if err != nil {
+		httpError(w, err, http.StatusBadRequest)
+		return
+	}
+	exists, err := fileExists(file)
+	if err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		httpError(w, fmt.Errorf("%s does not exist", p), http.StatusNotFound)
+		return
+	}
+	if err := file.Delete(); err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// fileExists returns whether file exists. An os File exists for a directory too, which isn't a file to serve, write or
+// delete.
+func fileExists(file vfs.File) (bool, error) {
+	exists, err := file.Exists()
+	if err != nil || !exists || file.Location().FileSystem().Scheme() != _os.Scheme {
+		return exists, err
+	}
+	info, err := os.Stat(file.Path())
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// allowWrites returns whether writes are allowed, responding with 405 Method Not Allowed if they aren't.
+func (h *Handler) allowWrites(w http.ResponseWriter) bool {
+	if !h.options.AllowWrites {
+		h.methodNotAllowed(w)
+	}
+	return h.options.AllowWrites
+}
+
+func (h *Handler) methodNotAllowed(w http.ResponseWriter) {
+	allow := "GET, HEAD"
+	if h.options.AllowWrites {
+		allow += ", PUT, DELETE"
+	}
+	w.Header().Set("Allow", allow)
+	httpError(w, fmt.Errorf("method not allowed, only %s", allow), http.StatusMethodNotAllowed)
+}
+
+// cleanPath returns the request path p as an absolute path with ".." elements stopping at the root, keeping any
+// trailing slash.
+func cleanPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// relativePath returns the request path p relative to the Handler's location.
+func relativePath(p string) string {
+	return strings.TrimPrefix(p, "/")
+}
+
+// redirect redirects to target, relative to the request's path, keeping its query. The target is left relative, unlike
+// with http.Redirect, so that it's still right when the Handler is behind http.StripPrefix.
+func redirect(w http.ResponseWriter, r *http.Request, target string) {
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	w.Header().Set("Location", target)
+	w.WriteHeader(http.StatusMovedPermanently)
+}
+
+func httpError(w http.ResponseWriter, err error, status int) {
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,235 @@
+package vfshttp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type handlerTestSuite struct {
+	suite.Suite
+	tmpDir  string
+	handler *Handler
+}
+
+func (s *handlerTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfshttp-test")
+	s.NoError(err)
+	s.tmpDir = dir
+
+	s.NoError(os.MkdirAll(filepath.Join(dir, "dir", "sub"), 0700))
+	s.NoError(os.MkdirAll(filepath.Join(dir, "empty"), 0700))
+	for name, content := range map[string]string{
+		"file.txt":        "0123456789",
+		"dir/a b.txt":     "a",
+		"dir/<script>.js": "b",
+		"dir/sub/c.txt":   "c",
+	} {
+		s.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	location, err := (&_os.FileSystem{}).NewLocation("", utils.EnsureTrailingSlash(dir))
+	s.NoError(err)
+	s.handler = NewHandler(location)
+}
+
+func (s *handlerTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *handlerTestSuite) request(method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	s.handler.ServeHTTP(w, r)
+	return w
+}
+
+func (s *handlerTestSuite) TestGetFile() {
+	w := s.request(http.MethodGet, "/file.txt", "", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("0123456789", w.Body.String())
+	s.Equal("10", w.Header().Get("Content-Length"))
+	s.NotEmpty(w.Header().Get("Last-Modified"))
+
+	w = s.request(http.MethodGet, "/dir/a%20b.txt", "", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("a", w.Body.String())
+
+	w = s.request(http.MethodHead, "/file.txt", "", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("10", w.Header().Get("Content-Length"))
+	s.Empty(w.Body.String())
+
+	w = s.request(http.MethodGet, "/missing.txt", "", nil)
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *handlerTestSuite) TestGetRange() {
+	w := s.request(http.MethodGet, "/file.txt", "", map[string]string{"Range": "bytes=2-5"})
+	s.Equal(http.StatusPartialContent, w.Code)
+	s.Equal("2345", w.Body.String())
+	s.Equal("bytes 2-5/10", w.Header().Get("Content-Range"))
+
+	w = s.request(http.MethodGet, "/file.txt", "", map[string]string{"Range": "bytes=-3"})
+	s.Equal(http.StatusPartialContent, w.Code)
+	s.Equal("789", w.Body.String())
+
+	w = s.request(http.MethodGet, "/file.txt", "", map[string]string{"Range": "bytes=20-"})
+	s.Equal(http.StatusRequestedRangeNotSatisfiable, w.Code)
+}
+
+func (s *handlerTestSuite) TestGetConditional() {
+	w := s.request(http.MethodGet, "/file.txt", "", nil)
+	lastModified := w.Header().Get("Last-Modified")
+
+	w = s.request(http.MethodGet, "/file.txt", "", map[string]string{"If-Modified-Since": lastModified})
+	s.Equal(http.StatusNotModified, w.Code)
+}
+
+func (s *handlerTestSuite) TestRedirect() {
+	w := s.request(http.MethodGet, "/dir?format=json", "", nil)
+	s.Equal(http.StatusMovedPermanently, w.Code)
+	s.Equal("dir/?format=json", w.Header().Get("Location"))
+
+	w = s.request(http.MethodGet, "/dir/sub", "", nil)
+	s.Equal(http.StatusMovedPermanently, w.Code)
+	s.Equal("sub/", w.Header().Get("Location"))
+
+	w = s.request(http.MethodGet, "/empty", "", nil)
+	s.Equal(http.StatusNotFound, w.Code, "an empty directory isn't a file")
+}
+
+func (s *handlerTestSuite) TestJSONListing() {
+	w := s.request(http.MethodGet, "/dir/", "", map[string]string{"Accept": "application/json"})
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("application/json", w.Header().Get("Content-Type"))
+
+	var listing Listing
+	s.NoError(json.Unmarshal(w.Body.Bytes(), &listing))
+	s.Equal("/dir/", listing.Path)
+	s.Len(listing.Entries, 3)
+	s.Equal(Entry{Name: "sub", Type: EntryTypeDirectory}, listing.Entries[0])
+	s.Equal("<script>.js", listing.Entries[1].Name)
+	s.Equal("a b.txt", listing.Entries[2].Name)
+	s.Equal(EntryTypeFile, listing.Entries[2].Type)
+	s.Equal(uint64(1), listing.Entries[2].Size)
+	s.NotNil(listing.Entries[2].Modified)
+
+	w = s.request(http.MethodGet, "/empty/?format=json", "", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.JSONEq(`{"path":"/empty/","entries":[]}`, w.Body.String())
+
+	w = s.request(http.MethodGet, "/missing/?format=json", "", nil)
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *handlerTestSuite) TestHTMLListing() {
+	w := s.request(http.MethodGet, "/dir/", "", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	s.Contains(body, `<a href="../">../</a>`)
+	s.Contains(body, `<a href="sub/">sub/</a>`)
+	s.Contains(body, `<a href="a%20b.txt">a b.txt</a>`)
+	s.Contains(body, `&lt;script&gt;.js</a>`, "names are escaped")
+	s.NotContains(body, "<script>")
+
+	w = s.request(http.MethodGet, "/", "", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.NotContains(w.Body.String(), `href="../"`, "the root has no parent")
+	s.Contains(w.Body.String(), `<a href="file.txt">file.txt</a>`)
+}
+
+func (s *handlerTestSuite) TestPathTraversal() {
+	outside := filepath.Join(filepath.Dir(s.tmpDir), filepath.Base(s.tmpDir)+"-outside.txt")
+	s.NoError(ioutil.WriteFile(outside, []byte("secret"), 0600))
+	defer func() { s.NoError(os.Remove(outside)) }()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.URL.Path = "/../" + filepath.Base(outside)
+	w := httptest.NewRecorder()
+	s.handler.ServeHTTP(w, r)
+	s.Equal(http.StatusNotFound, w.Code)
+	s.NotContains(w.Body.String(), "secret")
+}
+
+func (s *handlerTestSuite) TestWritesNotAllowed() {
+	w := s.request(http.MethodPut, "/new.txt", "content", nil)
+	s.Equal(http.StatusMethodNotAllowed, w.Code)
+	s.Equal("GET, HEAD", w.Header().Get("Allow"))
+	_, err := os.Stat(filepath.Join(s.tmpDir, "new.txt"))
+	s.True(os.IsNotExist(err))
+
+	w = s.request(http.MethodDelete, "/file.txt", "", nil)
+	s.Equal(http.StatusMethodNotAllowed, w.Code)
+	s.FileExists(filepath.Join(s.tmpDir, "file.txt"))
+
+	w = s.request(http.MethodPost, "/file.txt", "", nil)
+	s.Equal(http.StatusMethodNotAllowed, w.Code)
+}
+
+func (s *handlerTestSuite) TestPut() {
+	s.handler.WithOptions(Options{AllowWrites: true})
+
+	w := s.request(http.MethodPut, "/new/dir/new.txt", "content", nil)
+	s.Equal(http.StatusCreated, w.Code)
+	content, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "new", "dir", "new.txt"))
+	s.NoError(err)
+	s.Equal("content", string(content))
+
+	w = s.request(http.MethodPut, "/file.txt", "short", nil)
+	s.Equal(http.StatusNoContent, w.Code)
+	content, err = ioutil.ReadFile(filepath.Join(s.tmpDir, "file.txt"))
+	s.NoError(err)
+	s.Equal("short", string(content), "the old content is replaced, not overwritten")
+
+	w = s.request(http.MethodPut, "/empty.txt", "", nil)
+	s.Equal(http.StatusCreated, w.Code)
+	s.FileExists(filepath.Join(s.tmpDir, "empty.txt"))
+
+	w = s.request(http.MethodPut, "/dir/", "content", nil)
+	s.Equal(http.StatusMethodNotAllowed, w.Code)
+}
+
+func (s *handlerTestSuite) TestDelete() {
+	s.handler.WithOptions(Options{AllowWrites: true})
+
+	w := s.request(http.MethodDelete, "/file.txt", "", nil)
+	s.Equal(http.StatusNoContent, w.Code)
+	_, err := os.Stat(filepath.Join(s.tmpDir, "file.txt"))
+	s.True(os.IsNotExist(err))
+
+	w = s.request(http.MethodDelete, "/file.txt", "", nil)
+	s.Equal(http.StatusNotFound, w.Code)
+
+	w = s.request(http.MethodDelete, "/dir", "", nil)
+	s.Equal(http.StatusNotFound, w.Code, "a directory isn't a file")
+	_, err = os.Stat(filepath.Join(s.tmpDir, "dir", "sub", "c.txt"))
+	s.NoError(err)
+
+	w = s.request(http.MethodPost, "/file.txt", "", nil)
+	s.Equal(http.StatusMethodNotAllowed, w.Code)
+	s.Equal("GET, HEAD, PUT, DELETE", w.Header().Get("Allow"))
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(handlerTestSuite))
+}
@@ -0,0 +1,189 @@
+package vfshttp
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+const (
+	// EntryTypeFile is the type of a file in a listing.
+	EntryTypeFile = "file"
+
+	// EntryTypeDirectory is the type of a directory in a listing.
+	EntryTypeDirectory = "directory"
+)
+
+// Listing is the JSON form of a location's listing, returned for requests that accept application/json or have a
+// format=json query.
+type Listing struct {
+	// Path is the request path of the location, ending with a slash.
+	Path string `json:"path"`
+
+	// Entries are the directories, then the files, directly inside the location, each sorted by name.
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is a file or directory in a Listing. Size and Modified are only set for files.
+type Entry struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Size     uint64     `json:"size"`
+	Modified *time.Time `json:"modified,omitempty"`
+}
+
+// Href returns the entry's link relative to its listing, escaped and ending with a slash for a directory.
+func (e Entry) Href() string {
+	href := escapeName(e.Name)
+	if e.Type == EntryTypeDirectory {
+		href += "/"
+	}
+	return href
+}
+
+var listingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Path}}</title></head>
+<body>
+<h1>{{.Path}}</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Modified</th></tr>
+{{if ne .Path "/"}}<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{end}}{{range .Entries}}<tr><td><a href="{{.Href}}">{{.Name}}{{if eq .Type "directory"}}/{{end}}</a></td>
+<td>{{if eq .Type "file"}}{{.Size}}{{end}}</td><td>{{with .Modified}}{{.UTC.Format "2006-01-02 15:04:05"}}{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func (h *Handler) serveListing(w http.ResponseWriter, r *http.Request, p string) {
+	location, err := h.locationAt(p)
+	if err != nil {
+		httpError(w, err, http.StatusBadRequest)
+		return
+	}
+	listing, err := list(location, p)
+	if err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if len(listing.Entries) == 0 && p != "/" {
+		exists, err := location.Exists()
+		if err != nil {
+			httpError(w, err, http.StatusInternalServerError)
+			return
+		}
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	if acceptsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodHead {
+			return
+		}
+		if err := json.NewEncoder(w).Encode(listing); err != nil {
+			httpError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := listingTemplate.Execute(w, listing); err != nil {
+		httpError(w, err, http.StatusInternalServerError)
+	}
+}
+
+// hasEntries returns whether the location at request path p has any files or directories, in which case a request
+// for p without its trailing slash is redirected to the listing.
+func (h *Handler) hasEntries(p string) (bool, error) {
+	location, err := h.locationAt(p)
+	if err != nil {
+		return false, err
+	}
+	files, err := location.List()
+	if err != nil || len(files) > 0 {
+		return len(files) > 0, err
+	}
+	if lister, ok := location.(utils.DirLister); ok {
+		dirs, err := lister.ListDirs()
+		return len(dirs) > 0, err
+	}
+	return false, nil
+}
+
+// locationAt returns the location at request path p, which ends with a slash.
+func (h *Handler) locationAt(p string) (vfs.Location, error) {
+	if p == "/" {
+		return h.location, nil
+	}
+	return h.location.NewLocation(relativePath(p))
+}
+
+// list returns the listing of location, whose request path is p. Directories are only listed for locations that
+// implement utils.DirLister.
+func list(location vfs.Location, p string) (Listing, error) {
+	listing := Listing{Path: p, Entries: make([]Entry, 0)}
+
+	if lister, ok := location.(utils.DirLister); ok {
+		dirs, err := lister.ListDirs()
+		if err != nil {
+			return Listing{}, err
+		}
+		sort.Strings(dirs)
+		for _, dir := range dirs {
+			listing.Entries = append(listing.Entries, Entry{Name: dir, Type: EntryTypeDirectory})
+		}
+	}
+
+	names, err := location.List()
+	if err != nil {
+		return Listing{}, err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file, err := location.NewFile(name)
+		if err != nil {
+			return Listing{}, err
+		}
+		size, err := file.Size()
+		if err != nil {
+			return Listing{}, err
+		}
+		modified, err := file.LastModified()
+		if err != nil {
+			return Listing{}, err
+		}
+		listing.Entries = append(listing.Entries, Entry{Name: name, Type: EntryTypeFile, Size: size, Modified: modified})
+	}
+	return listing, nil
+}
+
+// acceptsJSON returns whether a listing should be returned as JSON rather than HTML.
+func acceptsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// escapeName returns name escaped for use as a link relative to its location. A name like "a:b" is prefixed with "./"
+// so that it isn't read as a URL with scheme "a".
+func escapeName(name string) string {
+	escaped := (&url.URL{Path: name}).EscapedPath()
+	if strings.Contains(name, ":") {
+		escaped = "./" + escaped
+	}
+	return escaped
+}
@@ -0,0 +1,236 @@
+package vfssimple
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/backend"
+	"github.com/c2fo/vfs/v3/backend/gs"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/backend/s3"
+	"github.com/c2fo/vfs/v3/backend/webhdfs"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// ConfigEnvVar is the environment variable holding the path of the config file loaded by NewFile and NewLocation. When
+// it isn't set, $HOME/.vfs/config.json is loaded if it exists.
+const ConfigEnvVar = "VFS_CONFIG"
+
+// Config holds named remotes, usually loaded from a JSON config file by LoadConfig:
+//
+//   {
+//     "remotes": {
+//       "prod-archive": {
+//         "root": "s3://archive-bucket/prod/",
+//         "options": {"region": "us-east-1", "profile": "prod"}
+//       }
+//     }
+//   }
+type Config struct {
+	Remotes map[string]Remote `json:"remotes"`
+}
+
+// Remote is a FileSystem configured with its own options, rooted at a URI. Once configured, the remote's files can be
+// addressed as <name>:<path>, ie: prod-archive:/2024/x.csv, as well as by any URI below Root.
+type Remote struct {
+	// Scheme is the scheme of the remote's backend, ie: "s3". Defaults to the scheme of Root.
+	Scheme string `json:"scheme,omitempty"`
+
+	// Root is the URI of the location the remote is rooted at, ie: s3://archive-bucket/prod/.
+	Root string `json:"root"`
+
+	// Options are the backend's options, in the JSON form of its Options type, ie: s3.Options. Durations are in
+	// nanoseconds.
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+var (
+	// remotes maps the name of each configured remote to its root URI
+	remotes   = make(map[string]string)
+	remotesMu sync.RWMutex
+
+	defaultConfig    sync.Once
+	defaultConfigErr error
+)
+
+// LoadConfig reads the JSON config file at path.
+func LoadConfig(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("unable to parse vfs config %s: %s", path, err)
+	}
+	return config, nil
+}
+
+// Configure registers a FileSystem for each of the config's remotes, with backend.Register, so that NewFile and
+// NewLocation use it for URIs below the remote's root and for <name>:<path> references to the remote.
+func Configure(config *Config) error {
+	names := make([]string, 0, len(config.Remotes))
+	for name := range config.Remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		remote := config.Remotes[name]
+		if err := validateRemoteName(name); err != nil {
+			return err
+		}
+		root, fs, err := remote.fileSystem()
+		if err != nil {
+			return fmt.Errorf("remote %s: %s", name, err)
+		}
+		backend.Register(root, fs)
+
+		remotesMu.Lock()
+		remotes[name] = root
+		remotesMu.Unlock()
+	}
+	return nil
+}
+
+// Remotes returns the root URI of each configured remote, keyed by name.
+func Remotes() map[string]string {
+	remotesMu.RLock()
+	defer remotesMu.RUnlock()
+	r := make(map[string]string, len(remotes))
+	for name, root := range remotes {
+		r[name] = root
+	}
+	return r
+}
+
+// DefaultConfigPath returns the path of the config file loaded by NewFile and NewLocation: the value of VFS_CONFIG,
+// or $HOME/.vfs/config.json.
+func DefaultConfigPath() string {
+	if path := os.Getenv(ConfigEnvVar); path != "" {
+		return path
+	}
+	// os.UserHomeDir needs go 1.12
+	home := os.Getenv("HOME")
+	if runtime.GOOS == utils.Windows {
+		home = os.Getenv("USERPROFILE")
+	}
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".vfs", "config.json")
+}
+
+// configureDefaults registers the FileSystems configured by environment variables, then the remotes in the file at
+// DefaultConfigPath, the first time it's called. A missing file is only an error when it's named by VFS_CONFIG.
+func configureDefaults() error {
+	defaultConfig.Do(func() {
+		if defaultConfigErr = RegisterFromEnv(); defaultConfigErr != nil {
+			return
+		}
+		path := DefaultConfigPath()
+		if path == "" {
+			return
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) && os.Getenv(ConfigEnvVar) == "" {
+			return
+		}
+		config, err := LoadConfig(path)
+		if err != nil {
+			defaultConfigErr = err
+			return
+		}
+		defaultConfigErr = Configure(config)
+	})
+	return defaultConfigErr
+}
+
+// expandRemote returns uri with a leading <name>: of a configured remote replaced by the remote's root. Any other uri
+// is returned as is.
+func expandRemote(uri string) string {
+	i := strings.Index(uri, ":")
+	if i < 1 || strings.HasPrefix(uri[i+1:], "//") {
+		return uri
+	}
+	remotesMu.RLock()
+	root, ok := remotes[uri[:i]]
+	remotesMu.RUnlock()
+	if !ok {
+		return uri
+	}
+	return root + strings.TrimLeft(uri[i+1:], "/")
+}
+
+// fileSystem returns the remote's root, with a trailing slash, and a FileSystem configured with its options.
+func (r Remote) fileSystem() (string, vfs.FileSystem, error) {
+	u, err := url.Parse(r.Root)
+	if err != nil {
+		return "", nil, err
+	}
+	if !u.IsAbs() {
+		return "", nil, fmt.Errorf("root %q must be a complete URI", r.Root)
+	}
+	scheme := r.Scheme
+	if scheme == "" {
+		scheme = u.Scheme
+	}
+	if scheme != u.Scheme {
+		return "", nil, fmt.Errorf("root %s doesn't have scheme %s", r.Root, scheme)
+	}
+
+	var fs vfs.FileSystem
+	switch scheme {
+	case s3.Scheme:
+		opts := s3.Options{}
+		err = r.decodeOptions(&opts)
+		fs = s3.NewFileSystem().WithOptions(opts)
+	case gs.Scheme:
+		opts := gs.Options{}
+		err = r.decodeOptions(&opts)
+		fs = gs.NewFileSystem().WithOptions(opts)
+	case webhdfs.Scheme:
+		opts := webhdfs.Options{}
+		err = r.decodeOptions(&opts)
+		fs = webhdfs.NewFileSystem().WithOptions(opts)
+	case _os.Scheme:
+		fs = &_os.FileSystem{}
+	default:
+		return "", nil, fmt.Errorf("%s is an unsupported uri scheme", scheme)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return utils.EnsureTrailingSlash(r.Root), fs, nil
+}
+
+func (r Remote) decodeOptions(opts interface{}) error {
+	if len(r.Options) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.Options, opts); err != nil {
+		return fmt.Errorf("unable to parse options: %s", err)
+	}
+	return nil
+}
+
+// validateRemoteName returns an error for a name that can't be told apart from a path or a URI scheme.
+func validateRemoteName(name string) error {
+	if name == "" || strings.ContainsAny(name, ":/") {
+		return fmt.Errorf("remote name %q must be non-empty and can't contain ':' or '/'", name)
+	}
+	for _, scheme := range []string{s3.Scheme, gs.Scheme, webhdfs.Scheme, _os.Scheme} {
+		if name == scheme {
+			return fmt.Errorf("remote name %q is a uri scheme", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package vfssimple
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3/backend"
+	"github.com/c2fo/vfs/v3/backend/s3"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type configTestSuite struct {
+	suite.Suite
+	tmpDir string
+}
+
+func (s *configTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfssimple-test")
+	s.NoError(err)
+	s.tmpDir = dir
+}
+
+func (s *configTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+	remotesMu.Lock()
+	for name, root := range remotes {
+		backend.Unregister(root)
+		delete(remotes, name)
+	}
+	remotesMu.Unlock()
+}
+
+func (s *configTestSuite) TestLoadConfig() {
+	path := filepath.Join(s.tmpDir, "config.json")
+	s.NoError(ioutil.WriteFile(path, []byte(`{
+		"remotes": {
+			"prod-archive": {
+				"root": "s3://archive-bucket/prod",
+				"options": {"region": "us-west-2", "s3ForcePathStyle": true}
+			}
+		}
+	}`), 0600))
+
+	config, err := LoadConfig(path)
+	s.NoError(err)
+	s.NoError(Configure(config))
+	s.Equal(map[string]string{"prod-archive": "s3://archive-bucket/prod/"}, Remotes())
+
+	fs, ok := backend.Backend("s3://archive-bucket/prod/").(*s3.FileSystem)
+	s.True(ok, "remote is registered under its root")
+	client, err := fs.Client()
+	s.NoError(err)
+	s.NotNil(client)
+
+	file, err := NewFile("prod-archive:/2024/x.csv")
+	s.NoError(err)
+	s.Equal("s3://archive-bucket/prod/2024/x.csv", file.URI())
+	s.Equal(fs, file.Location().FileSystem(), "remote's FileSystem is used")
+
+	location, err := NewLocation("prod-archive:")
+	s.NoError(err)
+	s.Equal("s3://archive-bucket/prod/", location.URI())
+
+	_, err = LoadConfig(filepath.Join(s.tmpDir, "missing.json"))
+	s.Error(err)
+
+	s.NoError(ioutil.WriteFile(path, []byte(`{"remotes": `), 0600))
+	_, err = LoadConfig(path)
+	s.Error(err)
+}
+
+func (s *configTestSuite) TestLocalRemote() {
+	s.NoError(Configure(&Config{Remotes: map[string]Remote{
+		"scratch": {Root: "file://" + s.tmpDir + "/"},
+	}}))
+
+	file, err := NewFile("scratch:notes.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("hello"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	content, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "notes.txt"))
+	s.NoError(err)
+	s.Equal("hello", string(content))
+
+	// names that aren't configured remotes are left alone
+	s.Equal("other:notes.txt", expandRemote("other:notes.txt"))
+	s.Equal("s3://bucket/notes.txt", expandRemote("s3://bucket/notes.txt"))
+}
+
+func (s *configTestSuite) TestConfigureErrors() {
+	tests := []struct {
+		name   string
+		remote Remote
+	}{
+		{name: "s3", remote: Remote{Root: "s3://bucket/"}},
+		{name: "a/b", remote: Remote{Root: "s3://bucket/"}},
+		{name: "relative", remote: Remote{Root: "/some/path/"}},
+		{name: "mismatch", remote: Remote{Scheme: "gs", Root: "s3://bucket/"}},
+		{name: "unsupported", remote: Remote{Root: "ftp://host/"}},
+		{name: "badoptions", remote: Remote{Root: "s3://bucket/", Options: []byte(`{"region": 1}`)}},
+	}
+	for _, test := range tests {
+		err := Configure(&Config{Remotes: map[string]Remote{test.name: test.remote}})
+		s.Error(err, test.name)
+	}
+}
+
+func (s *configTestSuite) TestDefaultConfigPath() {
+	home, config := os.Getenv("HOME"), os.Getenv(ConfigEnvVar)
+	defer func() {
+		s.NoError(os.Setenv("HOME", home))
+		s.NoError(os.Setenv(ConfigEnvVar, config))
+	}()
+
+	s.NoError(os.Setenv("HOME", s.tmpDir))
+	s.NoError(os.Setenv(ConfigEnvVar, ""))
+	s.Equal(filepath.Join(s.tmpDir, ".vfs", "config.json"), DefaultConfigPath())
+
+	s.NoError(os.Setenv(ConfigEnvVar, "/etc/vfs.json"))
+	s.Equal("/etc/vfs.json", DefaultConfigPath(), "VFS_CONFIG is used first")
+}
+
+func TestConfig(t *testing.T) {
+	suite.Run(t, new(configTestSuite))
+}
@@ -34,6 +34,29 @@ Just import vfssimple.
 
   }
 
+URIs
+
+URIs are parsed by ParseURI, which takes the path literally rather than percent-decoding it, so keys may contain
+spaces, unicode, '%', '?' and '#', and the URI returned by any File.URI or Location.URI can always be passed back to
+NewFile or NewLocation. URI.Escaped and ParseEscapedURI convert to and from percent-encoded URIs. Absolute local paths,
+including Windows paths such as C:\data\x.csv, are file URIs.
+
+When more than one registered file system could serve a URI, the one registered for the longest root containing it
+wins, then one registered for its scheme.
+
+Files and Locations
+
+NewFile and NewLocation trust the caller to know which a URI is. ParseFile and ParseLocation check the trailing slash
+instead, returning an error explaining the mismatch, and Resolve asks the backend when there's no trailing slash:
+
+  file, location, err := vfssimple.Resolve("s3://mybucket/some/path")
+  if err != nil {
+      return err
+  }
+  if location != nil {
+      // s3://mybucket/some/path/ has files below it
+  }
+
 Authentication and Options
 
 vfssimple is largely an example of how to initialize a set of backend filesystems.  It only provides a default
@@ -77,5 +100,39 @@ file system.
 	secureFile.CopyToLocation(publicLocation)
   }
 
+Environment Variables
+
+Backends can also be configured without code, from environment variables, ie: VFS_S3_ENDPOINT or
+VFS_GS_CREDENTIALS_FILE. Buckets listed in VFS_S3_BUCKETS or VFS_GS_BUCKETS (namenodes in VFS_WEBHDFS_NAMENODES) get
+their own file system, registered at the bucket's URI, with options overridden by variables named after the bucket:
+
+  VFS_S3_REGION=us-east-1
+  VFS_S3_BUCKETS=archive-bucket
+  VFS_S3_BUCKET_ARCHIVE_BUCKET_ENDPOINT=https://minio.internal:9000
+
+NewFile and NewLocation register them with RegisterFromEnv the first time they're called, before loading the config
+file. See s3.OptionsFromEnv, gs.OptionsFromEnv and webhdfs.OptionsFromEnv for each backend's variables.
+
+Named Remotes
+
+Rather than registering file systems in code, remotes can be defined in a JSON config file. Each remote has a name, a
+root URI, and the options of its backend, in the JSON form of the backend's Options type (ie: s3.Options):
+
+  {
+    "remotes": {
+      "prod-archive": {
+        "root": "s3://archive-bucket/prod/",
+        "options": {"region": "us-east-1", "profile": "prod"}
+      }
+    }
+  }
+
+NewFile and NewLocation load the file named by the VFS_CONFIG environment variable, or $HOME/.vfs/config.json if it
+exists, the first time they're called. Each remote's file system is registered under its root, so it's used for any
+URI below the root, and the remote's files can also be addressed by name:
+
+  file, err := vfssimple.NewFile("prod-archive:/2024/x.csv") // s3://archive-bucket/prod/2024/x.csv
+
+Other config files can be applied with LoadConfig and Configure. The vfscp and vfs commands use the same config.
 */
 package vfssimple
@@ -0,0 +1,135 @@
+package vfssimple
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/backend"
+	"github.com/c2fo/vfs/v3/backend/gs"
+	"github.com/c2fo/vfs/v3/backend/s3"
+	"github.com/c2fo/vfs/v3/backend/webhdfs"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// envBackend describes how a backend is configured from the environment.
+type envBackend struct {
+	scheme string
+	prefix string
+
+	// volume names a volume in the environment, ie: BUCKET. <prefix><volume>S lists the volumes with their own
+	// options, which are set by variables named <prefix><volume>_<volume name>_.
+	volume string
+
+	// fileSystem returns a FileSystem with options read from variables with each of the prefixes in turn, so that
+	// later prefixes override earlier ones.
+	fileSystem func(prefixes ...string) (vfs.FileSystem, error)
+}
+
+var envBackends = []envBackend{
+	{
+		scheme: s3.Scheme,
+		prefix: s3.EnvPrefix,
+		volume: "BUCKET",
+		fileSystem: func(prefixes ...string) (vfs.FileSystem, error) {
+			opts := s3.Options{}
+			for _, prefix := range prefixes {
+				var err error
+				if opts, err = s3.OptionsFromEnv(prefix, opts); err != nil {
+					return nil, err
+				}
+			}
+			return s3.NewFileSystem().WithOptions(opts), nil
+		},
+	},
+	{
+		scheme: gs.Scheme,
+		prefix: gs.EnvPrefix,
+		volume: "BUCKET",
+		fileSystem: func(prefixes ...string) (vfs.FileSystem, error) {
+			opts := gs.Options{}
+			for _, prefix := range prefixes {
+				var err error
+				if opts, err = gs.OptionsFromEnv(prefix, opts); err != nil {
+					return nil, err
+				}
+			}
+			return gs.NewFileSystem().WithOptions(opts), nil
+		},
+	},
+	{
+		scheme: webhdfs.Scheme,
+		prefix: webhdfs.EnvPrefix,
+		volume: "NAMENODE",
+		fileSystem: func(prefixes ...string) (vfs.FileSystem, error) {
+			opts := webhdfs.Options{}
+			for _, prefix := range prefixes {
+				var err error
+				if opts, err = webhdfs.OptionsFromEnv(prefix, opts); err != nil {
+					return nil, err
+				}
+			}
+			return webhdfs.NewFileSystem().WithOptions(opts), nil
+		},
+	},
+}
+
+// RegisterFromEnv registers FileSystems configured by environment variables, with backend.Register. When any of a
+// backend's variables are set, ie: VFS_S3_ENDPOINT, its default FileSystem is replaced by one with those options. See
+// s3.OptionsFromEnv, gs.OptionsFromEnv and webhdfs.OptionsFromEnv for the variables of each backend.
+//
+// Buckets (or namenodes, for webhdfs) listed in VFS_S3_BUCKETS, VFS_GS_BUCKETS or VFS_WEBHDFS_NAMENODES get a
+// FileSystem of their own, registered at the bucket's URI, whose options are overridden by variables named after the
+// bucket in upper case, with any character other than a letter or digit replaced by an underscore:
+//
+//   VFS_S3_REGION=us-east-1
+//   VFS_S3_BUCKETS=archive-bucket
+//   VFS_S3_BUCKET_ARCHIVE_BUCKET_ENDPOINT=https://minio.internal:9000
+//   VFS_S3_BUCKET_ARCHIVE_BUCKET_FORCE_PATH_STYLE=true
+//
+// NewFile and NewLocation call RegisterFromEnv the first time they're called.
+func RegisterFromEnv() error {
+	for _, b := range envBackends {
+		volumesPrefix := b.prefix + b.volume
+		if hasEnv(b.prefix, volumesPrefix) {
+			fs, err := b.fileSystem(b.prefix)
+			if err != nil {
+				return err
+			}
+			backend.Register(b.scheme, fs)
+		}
+
+		for _, volume := range utils.SplitList(os.Getenv(volumesPrefix + "S")) {
+			fs, err := b.fileSystem(b.prefix, volumesPrefix+"_"+envName(volume)+"_")
+			if err != nil {
+				return err
+			}
+			backend.Register(fmt.Sprintf("%s://%s/", b.scheme, volume), fs)
+		}
+	}
+	return nil
+}
+
+// hasEnv returns whether any non-empty environment variable starts with prefix but not with except.
+func hasEnv(prefix, except string) bool {
+	for _, env := range os.Environ() {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) == 2 && parts[1] != "" && strings.HasPrefix(parts[0], prefix) && !strings.HasPrefix(parts[0], except) {
+			return true
+		}
+	}
+	return false
+}
+
+// envName returns name in upper case with any character other than a letter or digit replaced by an underscore, ie:
+// "archive-bucket.v2" becomes "ARCHIVE_BUCKET_V2".
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
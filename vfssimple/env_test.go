@@ -0,0 +1,80 @@
+package vfssimple
+
+import (
+	"os"
+	"testing"
+
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/backend"
+	"github.com/c2fo/vfs/v3/backend/s3"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type envTestSuite struct {
+	suite.Suite
+	defaults map[string]vfs.FileSystem
+}
+
+func (s *envTestSuite) SetupTest() {
+	s.defaults = make(map[string]vfs.FileSystem)
+	for _, b := range envBackends {
+		s.defaults[b.scheme] = backend.Backend(b.scheme)
+	}
+}
+
+func (s *envTestSuite) TearDownTest() {
+	for scheme, fs := range s.defaults {
+		backend.Register(scheme, fs)
+	}
+	backend.Unregister("s3://archive-bucket.v2/")
+	for _, name := range []string{"VFS_S3_REGION", "VFS_S3_BUCKETS", "VFS_S3_BUCKET_ARCHIVE_BUCKET_V2_ENDPOINT", "VFS_GS_ENDPOINT"} {
+		_ = os.Unsetenv(name)
+	}
+}
+
+func (s *envTestSuite) endpoint(fs vfs.FileSystem) string {
+	client, err := fs.(*s3.FileSystem).Client()
+	s.NoError(err)
+	return client.(*awss3.S3).Endpoint
+}
+
+func (s *envTestSuite) TestRegisterFromEnv() {
+	s.NoError(os.Setenv("VFS_S3_REGION", "us-west-2"))
+	s.NoError(os.Setenv("VFS_S3_BUCKETS", "archive-bucket.v2"))
+	s.NoError(os.Setenv("VFS_S3_BUCKET_ARCHIVE_BUCKET_V2_ENDPOINT", "http://minio:9000"))
+	s.NoError(RegisterFromEnv())
+
+	s.NotEqual(s.defaults[s3.Scheme], backend.Backend(s3.Scheme), "default s3 FileSystem is replaced")
+	s.Equal(s.defaults["gs"], backend.Backend("gs"), "backends without variables are left alone")
+	s.Equal("https://s3.us-west-2.amazonaws.com", s.endpoint(backend.Backend(s3.Scheme)))
+
+	bucketFs := backend.Backend("s3://archive-bucket.v2/")
+	s.NotNil(bucketFs, "bucket FileSystem is registered")
+	s.Equal("http://minio:9000", s.endpoint(bucketFs))
+
+	file, err := NewFile("s3://archive-bucket.v2/some/data.csv")
+	s.NoError(err)
+	s.Equal(bucketFs, file.Location().FileSystem())
+}
+
+func (s *envTestSuite) TestRegisterFromEnvOnlyBuckets() {
+	s.NoError(os.Setenv("VFS_S3_BUCKETS", "archive-bucket.v2"))
+	s.NoError(RegisterFromEnv())
+	s.Equal(s.defaults[s3.Scheme], backend.Backend(s3.Scheme), "listing buckets doesn't replace the default")
+	s.NotNil(backend.Backend("s3://archive-bucket.v2/"))
+}
+
+func (s *envTestSuite) TestEnvName() {
+	s.Equal("ARCHIVE_BUCKET_V2", envName("archive-bucket.v2"))
+	s.Equal("NAMENODE_9870", envName("namenode:9870"))
+}
+
+func TestEnv(t *testing.T) {
+	suite.Run(t, new(envTestSuite))
+}
@@ -0,0 +1,84 @@
+package vfssimple
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// ParseFile returns the File at uri, or an error if uri ends with a slash, since only locations do.
+func ParseFile(uri string) (vfs.File, error) {
+	fs, u, err := parseSupportedURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(u.Path, "/") {
+		return nil, fmt.Errorf("%s is a location, since it ends with a slash, not a file", uri)
+	}
+	return fs.NewFile(u.Volume, u.Path)
+}
+
+// ParseLocation returns the Location at uri, or an error if uri doesn't end with a slash, since only locations do.
+func ParseLocation(uri string) (vfs.Location, error) {
+	fs, u, err := parseSupportedURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		return nil, fmt.Errorf("%s is a file, since it doesn't end with a slash, not a location (use %s/)", uri, uri)
+	}
+	return fs.NewLocation(u.Volume, u.Path)
+}
+
+// Resolve returns either the File or the Location at uri, the other being nil. A uri ending with a slash is always a
+// location. Otherwise the backend is checked: uri is a location if the location containing it lists it as a directory
+// or, for Locations that don't implement utils.DirLister, if no file exists at uri but there are files directly below
+// it. Anything else, including a uri where nothing exists yet, is a file.
+func Resolve(uri string) (vfs.File, vfs.Location, error) {
+	fs, u, err := parseSupportedURI(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	if strings.HasSuffix(u.Path, "/") {
+		location, err := fs.NewLocation(u.Volume, u.Path)
+		return nil, location, err
+	}
+
+	file, err := fs.NewFile(u.Volume, u.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	location, err := fs.NewLocation(u.Volume, utils.EnsureTrailingSlash(u.Path))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// an object store may have both a file and files below a prefix of the same name, in which case it's a location
+	if lister, ok := file.Location().(utils.DirLister); ok {
+		dirs, err := lister.ListDirs()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, dir := range dirs {
+			if dir == file.Name() {
+				return nil, location, nil
+			}
+		}
+		return file, nil, nil
+	}
+
+	exists, err := file.Exists()
+	if err != nil || exists {
+		return file, nil, err
+	}
+	names, err := location.List()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(names) > 0 {
+		return nil, location, nil
+	}
+	return file, nil, nil
+}
@@ -0,0 +1,127 @@
+package vfssimple
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3/backend"
+	"github.com/c2fo/vfs/v3/mocks"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type resolveTestSuite struct {
+	suite.Suite
+	tmpDir string
+	root   string
+}
+
+func (s *resolveTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfssimple-test")
+	s.NoError(err)
+	s.tmpDir = dir
+	s.root = "file://" + dir + "/"
+
+	s.NoError(os.MkdirAll(filepath.Join(dir, "dir", "sub"), 0700))
+	for _, name := range []string{"file.txt", "dir/a.txt"} {
+		s.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0600))
+	}
+}
+
+func (s *resolveTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+	backend.Unregister("mock")
+}
+
+func (s *resolveTestSuite) TestParseFile() {
+	file, err := ParseFile(s.root + "file.txt")
+	s.NoError(err)
+	s.Equal(s.root+"file.txt", file.URI())
+
+	_, err = ParseFile(s.root + "dir/")
+	s.EqualError(err, s.root+"dir/ is a location, since it ends with a slash, not a file")
+}
+
+func (s *resolveTestSuite) TestParseLocation() {
+	location, err := ParseLocation(s.root + "dir/")
+	s.NoError(err)
+	s.Equal(s.root+"dir/", location.URI())
+
+	_, err = ParseLocation(s.root + "dir")
+	s.EqualError(err, s.root+"dir is a file, since it doesn't end with a slash, not a location (use "+s.root+"dir/)")
+
+	_, err = ParseLocation("relative/dir/")
+	s.Error(err)
+}
+
+func (s *resolveTestSuite) TestResolve() {
+	tests := []struct {
+		uri        string
+		isLocation bool
+		expected   string
+	}{
+		{uri: s.root + "file.txt", expected: s.root + "file.txt"},
+		{uri: s.root + "dir", isLocation: true, expected: s.root + "dir/"},
+		{uri: s.root + "dir/", isLocation: true, expected: s.root + "dir/"},
+		{uri: s.root + "dir/sub", isLocation: true, expected: s.root + "dir/sub/"},
+		{uri: s.root + "missing.txt", expected: s.root + "missing.txt"},
+		{uri: s.root + "missing/", isLocation: true, expected: s.root + "missing/"},
+	}
+	for _, test := range tests {
+		file, location, err := Resolve(test.uri)
+		s.NoError(err, test.uri)
+		if test.isLocation {
+			s.Nil(file, test.uri)
+			s.Equal(test.expected, location.URI(), test.uri)
+		} else {
+			s.Nil(location, test.uri)
+			s.Equal(test.expected, file.URI(), test.uri)
+		}
+	}
+}
+
+func (s *resolveTestSuite) TestResolveWithoutDirLister() {
+	// mock Locations don't list directories, so a location is found by the files below it
+	tests := []struct {
+		name       string
+		exists     bool
+		below      []string
+		isLocation bool
+	}{
+		{name: "dir", below: []string{"b.txt"}, isLocation: true},
+		{name: "file.txt", exists: true},
+		{name: "missing"},
+	}
+	for _, test := range tests {
+		parent := new(mocks.Location)
+		location := new(mocks.Location)
+		location.On("List").Return(test.below, nil)
+		file := new(mocks.File)
+		file.On("Location").Return(parent)
+		file.On("Exists").Return(test.exists, nil)
+		fs := new(mocks.FileSystem)
+		fs.On("NewFile", "volume", "/"+test.name).Return(file, nil)
+		fs.On("NewLocation", "volume", "/"+test.name+"/").Return(location, nil)
+		backend.Register("mock", fs)
+
+		resolvedFile, resolvedLocation, err := Resolve("mock://volume/" + test.name)
+		s.NoError(err, test.name)
+		if test.isLocation {
+			s.Nil(resolvedFile, test.name)
+			s.Equal(location, resolvedLocation, test.name)
+		} else {
+			s.Nil(resolvedLocation, test.name)
+			s.Equal(file, resolvedFile, test.name)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	suite.Run(t, new(resolveTestSuite))
+}
@@ -0,0 +1,163 @@
+package vfssimple
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/backend"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// schemeRegex matches a URI scheme as defined by RFC 3986.
+var schemeRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.\-]*$`)
+
+// drivePathRegex matches a Windows path starting with a drive letter, ie: C:\data or C:/data.
+var drivePathRegex = regexp.MustCompile(`^[A-Za-z]:([\\/]|$)`)
+
+// URI is a URI split into the parts used to find its FileSystem and to create its File or Location.
+type URI struct {
+	// Scheme is the scheme of the URI in lower case, ie: "s3".
+	Scheme string
+
+	// Volume is the authority of the URI, ie: the bucket of an s3 URI. It's empty for local file URIs.
+	Volume string
+
+	// Path is the absolute path of the URI, ending with a slash for a location. Local Windows paths start with their
+	// drive letter instead, ie: C:\data\x.csv.
+	Path string
+}
+
+// ParseURI splits uri into its scheme, volume and path. The path is taken literally rather than percent-decoded, so it
+// may contain any character, including spaces, unicode, '%', '?' and '#', and ParseURI(file.URI()) always returns the
+// file's own volume and path. Use ParseEscapedURI for URIs whose paths are percent-encoded.
+//
+// Local paths without a scheme, either absolute (/data/x.csv) or Windows paths (C:\data\x.csv), are file URIs.
+func ParseURI(uri string) (URI, error) {
+	if strings.HasPrefix(uri, "/") || drivePathRegex.MatchString(uri) {
+		return URI{Scheme: _os.Scheme, Path: uri}, nil
+	}
+
+	i := strings.Index(uri, "://")
+	if i < 0 {
+		return URI{}, fmt.Errorf("%s is not a complete URI (scheme://authority/path) or absolute path", uri)
+	}
+	if !schemeRegex.MatchString(uri[:i]) {
+		return URI{}, fmt.Errorf("%s has an invalid uri scheme", uri)
+	}
+	u := URI{Scheme: strings.ToLower(uri[:i])}
+
+	rest := uri[i+len("://"):]
+	if u.Scheme == _os.Scheme && drivePathRegex.MatchString(rest) {
+		// file://C:\data\x.csv, as returned by File.URI on Windows
+		u.Path = rest
+		return u, nil
+	}
+	if j := strings.Index(rest, "/"); j >= 0 {
+		u.Volume, u.Path = rest[:j], rest[j:]
+	} else {
+		u.Volume, u.Path = rest, "/"
+	}
+	if u.Scheme == _os.Scheme && runtime.GOOS == utils.Windows && drivePathRegex.MatchString(u.Path[1:]) {
+		// file:///C:/data/x.csv
+		u.Path = u.Path[1:]
+	}
+	return u, nil
+}
+
+// IsURI returns whether s is a URI, or a <name>:<path> reference to a remote, rather than a local path. Windows paths,
+// such as C:\data, aren't URIs.
+func IsURI(s string) bool {
+	i := strings.Index(s, ":")
+	return i > 1 && !strings.ContainsAny(s[:i], `/\`)
+}
+
+// NormalizeURI returns s unchanged when it's a URI, and otherwise turns the local path s into an absolute file:// URI,
+// keeping any trailing slash so that locations can be told apart from files.
+func NormalizeURI(s string) (string, error) {
+	if IsURI(s) {
+		return s, nil
+	}
+	absPath, err := filepath.Abs(s)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(s, "/") || s == "." || s == ".." {
+		absPath = utils.EnsureTrailingSlash(absPath)
+	}
+	return _os.Scheme + "://" + absPath, nil
+}
+
+// ParseEscapedURI parses uri as ParseURI does, then percent-decodes its path, for URIs escaped by URI.Escaped or by
+// other RFC 3986 tools.
+func ParseEscapedURI(uri string) (URI, error) {
+	u, err := ParseURI(uri)
+	if err != nil {
+		return URI{}, err
+	}
+	if u.Path, err = url.PathUnescape(u.Path); err != nil {
+		return URI{}, fmt.Errorf("%s has an invalid escape: %s", uri, err)
+	}
+	return u, nil
+}
+
+// String returns the URI in the unescaped form returned by File.URI and Location.URI, ie: s3://bucket/my file.txt.
+func (u URI) String() string {
+	return fmt.Sprintf("%s://%s%s", u.Scheme, u.Volume, u.Path)
+}
+
+// Escaped returns the URI with its path percent-encoded, for use where a valid RFC 3986 URI is needed, ie:
+// s3://bucket/my%20file.txt. ParseEscapedURI(u.Escaped()) returns u.
+func (u URI) Escaped() string {
+	p := u.Path
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Volume, Path: p}).String()
+}
+
+// fileSystem returns the registered FileSystem for the URI. A FileSystem registered for a root URI, ie:
+// s3://bucket/prefix/, is used for every URI below the root, a FileSystem registered for an object URI is used for that
+// object only, and otherwise the FileSystem registered for the scheme is used. When several roots contain the URI,
+// the longest wins.
+func (u URI) fileSystem() (vfs.FileSystem, error) {
+	var fs vfs.FileSystem
+	longest := -1
+	for _, name := range backend.RegisteredBackends() {
+		length := u.matchLength(name)
+		if length > longest {
+			fs, longest = backend.Backend(name), length
+		}
+	}
+	if fs == nil {
+		return nil, fmt.Errorf("%s is an unsupported uri scheme", u.Scheme)
+	}
+	return fs, nil
+}
+
+// matchLength returns how specifically the registered backend name matches the URI: 0 for its scheme, the length of
+// a root or object URI containing it, or -1 when it doesn't match.
+func (u URI) matchLength(name string) int {
+	if !strings.Contains(name, "://") {
+		if strings.EqualFold(name, u.Scheme) {
+			return 0
+		}
+		return -1
+	}
+	root, err := ParseURI(name)
+	if err != nil || root.Scheme != u.Scheme || root.Volume != u.Volume {
+		return -1
+	}
+	switch {
+	case root.Path == u.Path:
+	case strings.HasSuffix(root.Path, "/") && strings.HasPrefix(utils.EnsureTrailingSlash(u.Path), root.Path):
+	default:
+		return -1
+	}
+	return len(root.String())
+}
@@ -0,0 +1,178 @@
+package vfssimple
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3/backend"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+	"github.com/c2fo/vfs/v3/backend/s3"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type uriTestSuite struct {
+	suite.Suite
+	registered []string
+}
+
+func (s *uriTestSuite) TearDownTest() {
+	for _, name := range s.registered {
+		backend.Unregister(name)
+	}
+	s.registered = nil
+}
+
+func (s *uriTestSuite) register(name string) *s3.FileSystem {
+	fs := s3.NewFileSystem()
+	backend.Register(name, fs)
+	s.registered = append(s.registered, name)
+	return fs
+}
+
+func (s *uriTestSuite) TestParseURI() {
+	tests := []struct {
+		uri      string
+		expected URI
+	}{
+		{uri: "s3://bucket/path/to/file.txt", expected: URI{Scheme: "s3", Volume: "bucket", Path: "/path/to/file.txt"}},
+		{uri: "S3://bucket/dir/", expected: URI{Scheme: "s3", Volume: "bucket", Path: "/dir/"}},
+		{uri: "gs://bucket", expected: URI{Scheme: "gs", Volume: "bucket", Path: "/"}},
+		{uri: "s3://bucket/my file ü.txt", expected: URI{Scheme: "s3", Volume: "bucket", Path: "/my file ü.txt"}},
+		{uri: "s3://bucket/100%.txt", expected: URI{Scheme: "s3", Volume: "bucket", Path: "/100%.txt"}},
+		{uri: "s3://bucket/a%20b.txt", expected: URI{Scheme: "s3", Volume: "bucket", Path: "/a%20b.txt"}},
+		{uri: "s3://bucket/key?versionId=1#x", expected: URI{Scheme: "s3", Volume: "bucket", Path: "/key?versionId=1#x"}},
+		{uri: "webhdfs://namenode:9870/data/", expected: URI{Scheme: "webhdfs", Volume: "namenode:9870", Path: "/data/"}},
+		{uri: "file:///tmp/x.txt", expected: URI{Scheme: "file", Path: "/tmp/x.txt"}},
+		{uri: "/tmp/x.txt", expected: URI{Scheme: "file", Path: "/tmp/x.txt"}},
+		{uri: `C:\data\x.csv`, expected: URI{Scheme: "file", Path: `C:\data\x.csv`}},
+		{uri: `file://C:\data\x.csv`, expected: URI{Scheme: "file", Path: `C:\data\x.csv`}},
+	}
+	for _, test := range tests {
+		u, err := ParseURI(test.uri)
+		s.NoError(err, test.uri)
+		s.Equal(test.expected, u, test.uri)
+	}
+
+	for _, uri := range []string{"relative/path.txt", "s3:/bucket/key", "3s://bucket/key", "://bucket/key"} {
+		_, err := ParseURI(uri)
+		s.Error(err, uri)
+	}
+}
+
+func (s *uriTestSuite) TestEscaped() {
+	u := URI{Scheme: "s3", Volume: "bucket", Path: "/dir/my file 100%?#ü.txt"}
+	s.Equal("s3://bucket/dir/my%20file%20100%25%3F%23%C3%BC.txt", u.Escaped())
+	s.Equal("s3://bucket/dir/my file 100%?#ü.txt", u.String())
+
+	parsed, err := ParseEscapedURI(u.Escaped())
+	s.NoError(err)
+	s.Equal(u, parsed, "escaped URIs round trip")
+
+	_, err = ParseEscapedURI("s3://bucket/100%.txt")
+	s.Error(err, "invalid escape")
+}
+
+func (s *uriTestSuite) TestIsURI() {
+	s.True(IsURI("s3://bucket/key"))
+	s.True(IsURI("prod-archive:/2024/x.csv"))
+	s.False(IsURI("/tmp/x.txt"))
+	s.False(IsURI("relative/a:b.txt"))
+	s.False(IsURI(`C:\data\x.csv`))
+}
+
+func (s *uriTestSuite) TestNormalizeURI() {
+	wd, err := os.Getwd()
+	s.NoError(err)
+	tests := []struct {
+		arg      string
+		expected string
+	}{
+		{arg: "s3://bucket/key", expected: "s3://bucket/key"},
+		{arg: "prod-archive:/2024/", expected: "prod-archive:/2024/"},
+		{arg: "/tmp/x.txt", expected: "file:///tmp/x.txt"},
+		{arg: "/tmp/data/", expected: "file:///tmp/data/"},
+		{arg: "x.txt", expected: "file://" + filepath.Join(wd, "x.txt")},
+		{arg: "data/", expected: "file://" + filepath.Join(wd, "data") + "/"},
+		{arg: ".", expected: "file://" + wd + "/"},
+	}
+	for _, test := range tests {
+		uri, err := NormalizeURI(test.arg)
+		s.NoError(err)
+		s.Equal(test.expected, uri, test.arg)
+	}
+}
+
+func (s *uriTestSuite) TestLongestRoot() {
+	bucket := s.register("s3://bucket/")
+	prefix := s.register("s3://bucket/prefix/")
+	object := s.register("s3://bucket/prefix/object.txt")
+
+	tests := []struct {
+		uri      string
+		expected interface{}
+	}{
+		{uri: "s3://bucket/other.txt", expected: bucket},
+		{uri: "s3://bucket/prefix/a.txt", expected: prefix},
+		{uri: "s3://bucket/prefix", expected: prefix},
+		{uri: "s3://bucket/prefixed.txt", expected: bucket},
+		{uri: "s3://bucket/prefix/object.txt", expected: object},
+		{uri: "s3://bucket/prefix/object.txt.bak", expected: prefix},
+		{uri: "s3://other/prefix/a.txt", expected: backend.Backend(s3.Scheme)},
+		// registered scheme names elsewhere in a URI don't match
+		{uri: "s3://other/file.txt", expected: backend.Backend(s3.Scheme)},
+		{uri: "s3://other/s3://bucket/x", expected: backend.Backend(s3.Scheme)},
+	}
+	for _, test := range tests {
+		u, err := ParseURI(test.uri)
+		s.NoError(err)
+		fs, err := u.fileSystem()
+		s.NoError(err, test.uri)
+		s.True(fs == test.expected, test.uri)
+	}
+
+	u, err := ParseURI("ftp://host/file.txt")
+	s.NoError(err)
+	_, err = u.fileSystem()
+	s.EqualError(err, "ftp is an unsupported uri scheme")
+}
+
+func (s *uriTestSuite) TestRoundTrip() {
+	dir, err := ioutil.TempDir("", "vfssimple-test")
+	s.NoError(err)
+	defer func() { s.NoError(os.RemoveAll(dir)) }()
+
+	name := filepath.Join(dir, "my file 100%?#ü.txt")
+	s.NoError(ioutil.WriteFile(name, []byte("content"), 0600))
+	osFile, err := (&_os.FileSystem{}).NewFile("", name)
+	s.NoError(err)
+
+	file, err := NewFile(osFile.URI())
+	s.NoError(err)
+	s.Equal(osFile.URI(), file.URI())
+	s.Equal(name, file.Path())
+	content, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("content", string(content))
+
+	s3File, err := s3.NewFileSystem().NewFile("bucket", "/dir/my file 100%?#ü.txt")
+	s.NoError(err)
+	file, err = NewFile(s3File.URI())
+	s.NoError(err)
+	s.Equal(s3File.URI(), file.URI())
+	s.Equal("/dir/my file 100%?#ü.txt", file.Path())
+
+	location, err := NewLocation("s3://bucket/dir with space/")
+	s.NoError(err)
+	s.Equal("s3://bucket/dir with space/", location.URI())
+}
+
+func TestURI(t *testing.T) {
+	suite.Run(t, new(uriTestSuite))
+}
@@ -0,0 +1,45 @@
+/*
+Package vfssync makes the files below one vfs.Location match the files below another, on any backends, copying only
+the files that are missing or changed and optionally deleting extraneous ones, much like rsync.
+
+Usage
+
+  import(
+      "github.com/c2fo/vfs/v3/vfssimple"
+      "github.com/c2fo/vfs/v3/vfssync"
+  )
+
+  func Mirror() error {
+      src, err := vfssimple.NewLocation("file:///data/exports/")
+      if err != nil {
+          return err
+      }
+      target, err := vfssimple.NewLocation("s3://mybucket/exports/")
+      if err != nil {
+          return err
+      }
+      actions, err := vfssync.Sync(src, target, vfssync.Options{Delete: true, Checksum: true})
+      if err != nil {
+          return err
+      }
+      for _, action := range actions {
+          if action.Err != nil {
+              return action.Err
+          }
+      }
+      return nil
+  }
+
+Plan returns the same actions without taking them, as does Sync with Options.DryRun.
+
+Comparison
+
+A source file is copied when there's no file at the same relative path below the target, or updated when the target
+file's size differs or the source file was modified after it. With Options.Checksum, files of the same size are
+compared by MD5 instead of modification time whenever both files report one (see MD5er): os files calculate it, s3
+files take it from their ETag (except multipart uploads) and gs files from their attributes.
+
+Both locations must be able to list their directories (see utils.DirLister), which os, s3, gs and webhdfs locations
+can.
+*/
+package vfssync
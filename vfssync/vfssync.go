@@ -0,0 +1,273 @@
+package vfssync
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/c2fo/vfs/v3"
+	"github.com/c2fo/vfs/v3/utils"
+)
+
+// ActionType is what an Action does to bring a target file in line with its source.
+type ActionType string
+
+// Actions a sync can take.
+const (
+	// Copy copies a source file that's missing at the target.
+	Copy ActionType = "copy"
+	// Update copies a source file over a target file that's changed.
+	Update ActionType = "update"
+	// Delete deletes a target file that isn't at the source, when Options.Delete is set.
+	Delete ActionType = "delete"
+)
+
+// MD5er is implemented by Files that can report the MD5 of their content, such as os, s3 and gs files. MD5 returns ""
+// when the MD5 isn't known.
+type MD5er interface {
+	MD5() (string, error)
+}
+
+// Options holds the options of a sync.
+type Options struct {
+	// Delete deletes files at the target that aren't at the source.
+	Delete bool
+
+	// DryRun plans the actions of a sync without taking them.
+	DryRun bool
+
+	// Checksum compares the MD5 of files of the same size, when both files report one, instead of their modification
+	// times.
+	Checksum bool
+
+	// Filter, if set, limits the sync to files whose path, relative to the source or target location, it returns
+	// true for. Target files it returns false for are never deleted.
+	Filter func(relativePath string) bool
+
+	// Parallel is the number of actions taken at once. Defaults to 1.
+	Parallel int
+
+	// OnAction, if set, is called as each action is finished, with the action's Err set if it failed. It may be
+	// called from several goroutines at once when Parallel is more than 1.
+	OnAction func(Action)
+}
+
+// Action is a change to a single target file.
+type Action struct {
+	Type ActionType
+
+	// RelativePath is the path of the file relative to the source and target locations.
+	RelativePath string
+
+	// Source is the source file, or nil for a Delete.
+	Source vfs.File
+
+	// Target is the target file.
+	Target vfs.File
+
+	// Reason explains why the action is needed, ie: "size differs".
+	Reason string
+
+	// Size is the size of the source file, or of the target file for a Delete.
+	Size uint64
+
+	// Err is the error taking the action, if any.
+	Err error
+}
+
+// String returns a description of the action, ie: "update s3://bucket/a.txt (size differs)".
+func (a Action) String() string {
+	return fmt.Sprintf("%s %s (%s)", a.Type, a.Target, a.Reason)
+}
+
+// Plan compares every file below src with the file at the same relative path below target, returning the actions that
+// would make target match src, in order of relative path. Both locations must implement utils.DirLister.
+func Plan(src, target vfs.Location, opts Options) ([]Action, error) {
+	srcFiles, err := files(src, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	targetFiles, err := files(target, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []Action
+	for relativePath, srcFile := range srcFiles {
+		targetFile, ok := targetFiles[relativePath]
+		if !ok {
+			targetFile, err = target.NewFile(relativePath)
+			if err != nil {
+				return nil, err
+			}
+		}
+		size, err := srcFile.Size()
+		if err != nil {
+			return nil, err
+		}
+		action := Action{RelativePath: relativePath, Source: srcFile, Target: targetFile, Size: size}
+
+		if !ok {
+			action.Type = Copy
+			action.Reason = "missing at target"
+			actions = append(actions, action)
+			continue
+		}
+		reason, err := compare(srcFile, targetFile, size, opts.Checksum)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			action.Type = Update
+			action.Reason = reason
+			actions = append(actions, action)
+		}
+	}
+
+	if opts.Delete {
+		for relativePath, targetFile := range targetFiles {
+			if _, ok := srcFiles[relativePath]; ok {
+				continue
+			}
+			size, err := targetFile.Size()
+			if err != nil {
+				return nil, err
+			}
+			actions = append(actions, Action{
+				Type:         Delete,
+				RelativePath: relativePath,
+				Target:       targetFile,
+				Reason:       "missing at source",
+				Size:         size,
+			})
+		}
+	}
+
+	sort.Slice(actions, func(i, j int) bool { return actions[i].RelativePath < actions[j].RelativePath })
+	return actions, nil
+}
+
+// Sync makes the files below target match the files below src, returning the actions planned by Plan. Unless
+// opts.DryRun is set the actions are taken, each with its Err set if it failed. A non-nil error is only returned when
+// planning fails; failed actions don't stop the others.
+func Sync(src, target vfs.Location, opts Options) ([]Action, error) {
+	actions, err := Plan(src, target, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		if opts.OnAction != nil {
+			for _, action := range actions {
+				opts.OnAction(action)
+			}
+		}
+		return actions, nil
+	}
+
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				action := &actions[index]
+				action.Err = take(action)
+				if opts.OnAction != nil {
+					opts.OnAction(*action)
+				}
+			}
+		}()
+	}
+	for i := range actions {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return actions, nil
+}
+
+// files returns every file below location accepted by filter, keyed by relative path.
+func files(location vfs.Location, filter func(string) bool) (map[string]vfs.File, error) {
+	found := make(map[string]vfs.File)
+	err := utils.Walk(location, func(relativePath string, file vfs.File) error {
+		if filter == nil || filter(relativePath) {
+			found[relativePath] = file
+		}
+		return nil
+	})
+	return found, err
+}
+
+// compare returns why target differs from src, whose size is srcSize, or "" if it doesn't.
+func compare(src, target vfs.File, srcSize uint64, checksum bool) (string, error) {
+	targetSize, err := target.Size()
+	if err != nil {
+		return "", err
+	}
+	if srcSize != targetSize {
+		return "size differs", nil
+	}
+
+	if checksum {
+		srcMD5, targetMD5, err := md5s(src, target)
+		if err != nil {
+			return "", err
+		}
+		if srcMD5 != "" && targetMD5 != "" {
+			if srcMD5 != targetMD5 {
+				return "checksum differs", nil
+			}
+			return "", nil
+		}
+	}
+
+	srcModified, err := src.LastModified()
+	if err != nil {
+		return "", err
+	}
+	targetModified, err := target.LastModified()
+	if err != nil {
+		return "", err
+	}
+	if srcModified.After(*targetModified) {
+		return "source is newer", nil
+	}
+	return "", nil
+}
+
+// md5s returns the MD5 of both files, or "" for a file that doesn't report one.
+func md5s(src, target vfs.File) (string, string, error) {
+	srcMD5, ok := src.(MD5er)
+	if !ok {
+		return "", "", nil
+	}
+	targetMD5, ok := target.(MD5er)
+	if !ok {
+		return "", "", nil
+	}
+	s, err := srcMD5.MD5()
+	if err != nil {
+		return "", "", err
+	}
+	t, err := targetMD5.MD5()
+	if err != nil {
+		return "", "", err
+	}
+	return s, t, nil
+}
+
+// take takes a single action.
+func take(action *Action) error {
+	switch action.Type {
+	case Copy, Update:
+		return action.Source.CopyToFile(action.Target)
+	case Delete:
+		return action.Target.Delete()
+	}
+	return fmt.Errorf("unknown action %q", action.Type)
+}
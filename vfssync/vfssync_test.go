@@ -0,0 +1,144 @@
+package vfssync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v3"
+	_os "github.com/c2fo/vfs/v3/backend/os"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type syncTestSuite struct {
+	suite.Suite
+	tmpDir string
+	src    vfs.Location
+	target vfs.Location
+}
+
+func (s *syncTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfssync-test")
+	s.NoError(err)
+	s.tmpDir = dir
+
+	old := time.Now().Add(-time.Hour)
+	s.writeFile("src/same.txt", "same", old)
+	s.writeFile("target/same.txt", "same", time.Now())
+	s.writeFile("src/missing.txt", "missing", old)
+	s.writeFile("src/dir/resized.txt", "longer content", old)
+	s.writeFile("target/dir/resized.txt", "short", old)
+	s.writeFile("src/newer.txt", "new!", time.Now())
+	s.writeFile("target/newer.txt", "old!", old)
+	s.writeFile("target/extra.txt", "extra", old)
+
+	osfs := &_os.FileSystem{}
+	s.src, err = osfs.NewLocation("", filepath.Join(dir, "src")+"/")
+	s.NoError(err)
+	s.target, err = osfs.NewLocation("", filepath.Join(dir, "target")+"/")
+	s.NoError(err)
+}
+
+func (s *syncTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *syncTestSuite) writeFile(name, contents string, modified time.Time) {
+	p := filepath.Join(s.tmpDir, name)
+	s.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+	s.NoError(ioutil.WriteFile(p, []byte(contents), 0644))
+	s.NoError(os.Chtimes(p, modified, modified))
+}
+
+func (s *syncTestSuite) readFile(name string) string {
+	contents, err := ioutil.ReadFile(filepath.Join(s.tmpDir, name))
+	s.NoError(err)
+	return string(contents)
+}
+
+func summarize(actions []Action) map[string]ActionType {
+	summary := make(map[string]ActionType)
+	for _, action := range actions {
+		summary[action.RelativePath] = action.Type
+	}
+	return summary
+}
+
+func (s *syncTestSuite) TestPlan() {
+	actions, err := Plan(s.src, s.target, Options{})
+	s.NoError(err)
+	s.Equal(map[string]ActionType{
+		"dir/resized.txt": Update,
+		"missing.txt":     Copy,
+		"newer.txt":       Update,
+	}, summarize(actions))
+	s.Equal("dir/resized.txt", actions[0].RelativePath, "actions are in order of path")
+	s.Equal("size differs", actions[0].Reason)
+
+	actions, err = Plan(s.src, s.target, Options{Delete: true})
+	s.NoError(err)
+	s.Equal(Delete, summarize(actions)["extra.txt"])
+}
+
+func (s *syncTestSuite) TestChecksum() {
+	s.writeFile("target/newer.txt", "new!", time.Now().Add(-2*time.Hour))
+	actions, err := Plan(s.src, s.target, Options{Checksum: true})
+	s.NoError(err)
+	_, planned := summarize(actions)["newer.txt"]
+	s.False(planned, "same content isn't copied, whatever its modification time")
+
+	s.writeFile("target/same.txt", "diff", time.Now())
+	actions, err = Plan(s.src, s.target, Options{Checksum: true})
+	s.NoError(err)
+	s.Equal(Update, summarize(actions)["same.txt"])
+}
+
+func (s *syncTestSuite) TestFilter() {
+	actions, err := Plan(s.src, s.target, Options{
+		Delete: true,
+		Filter: func(relativePath string) bool { return filepath.Dir(relativePath) == "dir" },
+	})
+	s.NoError(err)
+	s.Equal(map[string]ActionType{"dir/resized.txt": Update}, summarize(actions))
+}
+
+func (s *syncTestSuite) TestDryRun() {
+	var reported []Action
+	actions, err := Sync(s.src, s.target, Options{Delete: true, DryRun: true, OnAction: func(a Action) {
+		reported = append(reported, a)
+	}})
+	s.NoError(err)
+	s.Len(actions, 4)
+	s.Equal(actions, reported)
+	s.Equal("short", s.readFile("target/dir/resized.txt"), "dry run changes nothing")
+	s.Equal("extra", s.readFile("target/extra.txt"))
+}
+
+func (s *syncTestSuite) TestSync() {
+	actions, err := Sync(s.src, s.target, Options{Delete: true, Parallel: 3})
+	s.NoError(err)
+	s.Len(actions, 4)
+	for _, action := range actions {
+		s.NoError(action.Err)
+	}
+	s.Equal("longer content", s.readFile("target/dir/resized.txt"))
+	s.Equal("missing", s.readFile("target/missing.txt"))
+	s.Equal("new!", s.readFile("target/newer.txt"))
+	_, err = os.Stat(filepath.Join(s.tmpDir, "target/extra.txt"))
+	s.True(os.IsNotExist(err))
+
+	actions, err = Plan(s.src, s.target, Options{Delete: true})
+	s.NoError(err)
+	s.Empty(actions, "nothing left to do")
+}
+
+func TestSync(t *testing.T) {
+	suite.Run(t, new(syncTestSuite))
+}